@@ -10,9 +10,11 @@ import (
 var Cfg AppConfig
 
 type AppConfig struct {
-	MySQL  MySQLConfig  `mapstructure:"mysql"`
-	Server ServerConfig `mapstructure:"server"`
-	Jwt    JwtConfig    `mapstructure:"jwt"`
+	MySQL   MySQLConfig   `mapstructure:"mysql"`
+	Server  ServerConfig  `mapstructure:"server"`
+	Jwt     JwtConfig     `mapstructure:"jwt"`
+	Export  ExportConfig  `mapstructure:"export"`
+	Storage StorageConfig `mapstructure:"storage"`
 }
 
 type MySQLConfig struct {
@@ -40,6 +42,36 @@ type JwtConfig struct {
 	Expire int    `mapstructure:"expire"`
 }
 
+type ExportConfig struct {
+	Dir            string `mapstructure:"dir"`             // 后台导出文件的存放目录
+	AsyncThreshold int64  `mapstructure:"async_threshold"` // 超过该行数的导出转为后台任务
+}
+
+type StorageConfig struct {
+	Driver        string             `mapstructure:"driver"`          // local 或 s3
+	MaxSizeMB     int64              `mapstructure:"max_size_mb"`     // 单个上传文件的大小上限
+	AllowedTypes  []string           `mapstructure:"allowed_types"`   // 允许上传的 MIME 类型
+	SignExpire    int                `mapstructure:"sign_expire"`     // 下载地址有效期，单位：分钟
+	SignSecret    string             `mapstructure:"sign_secret"`     // 本地存储下载地址的签名密钥，为空时使用 JWT 密钥
+	OrphanGraceHr int                `mapstructure:"orphan_grace_hr"` // 上传后超过该小时数仍未被引用的文件视为孤儿文件
+	Local         LocalStorageConfig `mapstructure:"local"`
+	S3            S3StorageConfig    `mapstructure:"s3"`
+}
+
+type LocalStorageConfig struct {
+	Dir     string `mapstructure:"dir"`      // 文件存放目录
+	BaseURL string `mapstructure:"base_url"` // 签名下载地址前缀
+}
+
+type S3StorageConfig struct {
+	Endpoint  string `mapstructure:"endpoint"` // 如 "127.0.0.1:9000"，不含协议
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	Bucket    string `mapstructure:"bucket"`
+	Region    string `mapstructure:"region"`
+	UseSSL    bool   `mapstructure:"use_ssl"`
+}
+
 // Init 初始化配置
 func Init(configName string) {
 	if configName == "" {
@@ -56,6 +88,17 @@ func Init(configName string) {
 		panic(fmt.Errorf("读取配置文件 [%s.yaml] 失败: %w", configName, err))
 	}
 
+	// 可选配置项的默认值
+	viper.SetDefault("export.dir", "./storage/exports")
+	viper.SetDefault("export.async_threshold", 5000)
+	viper.SetDefault("storage.driver", "local")
+	viper.SetDefault("storage.max_size_mb", 10)
+	viper.SetDefault("storage.allowed_types", []string{"image/jpeg", "image/png", "image/gif", "image/webp", "application/pdf"})
+	viper.SetDefault("storage.sign_expire", 60)
+	viper.SetDefault("storage.orphan_grace_hr", 24)
+	viper.SetDefault("storage.local.dir", "./storage/files")
+	viper.SetDefault("storage.local.base_url", "/api/v1/files/raw")
+
 	// 将配置解析到 Cfg 变量
 	if err := viper.Unmarshal(&Cfg); err != nil {
 		panic(fmt.Errorf("解析配置到结构体失败: %w", err))
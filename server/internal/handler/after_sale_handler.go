@@ -0,0 +1,217 @@
+// server/internal/handler/after_sale_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AfterSaleHandler 封装了售后相关的 HTTP 处理函数
+type AfterSaleHandler struct {
+	service service.IAfterSaleService
+}
+
+// NewAfterSaleHandler 创建一个新的 AfterSaleHandler 实例
+func NewAfterSaleHandler(service service.IAfterSaleService) *AfterSaleHandler {
+	return &AfterSaleHandler{service: service}
+}
+
+// Apply 处理买家申请售后的请求
+func (h *AfterSaleHandler) Apply(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.ApplyAfterSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	afterSale, err := h.service.Apply(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, afterSale)
+}
+
+// List 处理列出售后记录的请求，支持 orderId、status 筛选
+func (h *AfterSaleHandler) List(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	var filter model.AfterSaleFilter
+	if v, err := strconv.ParseUint(c.Query("orderId"), 10, 32); err == nil {
+		filter.OrderID = uint(v)
+	}
+	if v, err := strconv.ParseInt(c.Query("status"), 10, 8); err == nil {
+		status := int8(v)
+		filter.Status = &status
+	}
+
+	afterSales, total, err := h.service.List(filter, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  afterSales,
+		"total": total,
+	})
+}
+
+// Get 处理获取售后详情的请求
+func (h *AfterSaleHandler) Get(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	afterSale, err := h.service.Get(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, afterSale)
+}
+
+// Cancel 处理买家撤销售后的请求
+func (h *AfterSaleHandler) Cancel(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	afterSale, err := h.service.Cancel(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, afterSale)
+}
+
+// Approve 处理供应商同意售后的请求
+func (h *AfterSaleHandler) Approve(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.HandleAfterSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	afterSale, err := h.service.Approve(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, afterSale)
+}
+
+// Reject 处理供应商驳回售后的请求
+func (h *AfterSaleHandler) Reject(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.HandleAfterSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	afterSale, err := h.service.Reject(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, afterSale)
+}
+
+// Dispute 处理买家对驳回结果提出申诉的请求
+func (h *AfterSaleHandler) Dispute(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.DisputeAfterSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	afterSale, err := h.service.Dispute(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, afterSale)
+}
+
+// Arbitrate 处理学校仲裁售后申诉的请求
+func (h *AfterSaleHandler) Arbitrate(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.ArbitrateAfterSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	afterSale, err := h.service.Arbitrate(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, afterSale)
+}
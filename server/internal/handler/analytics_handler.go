@@ -0,0 +1,163 @@
+// server/internal/handler/analytics_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AnalyticsHandler 封装了采购统计相关的 HTTP 处理函数
+type AnalyticsHandler struct {
+	service service.IAnalyticsService
+}
+
+// NewAnalyticsHandler 创建一个新的 AnalyticsHandler 实例
+func NewAnalyticsHandler(service service.IAnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service}
+}
+
+// Summary 处理查询采购概览的请求
+func (h *AnalyticsHandler) Summary(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	query, ok := parseAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.service.Summary(query, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// Trend 处理查询采购金额趋势的请求，granularity 为 day、week 或 month，默认 day
+func (h *AnalyticsHandler) Trend(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	query, ok := parseAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	points, err := h.service.Trend(query, c.DefaultQuery("granularity", model.AnalyticsByDay), claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": points})
+}
+
+// Breakdown 处理查询采购金额分布的请求，groupBy 为 category、supplier 或 canteen，默认 category
+func (h *AnalyticsHandler) Breakdown(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	query, ok := parseAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	groups, err := h.service.Breakdown(query, c.DefaultQuery("groupBy", model.AnalyticsGroupCategory), claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": groups})
+}
+
+// TopProducts 处理查询采购金额最高商品的请求，limit 默认 10，最多 50
+func (h *AnalyticsHandler) TopProducts(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	query, ok := parseAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	products, err := h.service.TopProducts(query, parseAnalyticsLimit(c), claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": products})
+}
+
+// PricesPaid 处理查询实际均价与指导价对比的请求，limit 默认 10，最多 50
+func (h *AnalyticsHandler) PricesPaid(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	query, ok := parseAnalyticsQuery(c)
+	if !ok {
+		return
+	}
+
+	prices, err := h.service.PricesPaid(query, parseAnalyticsLimit(c), claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": prices})
+}
+
+// parseAnalyticsQuery 解析 startDate、endDate、merchantId、supplierId 查询参数，失败时直接写出 400 响应
+func parseAnalyticsQuery(c *gin.Context) (model.AnalyticsQuery, bool) {
+	var query model.AnalyticsQuery
+	if v := c.Query("startDate"); v != "" {
+		start, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的开始日期"})
+			return query, false
+		}
+		query.StartDate = &start
+	}
+	if v := c.Query("endDate"); v != "" {
+		end, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的结束日期"})
+			return query, false
+		}
+		query.EndDate = &end
+	}
+	if v, err := strconv.ParseUint(c.Query("merchantId"), 10, 32); err == nil {
+		query.MerchantID = uint(v)
+	}
+	if v, err := strconv.ParseUint(c.Query("supplierId"), 10, 32); err == nil {
+		query.SupplierID = uint(v)
+	}
+	return query, true
+}
+
+// parseAnalyticsLimit 解析排行榜的条数
+func parseAnalyticsLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 {
+		return 10
+	}
+	if limit > 50 {
+		return 50
+	}
+	return limit
+}
@@ -0,0 +1,134 @@
+// server/internal/handler/batch_report_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BatchReportHandler 封装了报价批次检测报告相关的 HTTP 处理函数
+type BatchReportHandler struct {
+	service service.IBatchReportService
+}
+
+// NewBatchReportHandler 创建一个新的 BatchReportHandler 实例
+func NewBatchReportHandler(service service.IBatchReportService) *BatchReportHandler {
+	return &BatchReportHandler{service: service}
+}
+
+// Upload 处理供应商上传检测报告的请求
+func (h *BatchReportHandler) Upload(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UploadBatchReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reports, err := h.service.Upload(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "检测报告上传成功",
+		"reports": reports,
+	})
+}
+
+// Validate 处理校验检测报告的请求，只返回校验结果不保存
+func (h *BatchReportHandler) Validate(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.ValidateBatchReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.Validate(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "检测报告校验通过",
+		"report":  report,
+	})
+}
+
+// Remove 处理删除检测报告的请求
+func (h *BatchReportHandler) Remove(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Remove(id, c.Param("batchNo"), claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "检测报告删除成功"})
+}
+
+// QuoteReports 处理供应商查看报价检测报告的请求
+func (h *BatchReportHandler) QuoteReports(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reports, err := h.service.QuoteReports(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// ProductReports 处理查看商品各报价检测报告的请求
+func (h *BatchReportHandler) ProductReports(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	reports, err := h.service.ProductReports(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": reports})
+}
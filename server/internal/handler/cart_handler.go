@@ -0,0 +1,141 @@
+// server/internal/handler/cart_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CartHandler 封装了买家购物车相关的 HTTP 处理函数
+type CartHandler struct {
+	service service.ICartService
+}
+
+// NewCartHandler 创建一个新的 CartHandler 实例
+func NewCartHandler(service service.ICartService) *CartHandler {
+	return &CartHandler{service: service}
+}
+
+// Get 处理获取购物车的请求，按供应商分组返回
+func (h *CartHandler) Get(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	cart, err := h.service.GetCart(claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
+
+// Add 处理加入购物车的请求
+func (h *CartHandler) Add(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.service.AddItem(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已加入购物车",
+		"item":    item,
+	})
+}
+
+// Update 处理修改购物车条目数量的请求
+func (h *CartHandler) Update(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item, err := h.service.UpdateItem(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "购物车更新成功",
+		"item":    item,
+	})
+}
+
+// Remove 处理移除购物车条目的请求
+func (h *CartHandler) Remove(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.RemoveItem(id, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已从购物车移除"})
+}
+
+// Clear 处理清空购物车的请求
+func (h *CartHandler) Clear(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.ClearCart(claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "购物车已清空"})
+}
+
+// ConfirmPrices 处理确认价格变化的请求，返回确认后的购物车
+func (h *CartHandler) ConfirmPrices(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	cart, err := h.service.ConfirmPrices(claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, cart)
+}
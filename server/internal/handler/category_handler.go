@@ -0,0 +1,146 @@
+// server/internal/handler/category_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryHandler 封装了商品分类相关的 HTTP 处理函数
+type CategoryHandler struct {
+	service service.ICategoryService
+}
+
+// NewCategoryHandler 创建一个新的 CategoryHandler 实例
+func NewCategoryHandler(service service.ICategoryService) *CategoryHandler {
+	return &CategoryHandler{service: service}
+}
+
+// Tree 处理获取完整分类树的请求，供商品录入时的分类选择器使用
+func (h *CategoryHandler) Tree(c *gin.Context) {
+	tree, err := h.service.GetTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": tree})
+}
+
+// Create 处理创建分类的请求
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req model.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category, err := h.service.CreateCategory(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "分类创建成功",
+		"category": category,
+	})
+}
+
+// Update 处理修改分类名称和图标的请求
+func (h *CategoryHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateCategory(id, &req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "分类更新成功"})
+}
+
+// ReportPolicy 处理设置分类检测报告要求的请求
+func (h *CategoryHandler) ReportPolicy(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateReportPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetReportPolicy(id, &req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "检测报告要求更新成功"})
+}
+
+// Move 处理移动分类的请求
+func (h *CategoryHandler) Move(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.MoveCategory(id, &req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "分类移动成功"})
+}
+
+// Sort 处理批量调整分类排序的请求
+func (h *CategoryHandler) Sort(c *gin.Context) {
+	var req model.SortCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SortCategories(&req); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "排序更新成功"})
+}
+
+// Delete 处理删除分类的请求
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCategory(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "分类删除成功"})
+}
@@ -0,0 +1,87 @@
+// server/internal/handler/comparison_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ComparisonHandler 封装了多供应商比价相关的 HTTP 处理函数
+type ComparisonHandler struct {
+	service service.IComparisonService
+}
+
+// NewComparisonHandler 创建一个新的 ComparisonHandler 实例
+func NewComparisonHandler(service service.IComparisonService) *ComparisonHandler {
+	return &ComparisonHandler{service: service}
+}
+
+// Product 处理单个商品比价的请求
+func (h *ComparisonHandler) Product(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	comparison, err := h.service.CompareProduct(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// Category 处理按分类比价的请求
+func (h *ComparisonHandler) Category(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	comparisons, total, err := h.service.CompareCategory(id, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  comparisons,
+		"total": total,
+	})
+}
+
+// Basket 处理按采购清单挑选最低价报价的请求
+func (h *ComparisonHandler) Basket(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.BasketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.PickBasket(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,223 @@
+// server/internal/handler/delivery_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeliveryHandler 封装了供应商配送调度相关的 HTTP 处理函数
+type DeliveryHandler struct {
+	service service.IDeliveryService
+}
+
+// NewDeliveryHandler 创建一个新的 DeliveryHandler 实例
+func NewDeliveryHandler(service service.IDeliveryService) *DeliveryHandler {
+	return &DeliveryHandler{service: service}
+}
+
+// List 处理列出配送趟次的请求，支持 deliveryDate、driverId、status 筛选
+func (h *DeliveryHandler) List(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	var filter model.DeliveryRunFilter
+	if v := c.Query("deliveryDate"); v != "" {
+		date, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的送达日期"})
+			return
+		}
+		filter.DeliveryDate = &date
+	}
+	if v, err := strconv.ParseUint(c.Query("driverId"), 10, 32); err == nil {
+		filter.DriverID = uint(v)
+	}
+	if v, err := strconv.ParseInt(c.Query("status"), 10, 8); err == nil {
+		status := int8(v)
+		filter.Status = &status
+	}
+
+	runs, total, err := h.service.ListRuns(filter, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  runs,
+		"total": total,
+	})
+}
+
+// Create 处理排车的请求
+func (h *DeliveryHandler) Create(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.CreateDeliveryRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := h.service.CreateRun(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// Get 处理获取配送趟次详情的请求
+func (h *DeliveryHandler) Get(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.GetRun(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// AssignDriver 处理更换司机和车辆的请求
+func (h *DeliveryHandler) AssignDriver(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.AssignDriverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := h.service.AssignDriver(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// Cancel 处理取消配送趟次的请求
+func (h *DeliveryHandler) Cancel(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.CancelRun(id, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "配送趟次已取消"})
+}
+
+// Depart 处理登记趟次出发的请求
+func (h *DeliveryHandler) Depart(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.Depart(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// Arrive 处理登记订单送达的请求
+func (h *DeliveryHandler) Arrive(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	orderID, ok := parseIDParam(c, "orderId")
+	if !ok {
+		return
+	}
+
+	order, err := h.service.ArriveOrder(id, orderID, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "订单已送达",
+		"order":   order,
+	})
+}
+
+// DriverTasks 处理查询司机配送任务的请求，deliveryDate 默认为今天
+func (h *DeliveryHandler) DriverTasks(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	driverID, ok := parseIDParam(c, "driverId")
+	if !ok {
+		return
+	}
+	date := time.Now()
+	if v := c.Query("deliveryDate"); v != "" {
+		d, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的送达日期"})
+			return
+		}
+		date = d
+	}
+
+	tasks, err := h.service.DriverTasks(driverID, date, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": tasks})
+}
@@ -0,0 +1,148 @@
+// server/internal/handler/export_handler.go
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"server/internal/model"
+	"server/internal/service"
+	"server/pkg/sheet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler 封装了数据导出相关的 HTTP 处理函数
+type ExportHandler struct {
+	service service.IExportService
+}
+
+// NewExportHandler 创建一个新的 ExportHandler 实例
+func NewExportHandler(service service.IExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// Columns 处理获取可导出列的请求
+func (h *ExportHandler) Columns(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	columns, err := h.service.Columns(c.Param("source"), c.Query("lang"), claims)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": columns})
+}
+
+// Export 处理导出请求。
+// 筛选参数与对应的列表接口一致，另支持 format(xlsx/csv)、columns(逗号分隔的列)、lang(zh/en)、async(true 强制后台导出)。
+// 数据量较小时直接以文件流返回，超过阈值时创建后台任务并返回下载地址。
+func (h *ExportHandler) Export(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	source := c.Param("source")
+	query := c.Request.URL.Query()
+
+	async, err := h.service.ShouldRunAsync(source, query, claims)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if async {
+		job, err := h.service.CreateJob(source, query, claims)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, model.ExportJobResponse{Job: job, DownloadURL: exportDownloadURL(job.ID)})
+		return
+	}
+
+	// 参数已在 ShouldRunAsync 中校验，这里的格式解析不会失败
+	format, _ := sheet.ParseFormat(query.Get("format"))
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, service.ExportFileName(source, format)))
+	c.Status(http.StatusOK)
+	if _, err := h.service.Export(source, query, claims, c.Writer); err != nil {
+		// 响应头已经写出，只能中断连接
+		_ = c.Error(err)
+		c.Abort()
+	}
+}
+
+// ListJobs 处理列出当前用户导出任务的请求
+func (h *ExportHandler) ListJobs(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	jobs, total, err := h.service.ListJobs(claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  jobs,
+		"total": total,
+	})
+}
+
+// GetJob 处理查询导出任务状态的请求
+func (h *ExportHandler) GetJob(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetJob(id, claims)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.ExportJobResponse{Job: job, DownloadURL: exportDownloadURL(job.ID)})
+}
+
+// DownloadJob 处理下载后台导出文件的请求
+func (h *ExportHandler) DownloadJob(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetJob(id, claims)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if job.Status != model.ExportJobDone {
+		c.JSON(http.StatusConflict, gin.H{"error": "导出任务尚未完成"})
+		return
+	}
+
+	c.FileAttachment(job.FilePath, job.FileName)
+}
+
+// exportDownloadURL 返回后台导出任务的下载地址
+func exportDownloadURL(jobID uint) string {
+	return fmt.Sprintf("/api/v1/export-jobs/%d/download", jobID)
+}
@@ -0,0 +1,119 @@
+// server/internal/handler/file_handler.go
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler 封装了文件上传与下载相关的 HTTP 处理函数
+type FileHandler struct {
+	service service.IFileService
+}
+
+// NewFileHandler 创建一个新的 FileHandler 实例
+func NewFileHandler(service service.IFileService) *FileHandler {
+	return &FileHandler{service: service}
+}
+
+// Upload 处理上传文件的请求，文件放在 multipart 的 file 字段中，purpose 字段为 receipt 时保留照片拍摄时间
+func (h *FileHandler) Upload(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请选择要上传的文件"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "读取上传文件失败"})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.Upload(file, fileHeader.Filename, c.PostForm("purpose"), claims)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SignURL 处理获取单个文件下载地址的请求
+func (h *FileHandler) SignURL(c *gin.Context) {
+	key := c.Query("key")
+	if key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "缺少文件 key"})
+		return
+	}
+
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	urls, err := h.service.SignedURLsFor([]string{key}, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": urls[key]})
+}
+
+// SignURLs 处理批量获取文件下载地址的请求
+func (h *FileHandler) SignURLs(c *gin.Context) {
+	var req model.SignFileURLsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	urls, err := h.service.SignedURLsFor(req.Keys, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"urls": urls})
+}
+
+// Raw 处理本地存储的签名下载请求，不需要登录，凭地址中的签名访问
+func (h *FileHandler) Raw(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	path, err := h.service.LocalPath(key, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 文件按内容哈希命名，内容不会变化，可以长期缓存
+	c.Header("Cache-Control", "private, max-age=86400")
+	c.File(path)
+}
+
+// Cleanup 处理立即清理孤儿文件的请求
+func (h *FileHandler) Cleanup(c *gin.Context) {
+	result, err := h.service.CleanupOrphans()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
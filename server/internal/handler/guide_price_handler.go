@@ -0,0 +1,173 @@
+// server/internal/handler/guide_price_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GuidePriceHandler 封装了学校指导价和超价报价复核相关的 HTTP 处理函数
+type GuidePriceHandler struct {
+	service service.IGuidePriceService
+}
+
+// NewGuidePriceHandler 创建一个新的 GuidePriceHandler 实例
+func NewGuidePriceHandler(service service.IGuidePriceService) *GuidePriceHandler {
+	return &GuidePriceHandler{service: service}
+}
+
+// List 处理列出本校指导价的请求，支持 keyword 筛选
+func (h *GuidePriceHandler) List(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	guides, total, err := h.service.ListGuidePrices(c.Query("keyword"), claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  guides,
+		"total": total,
+	})
+}
+
+// Set 处理设置商品指导价的请求
+func (h *GuidePriceHandler) Set(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	productID, ok := parseIDParam(c, "productId")
+	if !ok {
+		return
+	}
+
+	var req model.SetGuidePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	guide, err := h.service.SetGuidePrice(productID, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "指导价设置成功",
+		"guide":   guide,
+	})
+}
+
+// Delete 处理删除商品指导价的请求
+func (h *GuidePriceHandler) Delete(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	productID, ok := parseIDParam(c, "productId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteGuidePrice(productID, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "指导价已删除"})
+}
+
+// Report 处理查询价格超出指导价区间报价的请求
+func (h *GuidePriceHandler) Report(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	items, total, err := h.service.OutOfBandReport(claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  items,
+		"total": total,
+	})
+}
+
+// ReviewQueue 处理获取待复核超价报价队列的请求
+func (h *GuidePriceHandler) ReviewQueue(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	items, total, err := h.service.ListReviewQueue(claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  items,
+		"total": total,
+	})
+}
+
+// ApproveQuote 处理复核通过超价报价的请求
+func (h *GuidePriceHandler) ApproveQuote(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.ApproveQuote(id, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "复核通过"})
+}
+
+// RejectQuote 处理驳回超价报价的请求
+func (h *GuidePriceHandler) RejectQuote(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.RejectQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写驳回原因"})
+		return
+	}
+
+	if err := h.service.RejectQuote(id, &req, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已驳回，报价已停用"})
+}
@@ -0,0 +1,50 @@
+// server/internal/handler/helper.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/router/middleware"
+	"server/pkg/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getClaims 从 Gin Context 获取认证中间件写入的用户信息，失败时直接写出 403 响应
+func getClaims(c *gin.Context) (*jwt.CustomClaims, bool) {
+	claims, exists := c.Get(middleware.ContextUserClaimsKey)
+	if !exists {
+		c.JSON(http.StatusForbidden, gin.H{"error": "无法获取用户信息，禁止访问"})
+		return nil, false
+	}
+	userClaims, ok := claims.(*jwt.CustomClaims)
+	if !ok || userClaims == nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "用户信息格式错误，禁止访问"})
+		return nil, false
+	}
+	return userClaims, true
+}
+
+// parseIDParam 解析路径参数中的ID，失败时直接写出 400 响应
+func parseIDParam(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// parsePage 解析分页参数，非法值回退为默认值
+func parsePage(c *gin.Context, defaultPageSize int) (int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.DefaultQuery("pageSize", strconv.Itoa(defaultPageSize)))
+	if err != nil || pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	return page, pageSize
+}
@@ -0,0 +1,100 @@
+// server/internal/handler/import_handler.go
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"server/internal/service"
+	"server/pkg/sheet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportFileSize 导入文件的大小上限（10MB）
+const maxImportFileSize = 10 << 20
+
+// ImportHandler 封装了批量导入相关的 HTTP 处理函数
+type ImportHandler struct {
+	service service.IImportService
+}
+
+// NewImportHandler 创建一个新的 ImportHandler 实例
+func NewImportHandler(service service.IImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// ImportSchools 处理批量导入学校的请求
+func (h *ImportHandler) ImportSchools(c *gin.Context) {
+	h.doImport(c, service.ImportTypeSchools)
+}
+
+// ImportSuppliers 处理批量导入供应商的请求
+func (h *ImportHandler) ImportSuppliers(c *gin.Context) {
+	h.doImport(c, service.ImportTypeSuppliers)
+}
+
+// ImportAccounts 处理批量导入子账号的请求
+func (h *ImportHandler) ImportAccounts(c *gin.Context) {
+	h.doImport(c, service.ImportTypeAccounts)
+}
+
+// doImport 读取上传的 file 字段并调用导入服务，dryRun=true 时只校验不入库
+func (h *ImportHandler) doImport(c *gin.Context, importType string) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请上传导入文件"})
+		return
+	}
+	if fileHeader.Size > maxImportFileSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "导入文件不能超过10MB"})
+		return
+	}
+	format, err := sheet.FormatOf(fileHeader.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "读取上传文件失败"})
+		return
+	}
+	defer file.Close()
+
+	dryRun := c.Query("dryRun") == "true"
+	result, err := h.service.Import(importType, file, format, dryRun, claims)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DownloadTemplate 处理下载导入模板的请求
+func (h *ImportHandler) DownloadTemplate(c *gin.Context) {
+	importType := c.Param("type")
+	format, err := sheet.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 模板很小，先写入缓冲区，避免出错时响应头已被写为文件下载
+	var buf bytes.Buffer
+	if err := h.service.WriteTemplate(importType, &buf, format); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-template.%s"`, importType, format))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
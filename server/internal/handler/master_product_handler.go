@@ -0,0 +1,183 @@
+// server/internal/handler/master_product_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MasterProductHandler 封装了平台标准商品库相关的 HTTP 处理函数
+type MasterProductHandler struct {
+	service service.IMasterProductService
+}
+
+// NewMasterProductHandler 创建一个新的 MasterProductHandler 实例
+func NewMasterProductHandler(service service.IMasterProductService) *MasterProductHandler {
+	return &MasterProductHandler{service: service}
+}
+
+// List 处理列出标准商品的请求，支持 categoryId、isEnabled、keyword 筛选
+func (h *MasterProductHandler) List(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	filter := model.MasterProductListFilter{Keyword: c.Query("keyword")}
+	if v, err := strconv.ParseUint(c.Query("categoryId"), 10, 32); err == nil {
+		filter.CategoryID = uint(v)
+	}
+	if v, err := strconv.ParseBool(c.Query("isEnabled")); err == nil {
+		filter.IsEnabled = &v
+	}
+
+	masters, total, err := h.service.ListMasters(filter, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  masters,
+		"total": total,
+	})
+}
+
+// Create 处理新建标准商品的请求
+func (h *MasterProductHandler) Create(c *gin.Context) {
+	var req model.MasterProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	master, err := h.service.CreateMaster(&req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "标准商品创建成功",
+		"master":  master,
+	})
+}
+
+// Update 处理修改标准商品的请求，修改会同步到已下发的学校副本
+func (h *MasterProductHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.MasterProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.UpdateMaster(id, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "标准商品更新成功",
+		"master":  result.Master,
+		"synced":  result.Synced,
+	})
+}
+
+// UpdateStatus 处理启用/停用标准商品的请求
+func (h *MasterProductHandler) UpdateStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateMasterStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetMasterEnabled(id, *req.IsEnabled); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "状态更新成功"})
+}
+
+// Copies 处理查询标准商品已下发副本的请求
+func (h *MasterProductHandler) Copies(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	copies, err := h.service.ListCopies(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": copies})
+}
+
+// Push 处理平台向学校下发标准商品的请求
+func (h *MasterProductHandler) Push(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.PushMasterProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Push(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "下发成功",
+		"result":  result,
+	})
+}
+
+// Subscribe 处理学校订阅标准商品的请求
+func (h *MasterProductHandler) Subscribe(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.SubscribeMasterProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Subscribe(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "订阅成功",
+		"result":  result,
+	})
+}
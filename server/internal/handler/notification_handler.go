@@ -0,0 +1,75 @@
+// server/internal/handler/notification_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler 封装了站内通知相关的 HTTP 处理函数
+type NotificationHandler struct {
+	service service.INotificationService
+}
+
+// NewNotificationHandler 创建一个新的 NotificationHandler 实例
+func NewNotificationHandler(service service.INotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// List 处理列出当前组织通知的请求，unread=true 时只列出未读通知
+func (h *NotificationHandler) List(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	list, total, unread, err := h.service.List(claims, c.Query("unread") == "true", page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":   list,
+		"total":  total,
+		"unread": unread,
+	})
+}
+
+// MarkRead 处理将一条通知标记为已读的请求
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.MarkRead(id, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已标记为已读"})
+}
+
+// MarkAllRead 处理将全部通知标记为已读的请求
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.MarkAllRead(claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已全部标记为已读"})
+}
@@ -0,0 +1,225 @@
+// server/internal/handler/order_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrderHandler 封装了订单相关的 HTTP 处理函数
+type OrderHandler struct {
+	service service.IOrderService
+}
+
+// NewOrderHandler 创建一个新的 OrderHandler 实例
+func NewOrderHandler(service service.IOrderService) *OrderHandler {
+	return &OrderHandler{service: service}
+}
+
+// Checkout 处理购物车结算的请求，重复提交同一结算令牌时返回首次结算的结果
+func (h *OrderHandler) Checkout(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.service.Checkout(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusCreated
+	if result.Duplicate {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
+
+// Get 处理获取订单详情的请求
+func (h *OrderHandler) Get(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.GetOrder(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// UpdateStatus 处理变更订单状态的请求，状态变更须符合订单状态机
+func (h *OrderHandler) UpdateStatus(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.service.ChangeStatus(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "订单状态更新成功",
+		"order":   order,
+	})
+}
+
+// Inbox 处理供应商订单收件箱的请求，支持 status、deliveryDate、orderNo 筛选
+func (h *OrderHandler) Inbox(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	filter := model.OrderListFilter{OrderNo: c.Query("orderNo")}
+	if v := c.Query("status"); v != "" {
+		status, err := strconv.ParseInt(v, 10, 8)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的订单状态"})
+			return
+		}
+		st := int8(status)
+		filter.Status = &st
+	}
+	if v := c.Query("deliveryDate"); v != "" {
+		date, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的送达日期"})
+			return
+		}
+		filter.DeliveryDate = &date
+	}
+
+	orders, total, counts, err := h.service.Inbox(filter, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":         orders,
+		"total":        total,
+		"statusCounts": counts,
+	})
+}
+
+// Accept 处理供应商接单的请求
+func (h *OrderHandler) Accept(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.AcceptOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.service.Accept(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "接单成功",
+		"order":   order,
+	})
+}
+
+// Reject 处理供应商拒单的请求
+func (h *OrderHandler) Reject(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.RejectOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.service.Reject(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "已拒单",
+		"order":   order,
+	})
+}
+
+// AdjustItems 处理标记订单明细缺货/少发的请求
+func (h *OrderHandler) AdjustItems(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.AdjustOrderItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.service.AdjustItems(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "订单明细已调整",
+		"order":   order,
+	})
+}
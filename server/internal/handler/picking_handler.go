@@ -0,0 +1,211 @@
+// server/internal/handler/picking_handler.go
+package handler
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"server/internal/model"
+	"server/internal/service"
+	"server/pkg/sheet"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PickingHandler 封装了供应商分拣相关的 HTTP 处理函数
+type PickingHandler struct {
+	service service.IPickingService
+}
+
+// NewPickingHandler 创建一个新的 PickingHandler 实例
+func NewPickingHandler(service service.IPickingService) *PickingHandler {
+	return &PickingHandler{service: service}
+}
+
+// List 处理列出分拣单的请求，支持 deliveryDate、status 筛选
+func (h *PickingHandler) List(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	var filter model.PickListFilter
+	if v := c.Query("deliveryDate"); v != "" {
+		date, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的送达日期"})
+			return
+		}
+		filter.DeliveryDate = &date
+	}
+	if v, err := strconv.ParseInt(c.Query("status"), 10, 8); err == nil {
+		status := int8(v)
+		filter.Status = &status
+	}
+
+	lists, total, err := h.service.ListPickLists(filter, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  lists,
+		"total": total,
+	})
+}
+
+// Create 处理生成分拣单的请求
+func (h *PickingHandler) Create(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.CreatePickListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := h.service.CreatePickList(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, detail)
+}
+
+// Get 处理获取分拣单详情的请求
+func (h *PickingHandler) Get(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.GetPickList(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// AssignPicker 处理指派分拣员的请求
+func (h *PickingHandler) AssignPicker(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.AssignPickerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := h.service.AssignPicker(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// PickLine 处理登记分拣行实拣数量的请求
+func (h *PickingHandler) PickLine(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	lineID, ok := parseIDParam(c, "lineId")
+	if !ok {
+		return
+	}
+
+	var req model.PickLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	line, err := h.service.PickLine(id, lineID, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "分拣数量已登记",
+		"line":    line,
+	})
+}
+
+// Complete 处理完成分拣的请求
+func (h *PickingHandler) Complete(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	list, err := h.service.CompletePickList(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "分拣已完成",
+		"pickList": list,
+	})
+}
+
+// Sheet 处理下载可打印分拣单的请求，format 支持 xlsx（默认）和 csv
+func (h *PickingHandler) Sheet(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	format, err := sheet.ParseFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 分拣单不大，先写入缓冲区，避免出错时响应头已被写为文件下载
+	var buf bytes.Buffer
+	if err := h.service.WriteSheet(id, claims, &buf, format); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="picking-%d.%s"`, id, format))
+	c.Data(http.StatusOK, format.ContentType(), buf.Bytes())
+}
@@ -0,0 +1,408 @@
+// server/internal/handler/product_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProductHandler 封装了商品(SPU)相关的 HTTP 处理函数
+type ProductHandler struct {
+	service       service.IProductService
+	searchService service.IProductSearchService
+}
+
+// NewProductHandler 创建一个新的 ProductHandler 实例
+func NewProductHandler(service service.IProductService, searchService service.IProductSearchService) *ProductHandler {
+	return &ProductHandler{service: service, searchService: searchService}
+}
+
+// Submit 处理供应商提交新商品的请求
+func (h *ProductHandler) Submit(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.SubmitProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := h.service.SubmitProduct(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 疑似重复商品只作为提示，查询失败不影响提交结果
+	duplicates, err := h.service.FindDuplicates(product.ID, claims)
+	if err != nil {
+		duplicates = []model.ProductDuplicate{}
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "商品已提交，等待学校审核",
+		"product":    product,
+		"duplicates": duplicates,
+	})
+}
+
+// Update 处理供应商修改商品的请求，被驳回的商品修改后自动重新提交审核
+func (h *ProductHandler) Update(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.SubmitProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := h.service.UpdateProduct(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "商品更新成功",
+		"product": product,
+	})
+}
+
+// UpdateCerts 处理上传商品三证资质的请求
+func (h *ProductHandler) UpdateCerts(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateProductCertsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateCerts(id, &req, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "资质更新成功"})
+}
+
+// GetByID 处理获取商品详情（含审核历史）的请求
+func (h *ProductHandler) GetByID(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.GetProduct(id, claims)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// List 处理列出商品的请求，支持 categoryId、auditStatus、keyword、mine 筛选
+func (h *ProductHandler) List(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	filter := parseProductFilter(c)
+	products, total, err := h.service.ListProducts(filter, c.Query("mine") == "true", claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  products,
+		"total": total,
+	})
+}
+
+// AuditQueue 处理学校获取待审商品队列的请求
+func (h *ProductHandler) AuditQueue(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	filter := parseProductFilter(c)
+	pending := model.ProductAuditPending
+	filter.AuditStatus = &pending
+	products, total, err := h.service.ListProducts(filter, false, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  products,
+		"total": total,
+	})
+}
+
+// Approve 处理审核通过商品的请求
+func (h *ProductHandler) Approve(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.ApproveProduct(id, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "审核通过"})
+}
+
+// Reject 处理驳回商品的请求
+func (h *ProductHandler) Reject(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.RejectProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请填写驳回原因"})
+		return
+	}
+
+	if err := h.service.RejectProduct(id, &req, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "已驳回"})
+}
+
+// Duplicates 处理查询疑似重复商品的请求
+func (h *ProductHandler) Duplicates(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	duplicates, err := h.service.FindDuplicates(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": duplicates})
+}
+
+// Merge 处理学校将重复商品合并到保留商品的请求
+func (h *ProductHandler) Merge(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.MergeProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.MergeProduct(id, &req, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "商品合并成功"})
+}
+
+// ListOnShelf 处理学校上架商品的请求
+func (h *ProductHandler) ListOnShelf(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.ListProduct(id, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "商品已上架"})
+}
+
+// Unlist 处理学校下架单个商品的请求
+func (h *ProductHandler) Unlist(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if _, err := h.service.UnlistProducts([]uint{id}, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "商品已下架"})
+}
+
+// BatchUnlist 处理学校批量下架商品的请求
+func (h *ProductHandler) BatchUnlist(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.BatchProductIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	count, err := h.service.UnlistProducts(req.IDs, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "批量下架成功",
+		"count":   count,
+	})
+}
+
+// Listed 处理食堂查询本校已上架商品的请求，支持 categoryId、keyword 筛选
+func (h *ProductHandler) Listed(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 20)
+
+	filter := model.ProductListFilter{Keyword: c.Query("keyword")}
+	if v, err := strconv.ParseUint(c.Query("categoryId"), 10, 32); err == nil {
+		filter.CategoryID = uint(v)
+	}
+	products, total, err := h.service.ListListedProducts(filter, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  products,
+		"total": total,
+	})
+}
+
+// Search 处理商品搜索的请求，q 支持中文、拼音全拼和首字母，并支持 categoryId、auditStatus、isListed 筛选
+func (h *ProductHandler) Search(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 20)
+
+	filter := parseProductFilter(c)
+	filter.Keyword = c.Query("q")
+	if filter.Keyword == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "请输入搜索关键词"})
+		return
+	}
+
+	products, total, err := h.searchService.Search(filter, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  products,
+		"total": total,
+	})
+}
+
+// RebuildIndex 处理全量重建商品搜索索引的请求
+func (h *ProductHandler) RebuildIndex(c *gin.Context) {
+	count, err := h.searchService.Rebuild()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "搜索索引重建完成",
+		"count":   count,
+	})
+}
+
+// parseProductFilter 从查询参数中解析商品筛选条件
+func parseProductFilter(c *gin.Context) model.ProductListFilter {
+	filter := model.ProductListFilter{Keyword: c.Query("keyword")}
+	if v, err := strconv.ParseUint(c.Query("categoryId"), 10, 32); err == nil {
+		filter.CategoryID = uint(v)
+	}
+	if v, err := strconv.ParseUint(c.Query("schoolId"), 10, 32); err == nil {
+		filter.SchoolID = uint(v)
+	}
+	if v, err := strconv.ParseInt(c.Query("auditStatus"), 10, 8); err == nil {
+		status := int8(v)
+		filter.AuditStatus = &status
+	}
+	if v, err := strconv.ParseBool(c.Query("isListed")); err == nil {
+		filter.IsListed = &v
+	}
+	return filter
+}
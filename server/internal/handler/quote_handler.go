@@ -0,0 +1,174 @@
+// server/internal/handler/quote_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuoteHandler 封装了供应商报价(SKU)相关的 HTTP 处理函数
+type QuoteHandler struct {
+	service service.IQuoteService
+}
+
+// NewQuoteHandler 创建一个新的 QuoteHandler 实例
+func NewQuoteHandler(service service.IQuoteService) *QuoteHandler {
+	return &QuoteHandler{service: service}
+}
+
+// Create 处理供应商为商品报价的请求
+func (h *QuoteHandler) Create(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.CreateQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.service.CreateQuote(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	message := "报价成功"
+	if quote.ReviewStatus == model.QuoteReviewPending {
+		message = "报价超出学校指导价，需等待学校复核后才能供货"
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": message,
+		"quote":   quote,
+	})
+}
+
+// UpdatePrice 处理供应商调整报价的请求，可指定生效时间进行预约调价
+func (h *QuoteHandler) UpdatePrice(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateQuotePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.service.UpdatePrice(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	message := "报价已更新"
+	if req.EffectiveFrom != nil && req.EffectiveFrom.After(time.Now()) {
+		message = "预约调价已设置，将在生效时间自动更新"
+	} else if quote.ReviewStatus == model.QuoteReviewPending {
+		message = "报价超出学校指导价，需等待学校复核后才能供货"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"quote":   quote,
+	})
+}
+
+// UpdateStatus 处理供应商启用/停用报价的请求
+func (h *QuoteHandler) UpdateStatus(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateQuoteStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetEnabled(id, *req.IsEnabled, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "报价状态已更新"})
+}
+
+// ListMine 处理供应商查看自己报价列表的请求，支持 keyword 筛选
+func (h *QuoteHandler) ListMine(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	quotes, total, err := h.service.ListMyQuotes(c.Query("keyword"), claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  quotes,
+		"total": total,
+	})
+}
+
+// PriceHistory 处理查询商品历史价格的请求，支持 supplierId、startDate、endDate 筛选
+func (h *QuoteHandler) PriceHistory(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	filter := model.PriceHistoryFilter{ProductID: id}
+	if v, err := strconv.ParseUint(c.Query("supplierId"), 10, 32); err == nil {
+		filter.SupplierID = uint(v)
+	}
+	if v := c.Query("startDate"); v != "" {
+		start, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的开始日期"})
+			return
+		}
+		filter.StartDate = &start
+	}
+	if v := c.Query("endDate"); v != "" {
+		end, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "无效的结束日期"})
+			return
+		}
+		end = end.AddDate(0, 0, 1) // 结束日期当天也包含在内
+		filter.EndDate = &end
+	}
+
+	histories, err := h.service.ListPriceHistory(filter, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"list": histories})
+}
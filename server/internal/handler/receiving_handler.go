@@ -0,0 +1,67 @@
+// server/internal/handler/receiving_handler.go
+package handler
+
+import (
+	"net/http"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReceivingHandler 封装了买家收货验收相关的 HTTP 处理函数
+type ReceivingHandler struct {
+	service service.IReceivingService
+}
+
+// NewReceivingHandler 创建一个新的 ReceivingHandler 实例
+func NewReceivingHandler(service service.IReceivingService) *ReceivingHandler {
+	return &ReceivingHandler{service: service}
+}
+
+// Receive 处理买家验收订单的请求
+func (h *ReceivingHandler) Receive(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.ReceiveOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	detail, err := h.service.Receive(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+// GetReceipt 处理获取订单验收结果的请求
+func (h *ReceivingHandler) GetReceipt(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	detail, err := h.service.GetReceipt(id, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
@@ -0,0 +1,165 @@
+// server/internal/handler/supplier_staff_handler.go
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"server/internal/model"
+	"server/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SupplierStaffHandler 封装了供应商员工管理相关的 HTTP 处理函数
+type SupplierStaffHandler struct {
+	service service.ISupplierStaffService
+}
+
+// NewSupplierStaffHandler 创建一个新的 SupplierStaffHandler 实例
+func NewSupplierStaffHandler(service service.ISupplierStaffService) *SupplierStaffHandler {
+	return &SupplierStaffHandler{service: service}
+}
+
+// List 处理列出本供应商员工的请求，支持 roleType、keyword 筛选
+func (h *SupplierStaffHandler) List(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	filter := model.SupplierStaffFilter{Keyword: c.Query("keyword")}
+	if v, err := strconv.ParseInt(c.Query("roleType"), 10, 8); err == nil {
+		filter.RoleType = int8(v)
+	}
+
+	staffs, total, err := h.service.ListStaff(filter, claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  staffs,
+		"total": total,
+	})
+}
+
+// Warnings 处理查询健康证过期预警的请求
+func (h *SupplierStaffHandler) Warnings(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePage(c, 10)
+
+	staffs, total, err := h.service.ExpiryWarnings(claims, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"list":  staffs,
+		"total": total,
+	})
+}
+
+// Create 处理新增员工的请求
+func (h *SupplierStaffHandler) Create(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+
+	var req model.SupplierStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	staff, err := h.service.CreateStaff(&req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "员工新增成功",
+		"staff":   staff,
+	})
+}
+
+// Update 处理修改员工信息的请求
+func (h *SupplierStaffHandler) Update(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.SupplierStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	staff, err := h.service.UpdateStaff(id, &req, claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "员工更新成功",
+		"staff":   staff,
+	})
+}
+
+// UpdateStatus 处理启用/停用员工的请求
+func (h *SupplierStaffHandler) UpdateStatus(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req model.UpdateStaffStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.SetStaffEnabled(id, *req.IsEnabled, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "状态更新成功"})
+}
+
+// Delete 处理删除员工的请求
+func (h *SupplierStaffHandler) Delete(c *gin.Context) {
+	claims, ok := getClaims(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteStaff(id, claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "员工删除成功"})
+}
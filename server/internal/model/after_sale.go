@@ -0,0 +1,59 @@
+// server/internal/model/after_sale.go
+package model
+
+// 售后类型
+const (
+	AfterSaleTypeRefund int8 = 1 // 仅退款
+	AfterSaleTypeReturn int8 = 2 // 退货退款
+)
+
+// 售后状态。供应商驳回后买家可向学校申诉一次，学校仲裁的结果为最终结果。
+const (
+	AfterSalePending   int8 = 10 // 待审核
+	AfterSaleApproved  int8 = 20 // 已通过
+	AfterSaleRejected  int8 = 30 // 已驳回
+	AfterSaleDisputed  int8 = 40 // 申诉中
+	AfterSaleCancelled int8 = 50 // 已撤销
+)
+
+// 售后来源
+const (
+	AfterSaleSourceApply   int8 = 1 // 买家申请
+	AfterSaleSourceReceipt int8 = 2 // 验收差异自动生成
+)
+
+// ApplyAfterSaleRequest 定义了买家申请售后的请求体，退款金额不能超过明细金额减去已申请和已退款的金额
+type ApplyAfterSaleRequest struct {
+	OrderItemID uint     `json:"orderItemId" binding:"required"`
+	Type        int8     `json:"type" binding:"required,oneof=1 2"`
+	Quantity    int      `json:"quantity" binding:"min=0"`
+	Amount      float64  `json:"amount" binding:"required,gt=0"`
+	Reason      string   `json:"reason" binding:"required,max=255"`
+	Evidences   []string `json:"evidences" binding:"max=9"`
+}
+
+// HandleAfterSaleRequest 定义了供应商审核售后的请求体，驳回时须填写意见
+type HandleAfterSaleRequest struct {
+	Remark string `json:"remark" binding:"max=255"`
+}
+
+// DisputeAfterSaleRequest 定义了买家对驳回结果提出申诉的请求体
+type DisputeAfterSaleRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// ArbitrateAfterSaleRequest 定义了学校仲裁售后申诉的请求体。
+// 支持退款时 Amount 为最终退款金额，不传则按申请金额退款，且不能超过申请金额。
+type ArbitrateAfterSaleRequest struct {
+	Approve bool     `json:"approve"`
+	Amount  *float64 `json:"amount" binding:"omitempty,gt=0"`
+	Remark  string   `json:"remark" binding:"required,max=255"`
+}
+
+// AfterSaleFilter 定义了售后列表的筛选条件
+type AfterSaleFilter struct {
+	MerchantIDs []uint // 买家组织ID范围，为 nil 时不限制
+	SupplierID  uint   // 卖家组织ID，为 0 时不限制
+	OrderID     uint
+	Status      *int8
+}
@@ -0,0 +1,93 @@
+// server/internal/model/analytics.go
+package model
+
+import "time"
+
+// 采购趋势的统计粒度
+const (
+	AnalyticsByDay   = "day"
+	AnalyticsByWeek  = "week"
+	AnalyticsByMonth = "month"
+)
+
+// 采购金额的分组维度
+const (
+	AnalyticsGroupCategory = "category"
+	AnalyticsGroupSupplier = "supplier"
+	AnalyticsGroupCanteen  = "canteen"
+)
+
+// AnalyticsMaxDays 统计的最大时间跨度（天）
+const AnalyticsMaxDays = 366
+
+// AnalyticsQuery 定义了采购统计接口的查询参数，会按调用方所属组织收窄为 AnalyticsFilter
+type AnalyticsQuery struct {
+	StartDate  *time.Time // 开始日期（含），为空时默认结束日期前 30 天
+	EndDate    *time.Time // 结束日期（含），为空时默认今天
+	MerchantID uint       // 只统计某个买家，为 0 时不限制
+	SupplierID uint       // 只统计某个供应商，为 0 时不限制
+}
+
+// AnalyticsFilter 定义了采购统计的筛选条件，时间范围按下单时间计算。
+// 统计只包含未被拒单或取消的订单。
+type AnalyticsFilter struct {
+	MerchantIDs []uint    // 买家组织ID范围，为 nil 时不限制
+	SupplierID  uint      // 卖家组织ID，为 0 时不限制
+	StartDate   time.Time // 下单时间起（含）
+	EndDate     time.Time // 下单时间止（不含）
+}
+
+// AnalyticsSummary 定义了采购概览
+type AnalyticsSummary struct {
+	StartDate      string             `json:"startDate"`
+	EndDate        string             `json:"endDate"`
+	OrderCount     int64              `json:"orderCount"`
+	TotalAmount    float64            `json:"totalAmount"`
+	RefundAmount   float64            `json:"refundAmount"` // 期间内订单已退款的金额
+	NetAmount      float64            `json:"netAmount"`
+	AvgOrderAmount float64            `json:"avgOrderAmount"`
+	StatusCounts   []OrderStatusCount `json:"statusCounts"` // 各状态订单数，含已拒单和已取消
+}
+
+// AnalyticsTotals 定义了订单数和金额的合计
+type AnalyticsTotals struct {
+	OrderCount int64
+	Amount     float64
+}
+
+// SpendPoint 定义了采购趋势中的一个时间段
+type SpendPoint struct {
+	Period     string  `json:"period"` // 日：2006-01-02，周：2006-W01，月：2006-01
+	OrderCount int64   `json:"orderCount"`
+	Amount     float64 `json:"amount"`
+}
+
+// SpendGroup 定义了按分类、供应商或食堂分组的采购金额
+type SpendGroup struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name"`
+	OrderCount int64   `json:"orderCount"`
+	Amount     float64 `json:"amount"`
+	Share      float64 `json:"share" gorm:"-"` // 占总金额的百分比
+}
+
+// TopProduct 定义了采购金额排名靠前的商品
+type TopProduct struct {
+	ProductID uint    `json:"productId"`
+	Name      string  `json:"name"`
+	Specs     string  `json:"specs"`
+	Quantity  int64   `json:"quantity"`
+	Amount    float64 `json:"amount"`
+}
+
+// PricePaid 定义了商品的实际采购均价与指导价的对比
+type PricePaid struct {
+	ProductID  uint     `json:"productId"`
+	Name       string   `json:"name"`
+	Specs      string   `json:"specs"`
+	Quantity   int64    `json:"quantity"`
+	Amount     float64  `json:"amount"`
+	AvgPrice   float64  `json:"avgPrice"`
+	GuidePrice *float64 `json:"guidePrice"` // 未设置指导价时为空
+	Deviation  *float64 `json:"deviation"`  // 均价偏离指导价的百分比，未设置指导价时为空
+}
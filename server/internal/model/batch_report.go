@@ -0,0 +1,85 @@
+// server/internal/model/batch_report.go
+package model
+
+import "time"
+
+// 批次检测结论
+const (
+	BatchReportPass int8 = 1 // 合格
+	BatchReportFail int8 = 2 // 不合格
+)
+
+const (
+	// DefaultReportValidDays 分类未设置时检测报告自检测日起的有效天数
+	DefaultReportValidDays = 7
+	// MaxBatchReports 每条报价最多保留的检测报告数，超出时丢弃检测日期最早的
+	MaxBatchReports = 20
+)
+
+// ResidueItem 定义了一项农药残留检测值，单位 mg/kg
+type ResidueItem struct {
+	Name  string  `json:"name" binding:"required,max=50"`
+	Value float64 `json:"value" binding:"gte=0"`
+	Limit float64 `json:"limit" binding:"gt=0"` // 国家标准最大残留限量
+}
+
+// BatchReport 定义了报价 BatchReports 列中保存的单份批次检测报告，日期格式为 2006-01-02
+type BatchReport struct {
+	BatchNo        string        `json:"batchNo"`
+	ProductionDate string        `json:"productionDate"`
+	InspectionDate string        `json:"inspectionDate"`
+	Lab            string        `json:"lab"`
+	Result         int8          `json:"result"`
+	Residues       []ResidueItem `json:"residues"`
+	Attachment     string        `json:"attachment"`
+	ExpiresOn      string        `json:"expiresOn"` // 有效期至（含当天），上传时按分类设置计算
+	UploadedAt     time.Time     `json:"uploadedAt"`
+	UploaderID     uint          `json:"uploaderId"`
+}
+
+// UploadBatchReportRequest 定义了上传批次检测报告的请求体，同一批次号重复上传时覆盖原报告
+type UploadBatchReportRequest struct {
+	BatchNo        string        `json:"batchNo" binding:"required,max=50"`
+	ProductionDate string        `json:"productionDate" binding:"required,datetime=2006-01-02"`
+	InspectionDate string        `json:"inspectionDate" binding:"required,datetime=2006-01-02"`
+	Lab            string        `json:"lab" binding:"required,max=100"`
+	Result         int8          `json:"result" binding:"required,oneof=1 2"`
+	Residues       []ResidueItem `json:"residues" binding:"dive"`
+	Attachment     string        `json:"attachment" binding:"required,max=255"`
+}
+
+// ValidateBatchReportRequest 定义了校验检测报告的请求体，只校验不保存
+type ValidateBatchReportRequest struct {
+	QuoteID uint `json:"quoteId" binding:"required"`
+	UploadBatchReportRequest
+}
+
+// UpdateReportPolicyRequest 定义了设置分类检测报告要求的请求体
+type UpdateReportPolicyRequest struct {
+	RequireReport *bool `json:"requireReport" binding:"required"`
+	ValidDays     int   `json:"validDays" binding:"gte=0,lte=365"` // 0 表示使用默认值
+}
+
+// ReportPolicy 定义了商品所在分类生效的检测报告要求
+type ReportPolicy struct {
+	Required  bool `json:"required"`
+	ValidDays int  `json:"validDays"`
+}
+
+// BatchReportView 定义了检测报告的返回结构，附带有效状态
+type BatchReportView struct {
+	BatchReport
+	Expired bool `json:"expired"`
+	Valid   bool `json:"valid"` // 检测合格且未过期
+}
+
+// QuoteReports 定义了一条报价的检测报告及可下单状态
+type QuoteReports struct {
+	QuoteID      uint              `json:"quoteId"`
+	SupplierID   uint              `json:"supplierId"`
+	SupplierName string            `json:"supplierName,omitempty"`
+	Policy       ReportPolicy      `json:"policy"`
+	Orderable    bool              `json:"orderable"`
+	Reason       string            `json:"reason,omitempty"` // 不可下单的原因
+	Reports      []BatchReportView `json:"reports"`
+}
@@ -0,0 +1,91 @@
+// server/internal/model/cart.go
+package model
+
+// 购物车限制
+const (
+	MaxCartItems    = 200   // 购物车最多容纳的条目数
+	MaxCartQuantity = 99999 // 单个条目的最大数量
+)
+
+// 购物车条目的问题类型
+const (
+	CartIssueQuoteRemoved    = "quote_removed"    // 报价已被删除
+	CartIssueQuoteDisabled   = "quote_disabled"   // 报价已停止供货或供应商已被禁用
+	CartIssueQuoteReviewing  = "quote_reviewing"  // 报价价格待复核或复核驳回
+	CartIssueProductUnlisted = "product_unlisted" // 商品已下架、被合并或不属于本校
+	CartIssueReportInvalid   = "report_invalid"   // 缺少有效的批次检测报告
+	CartIssuePriceChanged    = "price_changed"    // 加入购物车后价格发生变化
+)
+
+// AddCartItemRequest 定义了加入购物车的请求体，购物车中已有该报价时累加数量
+type AddCartItemRequest struct {
+	QuoteID  uint `json:"quoteId" binding:"required"`
+	Quantity int  `json:"quantity" binding:"required,min=1,max=99999"`
+}
+
+// UpdateCartItemRequest 定义了修改购物车条目数量的请求体
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1,max=99999"`
+}
+
+// CartLineRow 定义了购物车查询的原始行，附带报价、商品和供应商的实时信息；报价已被删除时报价相关字段为零值
+type CartLineRow struct {
+	ID              uint
+	QuoteID         uint
+	Quantity        int
+	AddedPrice      float64
+	ProductID       uint
+	SupplierID      uint
+	SupplierName    string
+	SupplierEnabled bool
+	Price           float64
+	QuoteEnabled    bool
+	ReviewStatus    int8
+	BatchReports    string
+	ProductName     string
+	Specs           string
+	Unit            string
+	Image           string
+	CategoryID      uint
+	SchoolID        uint
+	AuditStatus     int8
+	IsListed        bool
+	MergedInto      uint
+}
+
+// CartLine 定义了购物车中的一个条目
+type CartLine struct {
+	ID           uint       `json:"id"`
+	QuoteID      uint       `json:"quoteId"`
+	ProductID    uint       `json:"productId"`
+	ProductName  string     `json:"productName"`
+	Specs        string     `json:"specs"`
+	Unit         string     `json:"unit"`
+	Image        *ImageURLs `json:"image"`
+	Quantity     int        `json:"quantity"`
+	AddedPrice   float64    `json:"addedPrice"` // 加入购物车时的单价
+	Price        float64    `json:"price"`      // 当前报价
+	Amount       float64    `json:"amount"`     // 按当前报价计算的小计
+	PriceChanged bool       `json:"priceChanged"`
+	Orderable    bool       `json:"orderable"` // 是否可以下单，价格变化不影响
+	Issues       []string   `json:"issues"`
+	Reason       string     `json:"reason"` // 不可下单的原因
+}
+
+// CartSupplierGroup 定义了购物车中同一供应商的条目
+type CartSupplierGroup struct {
+	SupplierID   uint       `json:"supplierId"`
+	SupplierName string     `json:"supplierName"`
+	Lines        []CartLine `json:"lines"`
+	Subtotal     float64    `json:"subtotal"` // 可下单条目的金额合计
+}
+
+// CartView 定义了按供应商分组的购物车
+type CartView struct {
+	Groups            []CartSupplierGroup `json:"groups"`
+	Invalid           []CartLine          `json:"invalid"` // 报价已被删除、无法归属供应商的条目
+	Total             float64             `json:"total"`   // 可下单条目的金额合计
+	ItemCount         int                 `json:"itemCount"`
+	InvalidCount      int                 `json:"invalidCount"`      // 不可下单的条目数
+	PriceChangedCount int                 `json:"priceChangedCount"` // 价格发生变化的条目数
+}
@@ -0,0 +1,47 @@
+// server/internal/model/category.go
+package model
+
+// CreateCategoryRequest 定义了创建商品分类的请求体
+type CreateCategoryRequest struct {
+	Name     string `json:"name" binding:"required,max=50"`
+	ParentID uint   `json:"parentId"` // 0 表示顶级分类
+	Icon     string `json:"icon"`
+	Sort     int    `json:"sort"`
+}
+
+// UpdateCategoryRequest 定义了修改分类名称和图标的请求体
+type UpdateCategoryRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+	Icon string `json:"icon"`
+}
+
+// MoveCategoryRequest 定义了移动分类到新父级的请求体
+type MoveCategoryRequest struct {
+	ParentID uint `json:"parentId"` // 0 表示移动为顶级分类
+	Sort     *int `json:"sort"`     // 为空时保持原排序值
+}
+
+// CategorySortItem 定义了单个分类的排序值
+type CategorySortItem struct {
+	ID   uint `json:"id" binding:"required"`
+	Sort int  `json:"sort"`
+}
+
+// SortCategoriesRequest 定义了批量调整分类排序的请求体
+type SortCategoriesRequest struct {
+	Items []CategorySortItem `json:"items" binding:"required,min=1,dive"`
+}
+
+// CategoryNode 定义了分类树中的一个节点
+type CategoryNode struct {
+	ID       uint            `json:"id"`
+	Name     string          `json:"name"`
+	ParentID uint            `json:"parentId"`
+	Icon     string          `json:"icon"`
+	Sort     int             `json:"sort"`
+	IsLeaf   bool            `json:"isLeaf"`
+	Children []*CategoryNode `json:"children"`
+	// 检测报告要求，只反映分类自身的设置
+	RequireReport   bool `json:"requireReport"`
+	ReportValidDays int  `json:"reportValidDays"`
+}
@@ -0,0 +1,97 @@
+// server/internal/model/comparison.go
+package model
+
+import "time"
+
+// 价格走势
+const (
+	PriceTrendUp   = "up"   // 上涨
+	PriceTrendDown = "down" // 下跌
+	PriceTrendFlat = "flat" // 持平
+)
+
+// QuoteCompareRow 定义了比价查询的原始行，报价附带供应商名称
+type QuoteCompareRow struct {
+	ID           uint
+	ProductID    uint
+	SupplierID   uint
+	SupplierName string
+	Price        float64
+	BatchReports string
+}
+
+// SupplierPerformance 定义了供应商在统计周期内的履约表现
+type SupplierPerformance struct {
+	SupplierID       uint
+	OrderCount       int64      // 订单数
+	CompletedCount   int64      // 已完成订单数
+	AfterSaleCount   int64      // 售后申请数
+	LastDeliveryAt   *time.Time // 最近一次送达时间
+	AvgDeliveryHours *float64   // 平均送达时长（小时）
+}
+
+// QuoteComparison 定义了比价结果中的一条报价
+type QuoteComparison struct {
+	QuoteID          uint       `json:"quoteId"`
+	SupplierID       uint       `json:"supplierId"`
+	SupplierName     string     `json:"supplierName"`
+	Price            float64    `json:"price"`
+	IsBest           bool       `json:"isBest"`
+	Rating           *float64   `json:"rating"` // 0-5 分，统计周期内没有订单时为空
+	OrderCount       int64      `json:"orderCount"`
+	CompletedRate    float64    `json:"completedRate"`
+	AfterSaleCount   int64      `json:"afterSaleCount"`
+	LastDeliveryAt   *time.Time `json:"lastDeliveryAt"`
+	AvgDeliveryHours *float64   `json:"avgDeliveryHours"`
+	PriceChange      float64    `json:"priceChange"` // 与统计周期开始时相比的价格变化
+	Trend            string     `json:"trend"`
+	Orderable        bool       `json:"orderable"`             // 满足分类的检测报告要求，可以下单
+	ReportIssue      string     `json:"reportIssue,omitempty"` // 不可下单的原因
+}
+
+// ProductComparison 定义了单个商品的比价结果，报价按价格从低到高排列
+type ProductComparison struct {
+	Product     *ScmProduct       `json:"product"`
+	Quotes      []QuoteComparison `json:"quotes"`
+	BestQuoteID uint              `json:"bestQuoteId"` // 没有可下单的报价时为 0
+	BestPrice   float64           `json:"bestPrice"`
+}
+
+// BasketItem 定义了采购清单中的一项
+type BasketItem struct {
+	ProductID uint `json:"productId" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,gt=0"`
+}
+
+// BasketRequest 定义了按清单挑选最低价报价的请求体
+type BasketRequest struct {
+	Items []BasketItem `json:"items" binding:"required,min=1,dive"`
+}
+
+// BasketLine 定义了清单中已选定报价的一项
+type BasketLine struct {
+	ProductID    uint    `json:"productId"`
+	ProductName  string  `json:"productName"`
+	Specs        string  `json:"specs"`
+	Unit         string  `json:"unit"`
+	Quantity     int     `json:"quantity"`
+	QuoteID      uint    `json:"quoteId"`
+	SupplierID   uint    `json:"supplierId"`
+	SupplierName string  `json:"supplierName"`
+	Price        float64 `json:"price"`
+	Amount       float64 `json:"amount"`
+}
+
+// BasketUnavailable 定义了清单中无法选定报价的商品
+type BasketUnavailable struct {
+	ProductID uint   `json:"productId"`
+	Reason    string `json:"reason"`
+}
+
+// BasketResult 定义了按清单挑选最低价报价的结果
+type BasketResult struct {
+	Lines         []BasketLine        `json:"lines"`
+	Unavailable   []BasketUnavailable `json:"unavailable"`
+	TotalAmount   float64             `json:"totalAmount"`
+	SupplierCount int                 `json:"supplierCount"` // 涉及的供应商数量，即下单后将拆分的订单数
+}
@@ -0,0 +1,41 @@
+// server/internal/model/delivery.go
+package model
+
+import "time"
+
+// 配送趟次状态
+const (
+	DeliveryRunPending    int8 = 0 // 待出发
+	DeliveryRunDelivering int8 = 1 // 配送中
+	DeliveryRunFinished   int8 = 2 // 已完成
+)
+
+// CreateDeliveryRunRequest 定义了排车的请求体，订单须处于分拣中状态且尚未排车
+type CreateDeliveryRunRequest struct {
+	DeliveryDate string `json:"deliveryDate" binding:"required,datetime=2006-01-02"`
+	OrderIDs     []uint `json:"orderIds" binding:"required,min=1"`
+	DriverID     uint   `json:"driverId" binding:"required"`
+	VehicleNo    string `json:"vehicleNo" binding:"required,max=20"`
+}
+
+// AssignDriverRequest 定义了更换司机和车辆的请求体
+type AssignDriverRequest struct {
+	DriverID  uint   `json:"driverId" binding:"required"`
+	VehicleNo string `json:"vehicleNo" binding:"required,max=20"`
+}
+
+// DeliveryRunFilter 定义了配送趟次列表的筛选条件
+type DeliveryRunFilter struct {
+	SupplierID   uint
+	DriverID     uint
+	DeliveryDate *time.Time
+	Status       *int8
+}
+
+// DeliveryRunDetail 定义了配送趟次详情，附带司机信息和趟次内的订单
+type DeliveryRunDetail struct {
+	Run          *OrdDeliveryRun `json:"run"`
+	DriverName   string          `json:"driverName"`
+	DriverMobile string          `json:"driverMobile"`
+	Orders       []OrdOrder      `json:"orders"`
+}
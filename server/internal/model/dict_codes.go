@@ -0,0 +1,8 @@
+package model
+
+// DictCode 定义了系统内置数据字典的编码
+const (
+	DictEnabledStatus = "enabled_status" // 启用状态 true:启用 false:禁用
+	DictUserStatus    = "user_status"    // 账号状态 1:正常 2:锁定
+	DictOrderStatus   = "order_status"   // 订单状态
+)
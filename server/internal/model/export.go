@@ -0,0 +1,22 @@
+// server/internal/model/export.go
+package model
+
+// 导出任务状态
+const (
+	ExportJobPending    int8 = 10
+	ExportJobProcessing int8 = 20
+	ExportJobDone       int8 = 30
+	ExportJobFailed     int8 = 40
+)
+
+// ExportColumnInfo 描述一个可导出的列，供前端渲染列选择器
+type ExportColumnInfo struct {
+	Key    string `json:"key"`
+	Header string `json:"header"`
+}
+
+// ExportJobResponse 定义了创建后台导出任务后的返回结构
+type ExportJobResponse struct {
+	Job         *SysExportJob `json:"job"`
+	DownloadURL string        `json:"downloadUrl"`
+}
@@ -0,0 +1,57 @@
+// server/internal/model/file.go
+package model
+
+import "time"
+
+// FilePurposeReceipt 上传用途：收货凭证，图片会保留拍摄时间
+const FilePurposeReceipt = "receipt"
+
+// ImageURLs 定义了图片各尺寸的下载地址，没有缩略图的文件各尺寸均为原图地址
+type ImageURLs struct {
+	Key    string `json:"key"`
+	URL    string `json:"url"`
+	Medium string `json:"medium"`
+	Thumb  string `json:"thumb"`
+}
+
+// FileResponse 定义了上传文件的返回结构。
+// 业务数据中保存 Key，展示时通过签名地址访问；签名地址会过期，不应写入业务数据。
+type FileResponse struct {
+	ID        uint   `json:"id"`
+	Key       string `json:"key"`
+	Size      int64  `json:"size"`
+	MimeType  string `json:"mimeType"`
+	URL       string `json:"url"`       // 签名下载地址
+	Duplicate bool   `json:"duplicate"` // 相同内容的文件已存在，未重复保存
+
+	Width      int        `json:"width,omitempty"`
+	Height     int        `json:"height,omitempty"`
+	Medium     string     `json:"medium,omitempty"`
+	Thumb      string     `json:"thumb,omitempty"`
+	CapturedAt *time.Time `json:"capturedAt,omitempty"`
+}
+
+// 引用文件的业务数据类别，用于判断用户能否获取文件的下载地址
+const (
+	FileOwnerPublic   = "public"   // 分类图标、商品图等登录用户均可查看
+	FileOwnerOrder    = "order"    // 订单及其售后、溯源记录
+	FileOwnerQuote    = "quote"    // 供应商报价的检测报告
+	FileOwnerSupplier = "supplier" // 供应商内部资料，如员工健康证
+)
+
+// FileOwner 描述一条引用了文件的业务数据
+type FileOwner struct {
+	Kind string
+	ID   uint
+}
+
+// SignFileURLsRequest 定义了批量获取文件下载地址的请求体
+type SignFileURLsRequest struct {
+	Keys []string `json:"keys" binding:"required,min=1,max=100"`
+}
+
+// OrphanCleanupResult 定义了孤儿文件清理的结果
+type OrphanCleanupResult struct {
+	Scanned int `json:"scanned"`
+	Deleted int `json:"deleted"`
+}
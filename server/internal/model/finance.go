@@ -4,11 +4,24 @@ import (
 	"time"
 )
 
+// 资金流水类型
+const (
+	BillTypeOrder  int8 = 1 // 订单收款
+	BillTypeRefund int8 = 2 // 售后退款
+)
+
+// 资金流向
+const (
+	BillIncome  int8 = 1 // 收入
+	BillExpense int8 = 2 // 支出
+)
+
 // FinBill 资金流水表
 type FinBill struct {
 	ID          uint      `gorm:"primarykey"`
 	BillNo      string    `gorm:"type:varchar(32);not null;comment:流水号"`
-	OrderID     uint      `gorm:"not null;comment:关联订单"`
+	OrderID     uint      `gorm:"not null;index;comment:关联订单"`
+	AfterSaleID uint      `gorm:"not null;default:0;comment:关联售后，订单收款为0"`
 	SchoolID    uint      `gorm:"not null"`
 	SupplierID  uint      `gorm:"not null"`
 	Amount      float64   `gorm:"type:decimal(12,2);not null"`
@@ -0,0 +1,62 @@
+// server/internal/model/guide_price.go
+package model
+
+// 指导价超出上限时的处理方式
+const (
+	GuidePriceOverFlag   int8 = 1 // 标记为待复核
+	GuidePriceOverReject int8 = 2 // 直接拒绝
+)
+
+// 报价价格复核状态
+const (
+	QuoteReviewNone     int8 = 0 // 无需复核
+	QuoteReviewPending  int8 = 1 // 待复核
+	QuoteReviewAccepted int8 = 2 // 复核通过
+	QuoteReviewRejected int8 = 3 // 复核驳回
+)
+
+// SetGuidePriceRequest 定义了设置商品指导价的请求体
+type SetGuidePriceRequest struct {
+	GuidePrice float64 `json:"guidePrice" binding:"required,gt=0"`
+	Deviation  float64 `json:"deviation" binding:"gte=0,lte=100"`
+	OverMode   int8    `json:"overMode" binding:"required,oneof=1 2"`
+}
+
+// RejectQuoteRequest 定义了驳回超价报价的请求体，驳回原因必填
+type RejectQuoteRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// GuidePriceItem 定义了指导价列表的返回结构，附带商品基本信息
+type GuidePriceItem struct {
+	ScmGuidePrice
+	ProductName string `json:"productName"`
+	Specs       string `json:"specs"`
+	Unit        string `json:"unit"`
+}
+
+// QuoteBandFilter 定义了报价价格带查询的筛选条件
+type QuoteBandFilter struct {
+	SchoolID     uint  // 监管学校，为 0 时不限制
+	ReviewStatus *int8 // 价格复核状态
+	OutOfBand    bool  // 只返回价格超出指导价区间的启用报价
+}
+
+// QuoteBandItem 定义了报价与指导价对照的返回结构
+type QuoteBandItem struct {
+	QuoteID       uint     `json:"quoteId"`
+	ProductID     uint     `json:"productId"`
+	ProductName   string   `json:"productName"`
+	Specs         string   `json:"specs"`
+	Unit          string   `json:"unit"`
+	SupplierID    uint     `json:"supplierId"`
+	SupplierName  string   `json:"supplierName"`
+	Price         float64  `json:"price"`
+	IsEnabled     bool     `json:"isEnabled"`
+	ReviewStatus  int8     `json:"reviewStatus"`
+	GuidePrice    *float64 `json:"guidePrice"` // 未设置指导价时为空
+	Deviation     *float64 `json:"deviation"`
+	Floor         *float64 `json:"floor" gorm:"-"`
+	Ceiling       *float64 `json:"ceiling" gorm:"-"`
+	DeviationRate *float64 `json:"deviationRate" gorm:"-"` // 报价相对指导价的偏离百分比
+}
@@ -0,0 +1,19 @@
+// server/internal/model/import.go
+package model
+
+// ImportRowError 记录导入文件中某一行未通过校验的原因
+type ImportRowError struct {
+	Row    int      `json:"row"` // 表格中的行号（从 1 开始，表头为第 1 行）
+	Errors []string `json:"errors"`
+}
+
+// ImportResult 定义了批量导入的结果报告
+type ImportResult struct {
+	Type     string           `json:"type"`
+	DryRun   bool             `json:"dryRun"`
+	Total    int              `json:"total"`    // 数据行总数（不含表头和空行）
+	Valid    int              `json:"valid"`    // 通过校验的行数
+	Invalid  int              `json:"invalid"`  // 未通过校验的行数
+	Imported int              `json:"imported"` // 实际写入数据库的行数，试运行时恒为 0
+	Errors   []ImportRowError `json:"errors"`
+}
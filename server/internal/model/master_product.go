@@ -0,0 +1,47 @@
+// server/internal/model/master_product.go
+package model
+
+// MasterProductRequest 定义了平台新建或修改标准商品的请求体
+type MasterProductRequest struct {
+	CategoryID uint     `json:"categoryId" binding:"required"`
+	Name       string   `json:"name" binding:"required,max=100"`
+	Image      string   `json:"image"`
+	Specs      string   `json:"specs" binding:"required,max=100"`
+	Unit       string   `json:"unit" binding:"required,max=20"`
+	Certs      []string `json:"certs"`
+}
+
+// UpdateMasterStatusRequest 定义了启用/停用标准商品的请求体
+type UpdateMasterStatusRequest struct {
+	IsEnabled *bool `json:"isEnabled" binding:"required"`
+}
+
+// PushMasterProductsRequest 定义了平台向学校下发标准商品的请求体
+type PushMasterProductsRequest struct {
+	MasterIDs []uint `json:"masterIds" binding:"required,min=1"`
+	SchoolIDs []uint `json:"schoolIds" binding:"required,min=1"`
+}
+
+// SubscribeMasterProductsRequest 定义了学校订阅标准商品的请求体
+type SubscribeMasterProductsRequest struct {
+	MasterIDs []uint `json:"masterIds" binding:"required,min=1"`
+}
+
+// MasterProductListFilter 定义了标准商品列表的筛选条件
+type MasterProductListFilter struct {
+	CategoryID uint   // 分类，为 0 时不限制
+	IsEnabled  *bool  // 是否可下发
+	Keyword    string // 按名称模糊匹配
+}
+
+// PushResult 定义了下发标准商品的结果
+type PushResult struct {
+	Created int `json:"created"` // 新生成的学校商品数
+	Skipped int `json:"skipped"` // 学校已有该标准商品而跳过的数量
+}
+
+// MasterSyncResult 定义了修改标准商品后同步副本的结果
+type MasterSyncResult struct {
+	Master *ScmMasterProduct `json:"master"`
+	Synced int64             `json:"synced"` // 同步的学校副本数
+}
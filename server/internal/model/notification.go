@@ -0,0 +1,7 @@
+// server/internal/model/notification.go
+package model
+
+// 站内通知类别
+const (
+	NotifyStandingOrder = "standing_order" // 定期订单
+)
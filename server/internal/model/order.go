@@ -6,10 +6,13 @@ import (
 
 // OrdCart 购物车
 type OrdCart struct {
-	ID         uint `gorm:"primarykey"`
-	MerchantID uint `gorm:"not null;comment:买家"`
-	QuoteID    uint `gorm:"not null;comment:选中报价"`
-	Quantity   int  `gorm:"not null;default:1"`
+	ID         uint      `gorm:"primarykey"`
+	MerchantID uint      `gorm:"not null;uniqueIndex:uk_merchant_quote;comment:买家"`
+	QuoteID    uint      `gorm:"not null;uniqueIndex:uk_merchant_quote;index;comment:选中报价"`
+	Quantity   int       `gorm:"not null;default:1"`
+	AddedPrice float64   `gorm:"type:decimal(10,2);not null;default:0;comment:加入时的单价"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
 }
 
 func (OrdCart) TableName() string {
@@ -20,20 +23,172 @@ func (OrdCart) TableName() string {
 type OrdOrder struct {
 	ID              uint       `gorm:"primarykey"`
 	OrderNo         string     `gorm:"type:varchar(32);not null;uniqueIndex;comment:订单号"`
+	CheckoutID      uint       `gorm:"not null;default:0;index;comment:所属结算批次"`
 	MerchantID      uint       `gorm:"not null;comment:买家"`
 	SupplierID      uint       `gorm:"not null;comment:卖家"`
-	Status          int8       `gorm:"not null;default:10;comment:10:待接 30:配送 40:完成"`
+	Status          int8       `gorm:"not null;default:10;comment:10:待接单 20:已接单 25:分拣中 30:配送中 35:已送达 38:已收货 40:已完成 50:已拒单 60:已取消"`
 	ReceiptVouchers string     `gorm:"type:json;comment:收货凭证"`
 	TotalAmount     float64    `gorm:"type:decimal(12,2);not null;default:0.00"`
+	DeliveryDate    *time.Time `gorm:"type:date;index;comment:期望送达日期"`
+	DeliveryRunID   uint       `gorm:"not null;default:0;index;comment:所属配送趟次，0表示尚未排车"`
 	CreatedAt       time.Time  `gorm:"autoCreateTime"`
 	DeliveryTime    *time.Time `gorm:"comment:配送时间"`
 	ArrivalTime     *time.Time `gorm:"comment:送达时间"`
+	// 买家验收时填写
+	ReceiverName     string     `gorm:"type:varchar(50);comment:验收人"`
+	ReceiptSignature string     `gorm:"type:varchar(255);comment:验收人签名图片"`
+	ReceivedAt       *time.Time `gorm:"comment:验收时间"`
+
+	VoucherImages  []ImageURLs    `gorm:"-"` // 收货凭证各尺寸的下载地址，仅用于接口返回
+	SignatureImage *ImageURLs     `gorm:"-"` // 验收人签名的下载地址，仅用于接口返回
+	Items          []OrdOrderItem `gorm:"-"` // 订单明细，仅用于接口返回
+	MerchantName   string         `gorm:"-"` // 买家名称，仅用于接口返回
 }
 
 func (OrdOrder) TableName() string {
 	return "ord_orders"
 }
 
+// OrdOrderLog 订单状态变更记录
+type OrdOrderLog struct {
+	ID            uint      `gorm:"primarykey"`
+	OrderID       uint      `gorm:"not null;index;comment:订单ID"`
+	FromStatus    int8      `gorm:"not null;comment:变更前状态"`
+	ToStatus      int8      `gorm:"not null;comment:变更后状态"`
+	Actor         string    `gorm:"type:varchar(20);not null;comment:操作方 buyer/supplier/platform/system"`
+	OperatorID    uint      `gorm:"not null;default:0;comment:操作人ID，系统操作为0"`
+	OperatorOrgID uint      `gorm:"not null;default:0;comment:操作人组织ID"`
+	Remark        string    `gorm:"type:varchar(255);comment:备注/原因"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (OrdOrderLog) TableName() string {
+	return "ord_order_logs"
+}
+
+// OrdPickList 分拣单，汇总某送达日期下已接单订单的明细，按商品和买家分行
+type OrdPickList struct {
+	ID           uint       `gorm:"primarykey"`
+	SupplierID   uint       `gorm:"not null;index:idx_supplier_date;comment:供应商"`
+	DeliveryDate time.Time  `gorm:"type:date;not null;index:idx_supplier_date;comment:送达日期"`
+	PickerID     uint       `gorm:"not null;default:0;comment:分拣员，0表示未指派"`
+	Status       int8       `gorm:"not null;default:0;comment:0:待分拣 1:分拣中 2:已完成"`
+	OrderCount   int        `gorm:"not null;default:0;comment:包含的订单数"`
+	OperatorID   uint       `gorm:"not null;comment:生成人ID"`
+	CompletedAt  *time.Time `gorm:"comment:完成时间"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (OrdPickList) TableName() string {
+	return "ord_pick_lists"
+}
+
+// OrdPickLine 分拣行，同一分拣单中同一商品发往同一买家的明细合并为一行
+type OrdPickLine struct {
+	ID         uint       `gorm:"primarykey"`
+	PickListID uint       `gorm:"not null;index;comment:分拣单ID"`
+	ProductID  uint       `gorm:"not null;comment:商品ID"`
+	SnapName   string     `gorm:"type:varchar(100);not null;comment:快照:品名"`
+	SnapSpecs  string     `gorm:"type:varchar(100);not null;comment:快照:规格"`
+	DestID     uint       `gorm:"not null;comment:收货食堂，直属学校的商户归入学校"`
+	Quantity   int        `gorm:"not null;comment:应拣数量"`
+	PickedQty  int        `gorm:"not null;default:0;comment:实拣数量"`
+	Picked     bool       `gorm:"not null;default:false;comment:是否已拣"`
+	PickedAt   *time.Time `gorm:"comment:分拣时间"`
+}
+
+func (OrdPickLine) TableName() string {
+	return "ord_pick_lines"
+}
+
+// OrdDeliveryRun 配送趟次，一个司机驾驶一辆车配送一批订单
+type OrdDeliveryRun struct {
+	ID           uint       `gorm:"primarykey"`
+	SupplierID   uint       `gorm:"not null;index:idx_supplier_date;comment:供应商"`
+	DeliveryDate time.Time  `gorm:"type:date;not null;index:idx_supplier_date;comment:送达日期"`
+	DriverID     uint       `gorm:"not null;index;comment:司机"`
+	VehicleNo    string     `gorm:"type:varchar(20);not null;comment:车牌号"`
+	Status       int8       `gorm:"not null;default:0;comment:0:待出发 1:配送中 2:已完成"`
+	OrderCount   int        `gorm:"not null;default:0;comment:包含的订单数"`
+	OperatorID   uint       `gorm:"not null;comment:排车人ID"`
+	DepartedAt   *time.Time `gorm:"comment:出发时间"`
+	FinishedAt   *time.Time `gorm:"comment:全部送达时间"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (OrdDeliveryRun) TableName() string {
+	return "ord_delivery_runs"
+}
+
+// OrdStandingOrder 定期订单模板，调度器在送达日期前 LeadDays 天按模板自动下单
+type OrdStandingOrder struct {
+	ID          uint       `gorm:"primarykey"`
+	MerchantID  uint       `gorm:"not null;index;comment:买家"`
+	SupplierID  uint       `gorm:"not null;comment:供应商"`
+	Name        string     `gorm:"type:varchar(50);not null;comment:模板名称"`
+	Weekdays    string     `gorm:"type:varchar(20);not null;default:'';comment:每周送达日，逗号分隔，0表示周日"`
+	Dates       string     `gorm:"type:json;comment:指定送达日期"`
+	SkipDates   string     `gorm:"type:json;comment:跳过的送达日期"`
+	LeadDays    int        `gorm:"not null;default:1;comment:提前下单天数"`
+	StartDate   time.Time  `gorm:"type:date;not null;comment:首个送达日期"`
+	EndDate     *time.Time `gorm:"type:date;comment:最后送达日期，为空表示不限"`
+	Status      int8       `gorm:"not null;default:0;index;comment:0:启用 1:暂停"`
+	LastRunDate *time.Time `gorm:"type:date;comment:最近一次处理的送达日期"`
+	OperatorID  uint       `gorm:"not null;comment:创建人ID，自动下单以其名义记录"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime"`
+
+	Items []CartLine `gorm:"-"` // 模板商品及其当前可下单状态，仅用于接口返回
+}
+
+func (OrdStandingOrder) TableName() string {
+	return "ord_standing_orders"
+}
+
+// OrdStandingOrderItem 定期订单模板的商品
+type OrdStandingOrderItem struct {
+	ID              uint `gorm:"primarykey"`
+	StandingOrderID uint `gorm:"not null;index;comment:模板ID"`
+	QuoteID         uint `gorm:"not null;comment:报价ID"`
+	Quantity        int  `gorm:"not null;comment:数量"`
+}
+
+func (OrdStandingOrderItem) TableName() string {
+	return "ord_standing_order_items"
+}
+
+// OrdStandingOrderRun 定期订单的执行记录，同一模板同一送达日期只执行一次
+type OrdStandingOrderRun struct {
+	ID              uint      `gorm:"primarykey"`
+	StandingOrderID uint      `gorm:"not null;uniqueIndex:uk_standing_date;comment:模板ID"`
+	DeliveryDate    time.Time `gorm:"type:date;not null;uniqueIndex:uk_standing_date;comment:送达日期"`
+	Status          int8      `gorm:"not null;comment:1:已下单 2:已跳过 3:失败"`
+	CheckoutID      uint      `gorm:"not null;default:0;comment:生成的结算批次"`
+	Message         string    `gorm:"type:varchar(500);comment:说明"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+}
+
+func (OrdStandingOrderRun) TableName() string {
+	return "ord_standing_order_runs"
+}
+
+// OrdCheckout 结算批次，一次结算按供应商拆分为多个订单；同一买家的结算令牌唯一，用于防止重复提交
+type OrdCheckout struct {
+	ID          uint      `gorm:"primarykey"`
+	MerchantID  uint      `gorm:"not null;uniqueIndex:uk_merchant_token;comment:买家"`
+	Token       string    `gorm:"type:varchar(64);not null;uniqueIndex:uk_merchant_token;comment:结算令牌"`
+	OrderCount  int       `gorm:"not null;default:0;comment:拆分出的订单数"`
+	TotalAmount float64   `gorm:"type:decimal(12,2);not null;default:0.00"`
+	OperatorID  uint      `gorm:"not null;comment:下单人ID"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (OrdCheckout) TableName() string {
+	return "ord_checkouts"
+}
+
 // OrdOrderItem 订单明细 - 交易快照
 type OrdOrderItem struct {
 	ID        uint    `gorm:"primarykey"`
@@ -45,6 +200,16 @@ type OrdOrderItem struct {
 	SnapPrice float64 `gorm:"type:decimal(10,2);not null;comment:快照:单价"`
 	Quantity  int     `gorm:"not null;comment:数量"`
 	Amount    float64 `gorm:"type:decimal(12,2);not null;comment:该项总价"`
+	// 供应商可将明细标记为缺货或少发，Quantity 随之调整，OrderedQty 保留下单时的数量
+	OrderedQty   int    `gorm:"not null;default:0;comment:下单数量"`
+	FulfilStatus int8   `gorm:"not null;default:0;comment:0:正常 1:少发 2:缺货"`
+	FulfilRemark string `gorm:"type:varchar(255);comment:缺货/少发说明"`
+	PickLineID   uint   `gorm:"not null;default:0;index;comment:所属分拣行，0表示尚未生成分拣单"`
+	// 买家验收结果，ReceivedQty 为实收数量，与 Quantity 不一致时生成售后或调价记录
+	ReceiveStatus int8     `gorm:"not null;default:0;comment:0:待验收 1:已验收 2:拒收"`
+	ReceivedQty   int      `gorm:"not null;default:0;comment:实收数量"`
+	ActualWeight  *float64 `gorm:"type:decimal(10,3);comment:实称重量(kg)"`
+	ReceiveRemark string   `gorm:"type:varchar(255);comment:拒收/差异原因"`
 }
 
 func (OrdOrderItem) TableName() string {
@@ -53,18 +218,55 @@ func (OrdOrderItem) TableName() string {
 
 // OrdAfterSale 售后表
 type OrdAfterSale struct {
-	ID          uint    `gorm:"primarykey"`
-	OrderItemID uint    `gorm:"not null;comment:关联明细"`
-	Type        int8    `gorm:"not null;comment:1:仅退款 2:退货退款"`
-	Reason      string  `gorm:"type:varchar(255);not null;comment:原因"`
-	ApplyAmount float64 `gorm:"type:decimal(10,2);not null;comment:金额"`
-	Status      int8    `gorm:"not null;default:10;comment:10:待审 20:通过 30:驳回"`
+	ID                uint       `gorm:"primarykey"`
+	OrderID           uint       `gorm:"not null;default:0;index;comment:关联订单"`
+	OrderItemID       uint       `gorm:"not null;index;comment:关联明细"`
+	MerchantID        uint       `gorm:"not null;default:0;index;comment:买家"`
+	SupplierID        uint       `gorm:"not null;default:0;index;comment:卖家"`
+	Source            int8       `gorm:"not null;default:1;comment:1:买家申请 2:验收差异"`
+	Type              int8       `gorm:"not null;comment:1:仅退款 2:退货退款"`
+	Quantity          int        `gorm:"not null;default:0;comment:涉及数量"`
+	Reason            string     `gorm:"type:varchar(255);not null;comment:原因"`
+	Evidences         string     `gorm:"type:json;comment:凭证图片"`
+	ApplyAmount       float64    `gorm:"type:decimal(10,2);not null;comment:金额"`
+	RefundAmount      float64    `gorm:"type:decimal(10,2);not null;default:0;comment:实际退款金额"`
+	Status            int8       `gorm:"not null;default:10;comment:10:待审 20:通过 30:驳回 40:申诉中 50:已撤销"`
+	SupplierRemark    string     `gorm:"type:varchar(255);comment:供应商处理意见"`
+	DisputeReason     string     `gorm:"type:varchar(255);comment:买家申诉理由"`
+	Arbitrated        bool       `gorm:"not null;default:false;comment:是否经学校仲裁"`
+	ArbitrationRemark string     `gorm:"type:varchar(255);comment:学校仲裁意见"`
+	OperatorID        uint       `gorm:"not null;default:0;comment:申请人ID，验收自动生成时为验收人"`
+	HandlerID         uint       `gorm:"not null;default:0;comment:最后处理人ID"`
+	HandledAt         *time.Time `gorm:"comment:最后处理时间"`
+	CreatedAt         time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time  `gorm:"autoUpdateTime"`
+
+	OrderNo        string      `gorm:"-"` // 订单号，仅用于接口返回
+	ProductName    string      `gorm:"-"` // 商品名称快照，仅用于接口返回
+	EvidenceImages []ImageURLs `gorm:"-"` // 凭证图片各尺寸的下载地址，仅用于接口返回
 }
 
 func (OrdAfterSale) TableName() string {
 	return "ord_after_sales"
 }
 
+// OrdPriceAdjustment 订单调价记录，验收时实收数量多于发货数量，按快照单价补计差额
+type OrdPriceAdjustment struct {
+	ID           uint      `gorm:"primarykey"`
+	OrderID      uint      `gorm:"not null;index;comment:关联订单"`
+	OrderItemID  uint      `gorm:"not null;comment:关联明细"`
+	Quantity     int       `gorm:"not null;comment:数量差额"`
+	BeforeAmount float64   `gorm:"type:decimal(12,2);not null;comment:调整前明细金额"`
+	AfterAmount  float64   `gorm:"type:decimal(12,2);not null;comment:调整后明细金额"`
+	Reason       string    `gorm:"type:varchar(255);not null;comment:原因"`
+	OperatorID   uint      `gorm:"not null;comment:操作人ID"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+func (OrdPriceAdjustment) TableName() string {
+	return "ord_price_adjustments"
+}
+
 // OrdItemTrace 全链路溯源表
 type OrdItemTrace struct {
 	ID           uint      `gorm:"primarykey"`
@@ -0,0 +1,116 @@
+// server/internal/model/order_request.go
+package model
+
+import "time"
+
+// 订单状态
+const (
+	OrderStatusPending    int8 = 10 // 待接单
+	OrderStatusAccepted   int8 = 20 // 已接单
+	OrderStatusPicking    int8 = 25 // 分拣中
+	OrderStatusDelivering int8 = 30 // 配送中
+	OrderStatusDelivered  int8 = 35 // 已送达
+	OrderStatusReceived   int8 = 38 // 已收货
+	OrderStatusCompleted  int8 = 40 // 已完成
+	OrderStatusRejected   int8 = 50 // 已拒单
+	OrderStatusCancelled  int8 = 60 // 已取消
+)
+
+// OrderStatusLabels 订单状态的中文名称
+var OrderStatusLabels = map[int8]string{
+	OrderStatusPending:    "待接单",
+	OrderStatusAccepted:   "已接单",
+	OrderStatusPicking:    "分拣中",
+	OrderStatusDelivering: "配送中",
+	OrderStatusDelivered:  "已送达",
+	OrderStatusReceived:   "已收货",
+	OrderStatusCompleted:  "已完成",
+	OrderStatusRejected:   "已拒单",
+	OrderStatusCancelled:  "已取消",
+}
+
+// 订单状态变更的操作方
+const (
+	OrderActorBuyer    = "buyer"    // 买家（食堂、商户）
+	OrderActorSupplier = "supplier" // 供应商
+	OrderActorPlatform = "platform" // 平台
+	OrderActorSystem   = "system"   // 系统自动操作
+)
+
+// 订单明细的履约状态
+const (
+	OrderItemFulfilNormal     int8 = 0 // 正常
+	OrderItemFulfilShort      int8 = 1 // 少发
+	OrderItemFulfilOutOfStock int8 = 2 // 缺货
+)
+
+// OrderListFilter 定义了订单列表的筛选条件
+type OrderListFilter struct {
+	MerchantIDs  []uint     // 买家组织ID范围，为 nil 时不限制
+	SupplierID   uint       // 卖家组织ID，为 0 时不限制
+	Status       *int8      // 订单状态
+	StartDate    *time.Time // 下单时间起（含）
+	EndDate      *time.Time // 下单时间止（不含）
+	DeliveryDate *time.Time // 期望送达日期
+	OrderNo      string     // 按订单号模糊匹配
+}
+
+// CheckoutRequest 定义了购物车结算的请求体。
+// Token 由前端在进入结算页时生成，重复提交同一 Token 只会返回首次结算的结果；ItemIDs 为空时结算整个购物车。
+// DeliveryDate 为期望送达日期，为空时默认次日送达。
+type CheckoutRequest struct {
+	Token        string `json:"token" binding:"required,max=64"`
+	ItemIDs      []uint `json:"itemIds"`
+	DeliveryDate string `json:"deliveryDate" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// CheckoutResult 定义了结算结果
+type CheckoutResult struct {
+	CheckoutID  uint       `json:"checkoutId"`
+	Token       string     `json:"token"`
+	TotalAmount float64    `json:"totalAmount"`
+	Orders      []OrdOrder `json:"orders"`
+	Duplicate   bool       `json:"duplicate"` // 是否为重复提交，重复提交时返回首次结算生成的订单
+}
+
+// UpdateOrderStatusRequest 定义了变更订单状态的请求体
+type UpdateOrderStatusRequest struct {
+	Status int8   `json:"status" binding:"required"`
+	Remark string `json:"remark" binding:"max=255"`
+}
+
+// OrderDetail 定义了订单详情的返回结构，附带明细和状态变更记录
+type OrderDetail struct {
+	Order *OrdOrder     `json:"order"`
+	Logs  []OrdOrderLog `json:"logs"`
+}
+
+// AcceptOrderRequest 定义了供应商接单的请求体
+type AcceptOrderRequest struct {
+	Remark string `json:"remark" binding:"max=255"`
+}
+
+// RejectOrderRequest 定义了供应商拒单的请求体，拒单原因必填
+type RejectOrderRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// AdjustOrderItem 定义了对一条订单明细的缺货/少发标记。
+// 缺货时数量为 0；少发时数量须小于下单数量；恢复正常时数量恢复为下单数量，均可不传 Quantity。
+type AdjustOrderItem struct {
+	ItemID       uint   `json:"itemId" binding:"required"`
+	FulfilStatus *int8  `json:"fulfilStatus" binding:"required,oneof=0 1 2"`
+	Quantity     int    `json:"quantity" binding:"min=0"`
+	Remark       string `json:"remark" binding:"max=255"`
+}
+
+// AdjustOrderItemsRequest 定义了批量标记订单明细缺货/少发的请求体
+type AdjustOrderItemsRequest struct {
+	Items []AdjustOrderItem `json:"items" binding:"required,min=1,dive"`
+}
+
+// OrderStatusCount 定义了某状态下的订单数，用于订单列表的状态页签
+type OrderStatusCount struct {
+	Status int8  `json:"status"`
+	Count  int64 `json:"count"`
+}
@@ -0,0 +1,64 @@
+// server/internal/model/picking.go
+package model
+
+import "time"
+
+// 分拣单状态
+const (
+	PickListPending int8 = 0 // 待分拣
+	PickListPicking int8 = 1 // 分拣中
+	PickListDone    int8 = 2 // 已完成
+)
+
+// CreatePickListRequest 定义了生成分拣单的请求体，汇总该送达日期下所有已接单且尚未分拣的订单
+type CreatePickListRequest struct {
+	DeliveryDate string `json:"deliveryDate" binding:"required,datetime=2006-01-02"`
+	PickerID     uint   `json:"pickerId"` // 可选，生成时直接指派分拣员
+}
+
+// AssignPickerRequest 定义了指派分拣员的请求体
+type AssignPickerRequest struct {
+	StaffID uint `json:"staffId" binding:"required"`
+}
+
+// PickLineRequest 定义了登记分拣行实拣数量的请求体
+type PickLineRequest struct {
+	PickedQty *int `json:"pickedQty" binding:"required,min=0"`
+}
+
+// PickListFilter 定义了分拣单列表的筛选条件
+type PickListFilter struct {
+	SupplierID   uint
+	DeliveryDate *time.Time
+	Status       *int8
+}
+
+// PickDestination 定义了分拣单中某商品发往一个食堂（或学校）的分拣行
+type PickDestination struct {
+	LineID    uint       `json:"lineId"`
+	DestID    uint       `json:"destId"`
+	DestName  string     `json:"destName"`
+	Quantity  int        `json:"quantity"`
+	PickedQty int        `json:"pickedQty"`
+	Picked    bool       `json:"picked"`
+	PickedAt  *time.Time `json:"pickedAt"`
+}
+
+// PickProductGroup 定义了分拣单中同一商品的汇总
+type PickProductGroup struct {
+	ProductID    uint              `json:"productId"`
+	Name         string            `json:"name"`
+	Specs        string            `json:"specs"`
+	Quantity     int               `json:"quantity"`  // 应拣总数
+	PickedQty    int               `json:"pickedQty"` // 实拣总数
+	Destinations []PickDestination `json:"destinations"`
+}
+
+// PickListDetail 定义了分拣单详情，按商品汇总后再按食堂（或学校）分行
+type PickListDetail struct {
+	PickList    *OrdPickList       `json:"pickList"`
+	PickerName  string             `json:"pickerName"`
+	Products    []PickProductGroup `json:"products"`
+	LineCount   int                `json:"lineCount"`
+	PickedCount int                `json:"pickedCount"`
+}
@@ -0,0 +1,78 @@
+// server/internal/model/product.go
+package model
+
+// 商品审核状态
+const (
+	ProductAuditPending  int8 = 0 // 待审
+	ProductAuditApproved int8 = 1 // 通过
+	ProductAuditRejected int8 = 2 // 驳回
+)
+
+// 商品来源
+const (
+	ProductSourcePlatform int8 = 1 // 平台下发
+	ProductSourceSchool   int8 = 2 // 学校自建
+	ProductSourceSupplier int8 = 3 // 供应商上传
+)
+
+// 商品审核记录动作
+const (
+	ProductAuditActionSubmit   int8 = 1 // 提交
+	ProductAuditActionResubmit int8 = 2 // 重新提交
+	ProductAuditActionApprove  int8 = 3 // 通过
+	ProductAuditActionReject   int8 = 4 // 驳回
+	ProductAuditActionMerge    int8 = 5 // 作为重复商品被合并
+	ProductAuditActionEdit     int8 = 6 // 待审核期间修改
+)
+
+// SubmitProductRequest 定义了供应商提交新商品(SPU)的请求体
+type SubmitProductRequest struct {
+	CategoryID uint   `json:"categoryId" binding:"required"`
+	Name       string `json:"name" binding:"required,max=100"`
+	Image      string `json:"image" binding:"required"`
+	Specs      string `json:"specs" binding:"required,max=100"`
+	Unit       string `json:"unit" binding:"required,max=20"`
+}
+
+// RejectProductRequest 定义了驳回商品的请求体，驳回原因必填
+type RejectProductRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// UpdateProductCertsRequest 定义了上传商品三证资质的请求体
+type UpdateProductCertsRequest struct {
+	Certs []string `json:"certs" binding:"required,min=1,dive,required"`
+}
+
+// BatchProductIDsRequest 定义了批量操作商品的请求体
+type BatchProductIDsRequest struct {
+	IDs []uint `json:"ids" binding:"required,min=1"`
+}
+
+// MergeProductRequest 定义了合并重复商品的请求体，TargetID 为保留的商品
+type MergeProductRequest struct {
+	TargetID uint `json:"targetId" binding:"required"`
+}
+
+// ProductListFilter 定义了商品列表的筛选条件
+type ProductListFilter struct {
+	SchoolID    uint   // 监管学校，为 0 时不限制
+	CreatorID   uint   // 创建者组织ID，为 0 时不限制
+	CategoryID  uint   // 分类，为 0 时不限制
+	AuditStatus *int8  // 审核状态
+	IsListed    *bool  // 上架状态
+	Keyword     string // 按名称模糊匹配
+}
+
+// ProductDuplicate 定义了疑似重复商品及其相似度
+type ProductDuplicate struct {
+	Product    ScmProduct `json:"product"`
+	Similarity float64    `json:"similarity"` // 0-1，越大越相似
+}
+
+// ProductDetail 定义了商品详情的返回结构，附带审核历史；待审商品还会附带疑似重复商品
+type ProductDetail struct {
+	Product    *ScmProduct        `json:"product"`
+	Audits     []ScmProductAudit  `json:"audits"`
+	Duplicates []ProductDuplicate `json:"duplicates"`
+}
@@ -0,0 +1,40 @@
+// server/internal/model/quote.go
+package model
+
+import "time"
+
+// CreateQuoteRequest 定义了供应商为商品报价的请求体
+type CreateQuoteRequest struct {
+	ProductID uint    `json:"productId" binding:"required"`
+	Price     float64 `json:"price" binding:"required,gt=0"`
+}
+
+// UpdateQuotePriceRequest 定义了供应商调整报价的请求体。
+// EffectiveFrom 为空或早于当前时间时立即生效，否则作为预约调价在指定时间生效。
+type UpdateQuotePriceRequest struct {
+	Price         float64    `json:"price" binding:"required,gt=0"`
+	EffectiveFrom *time.Time `json:"effectiveFrom"`
+}
+
+// UpdateQuoteStatusRequest 定义了启用/停用报价的请求体
+type UpdateQuoteStatusRequest struct {
+	IsEnabled *bool `json:"isEnabled" binding:"required"`
+}
+
+// QuoteListItem 定义了报价列表的返回结构，附带商品基本信息和待生效的预约调价
+type QuoteListItem struct {
+	ScmProductQuote
+	ProductName  string     `json:"productName"`
+	Specs        string     `json:"specs"`
+	Unit         string     `json:"unit"`
+	PendingPrice *float64   `json:"pendingPrice" gorm:"-"`
+	PendingFrom  *time.Time `json:"pendingFrom" gorm:"-"`
+}
+
+// PriceHistoryFilter 定义了价格历史查询的筛选条件
+type PriceHistoryFilter struct {
+	ProductID  uint       // 商品ID，必填
+	SupplierID uint       // 报价人，为 0 时返回所有供应商
+	StartDate  *time.Time // 生效时间下限
+	EndDate    *time.Time // 生效时间上限（不含）
+}
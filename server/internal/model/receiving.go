@@ -0,0 +1,37 @@
+// server/internal/model/receiving.go
+package model
+
+// 订单明细的验收状态
+const (
+	ReceivePending  int8 = 0 // 待验收
+	ReceiveAccepted int8 = 1 // 已验收
+	ReceiveRejected int8 = 2 // 拒收
+)
+
+// ReceiveOrderLine 定义了一条订单明细的验收结果。
+// 未出现在请求中的明细视为按发货数量全部收货；ReceivedQty 为空时同样按发货数量计。
+// 拒收时实收数量为 0，须填写原因。
+type ReceiveOrderLine struct {
+	ItemID       uint     `json:"itemId" binding:"required"`
+	ReceivedQty  *int     `json:"receivedQty" binding:"omitempty,min=0,max=99999"`
+	ActualWeight *float64 `json:"actualWeight" binding:"omitempty,min=0"`
+	Rejected     bool     `json:"rejected"`
+	Reason       string   `json:"reason" binding:"max=255"`
+}
+
+// ReceiveOrderRequest 定义了买家验收订单的请求体。
+// Vouchers 和 Signature 为通过上传接口（purpose=receipt）得到的文件 Key。
+type ReceiveOrderRequest struct {
+	Lines        []ReceiveOrderLine `json:"lines" binding:"dive"`
+	Vouchers     []string           `json:"vouchers" binding:"required,min=1,max=9"`
+	Signature    string             `json:"signature" binding:"required,max=255"`
+	ReceiverName string             `json:"receiverName" binding:"required,max=50"`
+	Remark       string             `json:"remark" binding:"max=255"`
+}
+
+// ReceiptDetail 定义了订单验收结果，附带验收差异生成的售后和调价记录
+type ReceiptDetail struct {
+	Order       *OrdOrder            `json:"order"`
+	AfterSales  []OrdAfterSale       `json:"afterSales"`
+	Adjustments []OrdPriceAdjustment `json:"adjustments"`
+}
@@ -8,6 +8,8 @@ const (
 	RoleSchoolStaff   = "school_staff"
 	RoleSupplierAdmin = "supplier_admin"
 	RoleSupplierStaff = "supplier_staff"
-	// 根据需要可以添加更多角色，例如食堂管理员等
-	// RoleCanteenAdmin = "canteen_admin"
+	RoleCanteenAdmin  = "canteen_admin"
+	RoleCanteenStaff  = "canteen_staff"
+	RoleMerchantAdmin = "merchant_admin"
+	RoleMerchantStaff = "merchant_staff"
 )
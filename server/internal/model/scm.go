@@ -11,6 +11,9 @@ type ScmCategory struct {
 	ParentID uint   `gorm:"not null;default:0"`
 	Icon     string `gorm:"type:varchar(255);comment:图标"`
 	Sort     int    `gorm:"not null;default:0"`
+	// 批次检测报告要求，对所有子分类生效
+	RequireReport   bool `gorm:"not null;default:false;comment:报价需附带有效检测报告"`
+	ReportValidDays int  `gorm:"not null;default:0;comment:检测报告有效天数 0:使用默认值"`
 }
 
 func (ScmCategory) TableName() string {
@@ -30,14 +33,56 @@ type ScmProduct struct {
 	CreatorID   uint      `gorm:"default:0;comment:创建者ID"`
 	StaticCerts string    `gorm:"type:json;comment:三证资质"`
 	AuditStatus int8      `gorm:"not null;default:0;comment:0:待审 1:通过 2:驳回"`
+	SpecsLocked bool      `gorm:"not null;default:false;comment:规格锁定(审核通过后不可改)"`
+	AuditReason string    `gorm:"type:varchar(255);comment:最近一次驳回原因"`
 	IsListed    bool      `gorm:"not null;default:false;comment:上架状态"`
+	MergedInto  uint      `gorm:"not null;default:0;index;comment:被合并到的商品ID，0表示未合并"`
+	MasterID    uint      `gorm:"not null;default:0;index;comment:来源平台标准商品ID，0表示非平台下发"`
 	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+
+	ImageURLs *ImageURLs `gorm:"-"` // 标准图各尺寸的下载地址，仅用于接口返回
 }
 
 func (ScmProduct) TableName() string {
 	return "scm_products"
 }
 
+// ScmMasterProduct 平台标准商品库，下发到学校后生成学校商品库中的副本
+type ScmMasterProduct struct {
+	ID          uint      `gorm:"primarykey"`
+	CategoryID  uint      `gorm:"not null;comment:分类"`
+	Name        string    `gorm:"type:varchar(100);not null;comment:商品名称"`
+	Image       string    `gorm:"type:varchar(255);comment:标准图"`
+	Specs       string    `gorm:"type:varchar(100);not null;comment:固定规格"`
+	Unit        string    `gorm:"type:varchar(20);not null;comment:计量单位"`
+	StaticCerts string    `gorm:"type:json;comment:三证资质"`
+	IsEnabled   bool      `gorm:"not null;default:true;comment:是否可下发"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+func (ScmMasterProduct) TableName() string {
+	return "scm_master_products"
+}
+
+// ScmProductAudit 商品审核记录表
+type ScmProductAudit struct {
+	ID            uint      `gorm:"primarykey"`
+	ProductID     uint      `gorm:"not null;index;comment:商品ID"`
+	Action        int8      `gorm:"not null;comment:1:提交 2:重新提交 3:通过 4:驳回 5:合并 6:修改"`
+	FromStatus    int8      `gorm:"not null;comment:变更前审核状态"`
+	ToStatus      int8      `gorm:"not null;comment:变更后审核状态"`
+	Reason        string    `gorm:"type:varchar(255);comment:驳回原因/备注"`
+	OperatorID    uint      `gorm:"not null;comment:操作人ID"`
+	OperatorOrgID uint      `gorm:"not null;comment:操作人组织ID"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (ScmProductAudit) TableName() string {
+	return "scm_product_audits"
+}
+
 // ScmProductQuote 供应商报价 SKU
 type ScmProductQuote struct {
 	ID           uint      `gorm:"primarykey"`
@@ -46,21 +91,65 @@ type ScmProductQuote struct {
 	Price        float64   `gorm:"type:decimal(10,2);not null;comment:报价"`
 	BatchReports string    `gorm:"type:json;comment:批次报告"`
 	IsEnabled    bool      `gorm:"not null;default:true;comment:供货开关"`
+	ReviewStatus int8      `gorm:"not null;default:0;comment:价格复核 0:无需复核 1:待复核 2:复核通过 3:复核驳回"`
+	ReviewReason string    `gorm:"type:varchar(255);comment:复核驳回原因"`
+	MergedInto   uint      `gorm:"not null;default:0;comment:商品合并时被合并到的报价ID"`
 	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
 }
 
 func (ScmProductQuote) TableName() string {
 	return "scm_product_quotes"
 }
 
+// ScmGuidePrice 学校为商品设定的指导价
+type ScmGuidePrice struct {
+	ID         uint      `gorm:"primarykey"`
+	SchoolID   uint      `gorm:"not null;index;comment:监管学校"`
+	ProductID  uint      `gorm:"not null;uniqueIndex;comment:关联SPU"`
+	GuidePrice float64   `gorm:"type:decimal(10,2);not null;comment:指导价"`
+	Deviation  float64   `gorm:"type:decimal(5,2);not null;default:0;comment:允许偏离百分比"`
+	OverMode   int8      `gorm:"not null;default:1;comment:超出上限处理 1:标记复核 2:直接拒绝"`
+	OperatorID uint      `gorm:"not null;comment:操作人ID"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (ScmGuidePrice) TableName() string {
+	return "scm_guide_prices"
+}
+
+// ScmQuotePriceHistory 报价价格变更历史表，未生效的记录即为预约调价
+type ScmQuotePriceHistory struct {
+	ID            uint      `gorm:"primarykey"`
+	QuoteID       uint      `gorm:"not null;index;comment:报价ID"`
+	ProductID     uint      `gorm:"not null;index:idx_prod_effective;comment:关联SPU"`
+	SupplierID    uint      `gorm:"not null;comment:报价人"`
+	OldPrice      float64   `gorm:"type:decimal(10,2);not null;default:0;comment:调整前价格"`
+	Price         float64   `gorm:"type:decimal(10,2);not null;comment:调整后价格"`
+	EffectiveFrom time.Time `gorm:"not null;index:idx_prod_effective;comment:生效时间"`
+	Applied       bool      `gorm:"not null;default:false;index;comment:是否已生效"`
+	OperatorID    uint      `gorm:"not null;comment:操作人ID"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (ScmQuotePriceHistory) TableName() string {
+	return "scm_quote_price_histories"
+}
+
 // ScmSupplierStaff 供应商员工表
 type ScmSupplierStaff struct {
-	ID         uint   `gorm:"primarykey"`
-	SupplierID uint   `gorm:"not null;comment:所属供应商"`
-	Name       string `gorm:"type:varchar(50);not null;comment:姓名"`
-	Mobile     string `gorm:"type:varchar(20);not null"`
-	RoleType   int8   `gorm:"not null;comment:1:司机 2:分拣"`
-	HealthCert string `gorm:"type:varchar(255);comment:健康证图"`
+	ID               uint       `gorm:"primarykey"`
+	SupplierID       uint       `gorm:"not null;index;comment:所属供应商"`
+	Name             string     `gorm:"type:varchar(50);not null;comment:姓名"`
+	Mobile           string     `gorm:"type:varchar(20);not null"`
+	RoleType         int8       `gorm:"not null;comment:1:司机 2:分拣"`
+	HealthCert       string     `gorm:"type:varchar(255);comment:健康证图"`
+	HealthCertNo     string     `gorm:"type:varchar(50);comment:健康证编号"`
+	HealthCertExpiry *time.Time `gorm:"type:date;index;comment:健康证有效期至"`
+	IsEnabled        bool       `gorm:"default:true"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 func (ScmSupplierStaff) TableName() string {
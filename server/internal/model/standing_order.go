@@ -0,0 +1,53 @@
+// server/internal/model/standing_order.go
+package model
+
+// 定期订单模板状态
+const (
+	StandingOrderActive int8 = 0 // 启用
+	StandingOrderPaused int8 = 1 // 暂停
+)
+
+// 定期订单执行结果
+const (
+	StandingRunOrdered int8 = 1 // 已下单
+	StandingRunSkipped int8 = 2 // 按跳过日期跳过
+	StandingRunFailed  int8 = 3 // 失败，模板中没有可下单的商品
+)
+
+// 定期订单的提前下单天数范围
+const (
+	StandingOrderMinLeadDays = 1
+	StandingOrderMaxLeadDays = 7
+)
+
+// StandingOrderItemRequest 定义了定期订单模板中的一个商品
+type StandingOrderItemRequest struct {
+	QuoteID  uint `json:"quoteId" binding:"required"`
+	Quantity int  `json:"quantity" binding:"required,min=1,max=99999"`
+}
+
+// SaveStandingOrderRequest 定义了创建或修改定期订单模板的请求体。
+// 送达日按 Weekdays（0 表示周日）和 Dates 两种方式指定，至少指定一种；所有商品须来自同一供应商。
+// StartDate 为空时默认从最早可自动下单的日期（今天加提前天数）开始。
+type SaveStandingOrderRequest struct {
+	Name       string                     `json:"name" binding:"required,max=50"`
+	SupplierID uint                       `json:"supplierId" binding:"required"`
+	Items      []StandingOrderItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+	Weekdays   []int                      `json:"weekdays" binding:"max=7,dive,min=0,max=6"`
+	Dates      []string                   `json:"dates" binding:"max=100,dive,datetime=2006-01-02"`
+	LeadDays   int                        `json:"leadDays" binding:"omitempty,min=1,max=7"`
+	StartDate  string                     `json:"startDate" binding:"omitempty,datetime=2006-01-02"`
+	EndDate    string                     `json:"endDate" binding:"omitempty,datetime=2006-01-02"`
+}
+
+// SkipDatesRequest 定义了设置定期订单跳过日期的请求体，会整体替换原有的跳过日期
+type SkipDatesRequest struct {
+	Dates []string `json:"dates" binding:"max=100,dive,datetime=2006-01-02"`
+}
+
+// StandingOrderRunResult 定义了一轮定期订单调度的执行结果
+type StandingOrderRunResult struct {
+	Ordered int `json:"ordered"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+}
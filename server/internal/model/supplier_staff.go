@@ -0,0 +1,51 @@
+// server/internal/model/supplier_staff.go
+package model
+
+import "time"
+
+// 供应商员工岗位
+const (
+	StaffRoleDriver int8 = 1 // 司机
+	StaffRolePicker int8 = 2 // 分拣
+)
+
+// 健康证状态
+const (
+	HealthCertValid    int8 = 0 // 有效
+	HealthCertExpiring int8 = 1 // 即将过期
+	HealthCertExpired  int8 = 2 // 已过期
+	HealthCertMissing  int8 = 3 // 未登记有效期
+)
+
+// HealthCertWarnDays 健康证到期前多少天开始预警
+const HealthCertWarnDays = 30
+
+// SupplierStaffRequest 定义了新增/修改供应商员工的请求体，健康证有效期格式为 2006-01-02
+type SupplierStaffRequest struct {
+	Name             string `json:"name" binding:"required,max=50"`
+	Mobile           string `json:"mobile" binding:"required,max=20"`
+	RoleType         int8   `json:"roleType" binding:"required,oneof=1 2"`
+	HealthCert       string `json:"healthCert" binding:"required,max=255"`
+	HealthCertNo     string `json:"healthCertNo" binding:"required,max=50"`
+	HealthCertExpiry string `json:"healthCertExpiry" binding:"required,datetime=2006-01-02"`
+}
+
+// UpdateStaffStatusRequest 定义了启用/停用员工的请求体
+type UpdateStaffStatusRequest struct {
+	IsEnabled *bool `json:"isEnabled" binding:"required"`
+}
+
+// SupplierStaffFilter 定义了员工列表的筛选条件
+type SupplierStaffFilter struct {
+	SupplierID uint
+	RoleType   int8       // 为 0 时不限制
+	Keyword    string     // 按姓名或手机号模糊匹配
+	ExpireBy   *time.Time // 只返回健康证在该日期前到期或未登记有效期的员工
+}
+
+// SupplierStaffItem 定义了员工列表的返回结构，附带健康证状态
+type SupplierStaffItem struct {
+	ScmSupplierStaff
+	CertStatus int8 `json:"certStatus"`
+	DaysLeft   *int `json:"daysLeft"` // 距健康证到期的天数，已过期为负数，未登记有效期时为空
+}
@@ -99,3 +99,90 @@ type SysBanner struct {
 func (SysBanner) TableName() string {
 	return "sys_banners"
 }
+
+// SysExportJob 后台导出任务表
+type SysExportJob struct {
+	ID         uint       `gorm:"primarykey"`
+	UserID     uint       `gorm:"not null;index;comment:发起人ID"`
+	OrgID      uint       `gorm:"not null;comment:发起人组织ID"`
+	Source     string     `gorm:"type:varchar(50);not null;comment:导出数据源"`
+	Format     string     `gorm:"type:varchar(10);not null;comment:文件格式"`
+	Params     string     `gorm:"type:text;comment:导出参数"`
+	Status     int8       `gorm:"not null;default:10;comment:10:排队 20:处理中 30:完成 40:失败"`
+	FileName   string     `gorm:"type:varchar(100);comment:下载文件名"`
+	FilePath   string     `gorm:"type:varchar(255);comment:文件存放路径" json:"-"`
+	RowCount   int        `gorm:"not null;default:0;comment:导出行数"`
+	ErrorMsg   string     `gorm:"type:varchar(255);comment:失败原因"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime"`
+	FinishedAt *time.Time `gorm:"comment:完成时间"`
+}
+
+func (SysExportJob) TableName() string {
+	return "sys_export_jobs"
+}
+
+// SysFile 上传文件表，相同内容的文件只保存一份
+type SysFile struct {
+	ID           uint       `gorm:"primarykey"`
+	Hash         string     `gorm:"type:char(64);not null;uniqueIndex;comment:存储内容SHA-256"`
+	Key          string     `gorm:"type:varchar(191);not null;uniqueIndex;comment:存储路径"`
+	Size         int64      `gorm:"not null;comment:字节数"`
+	MimeType     string     `gorm:"type:varchar(100);not null"`
+	OriginalName string     `gorm:"type:varchar(255);comment:首次上传时的文件名"`
+	Width        int        `gorm:"not null;default:0;comment:图片宽度"`
+	Height       int        `gorm:"not null;default:0;comment:图片高度"`
+	VariantExt   string     `gorm:"type:varchar(10);comment:缩略图扩展名，为空表示没有缩略图"`
+	CapturedAt   *time.Time `gorm:"comment:拍摄时间，只为收货凭证保留"`
+	UploaderID   uint       `gorm:"not null;index;comment:首次上传人"`
+	OrgID        uint       `gorm:"not null;comment:首次上传人组织ID"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime;index"`
+}
+
+func (SysFile) TableName() string {
+	return "sys_files"
+}
+
+// SysFileUpload 文件上传记录，同一文件被多个组织上传时每个组织各记一条
+type SysFileUpload struct {
+	ID         uint      `gorm:"primarykey"`
+	FileID     uint      `gorm:"not null;uniqueIndex:idx_file_org;comment:文件ID"`
+	OrgID      uint      `gorm:"not null;uniqueIndex:idx_file_org;comment:上传人组织ID"`
+	UploaderID uint      `gorm:"not null;comment:该组织首次上传人"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (SysFileUpload) TableName() string {
+	return "sys_file_uploads"
+}
+
+// SysFileReference 文件引用表，业务数据保存文件 Key 时登记，用于孤儿文件清理和下载权限校验
+type SysFileReference struct {
+	ID        uint      `gorm:"primarykey"`
+	FileKey   string    `gorm:"type:varchar(191);not null;index;uniqueIndex:idx_source_record_key;comment:文件存储路径"`
+	Source    string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_source_record_key;comment:引用文件的业务表"`
+	RecordID  uint      `gorm:"not null;uniqueIndex:idx_source_record_key;comment:引用文件的业务记录ID"`
+	OwnerKind string    `gorm:"type:varchar(20);not null;comment:业务数据类别，见 FileOwner*"`
+	OwnerID   uint      `gorm:"not null;comment:用于校验下载权限的业务数据ID"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (SysFileReference) TableName() string {
+	return "sys_file_references"
+}
+
+// SysNotification 站内通知，发给组织内的所有用户
+type SysNotification struct {
+	ID        uint       `gorm:"primarykey"`
+	OrgID     uint       `gorm:"not null;index:idx_org_read;comment:接收组织ID"`
+	Category  string     `gorm:"type:varchar(50);not null;comment:通知类别"`
+	Title     string     `gorm:"type:varchar(100);not null;comment:标题"`
+	Content   string     `gorm:"type:text;comment:内容"`
+	RefID     uint       `gorm:"not null;default:0;comment:关联业务ID"`
+	IsRead    bool       `gorm:"not null;default:false;index:idx_org_read;comment:是否已读"`
+	ReadAt    *time.Time `gorm:"comment:阅读时间"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+}
+
+func (SysNotification) TableName() string {
+	return "sys_notifications"
+}
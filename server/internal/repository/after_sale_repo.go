@@ -0,0 +1,26 @@
+// server/internal/repository/after_sale_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IAfterSaleRepository 定义了售后数据仓库的接口
+type IAfterSaleRepository interface {
+	GetDB() *gorm.DB
+	Create(afterSale *model.OrdAfterSale) error
+	GetByID(id uint) (*model.OrdAfterSale, error)
+	// UpdateStatus 仅当售后仍处于 from 状态时将其变更为 to 状态，并同时更新 fields 中的字段；返回受影响的行数
+	UpdateStatus(id uint, from, to int8, fields map[string]interface{}) (int64, error)
+	// List 按筛选条件分页列出售后记录，按ID倒序
+	List(filter model.AfterSaleFilter, page, pageSize int) ([]model.OrdAfterSale, int64, error)
+	// ListByOrder 列出订单的全部售后记录，按ID顺序
+	ListByOrder(orderID uint) ([]model.OrdAfterSale, error)
+	// SumActiveByItem 统计订单明细已占用的售后金额：已通过的按实际退款金额，待审核和申诉中的按申请金额；
+	// excludeID 不为 0 时不统计该售后本身
+	SumActiveByItem(orderItemID, excludeID uint) (float64, error)
+	// CountOpenByOrder 统计订单尚未处理完毕（待审核、申诉中）的售后数
+	CountOpenByOrder(orderID uint) (int64, error)
+}
@@ -0,0 +1,98 @@
+// server/internal/repository/after_sale_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type afterSaleRepository struct {
+	db *gorm.DB
+}
+
+// NewAfterSaleRepository 创建一个新的 afterSaleRepository 实例
+func NewAfterSaleRepository(db *gorm.DB) IAfterSaleRepository {
+	return &afterSaleRepository{db: db}
+}
+
+func (r *afterSaleRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *afterSaleRepository) Create(afterSale *model.OrdAfterSale) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(afterSale).Error; err != nil {
+			return err
+		}
+		owner := model.FileOwner{Kind: model.FileOwnerOrder, ID: afterSale.OrderID}
+		return saveFileRefs(tx, afterSale.TableName(), afterSale.ID, owner, afterSale.Evidences)
+	})
+}
+
+func (r *afterSaleRepository) GetByID(id uint) (*model.OrdAfterSale, error) {
+	var afterSale model.OrdAfterSale
+	err := r.db.First(&afterSale, id).Error
+	return &afterSale, err
+}
+
+func (r *afterSaleRepository) UpdateStatus(id uint, from, to int8, fields map[string]interface{}) (int64, error) {
+	updates := map[string]interface{}{"status": to}
+	for k, v := range fields {
+		updates[k] = v
+	}
+	result := r.db.Model(&model.OrdAfterSale{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+func (r *afterSaleRepository) List(filter model.AfterSaleFilter, page, pageSize int) ([]model.OrdAfterSale, int64, error) {
+	var afterSales []model.OrdAfterSale
+	var total int64
+
+	query := r.db.Model(&model.OrdAfterSale{})
+	if filter.MerchantIDs != nil {
+		query = query.Where("merchant_id IN ?", filter.MerchantIDs)
+	}
+	if filter.SupplierID != 0 {
+		query = query.Where("supplier_id = ?", filter.SupplierID)
+	}
+	if filter.OrderID != 0 {
+		query = query.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&afterSales).Error
+	return afterSales, total, err
+}
+
+func (r *afterSaleRepository) ListByOrder(orderID uint) ([]model.OrdAfterSale, error) {
+	var afterSales []model.OrdAfterSale
+	err := r.db.Where("order_id = ?", orderID).Order("id ASC").Find(&afterSales).Error
+	return afterSales, err
+}
+
+func (r *afterSaleRepository) SumActiveByItem(orderItemID, excludeID uint) (float64, error) {
+	var sum float64
+	err := r.db.Model(&model.OrdAfterSale{}).
+		Select("COALESCE(SUM(CASE WHEN status = ? THEN refund_amount ELSE apply_amount END), 0)", model.AfterSaleApproved).
+		Where("order_item_id = ? AND status IN ? AND id <> ?", orderItemID,
+			[]int8{model.AfterSalePending, model.AfterSaleApproved, model.AfterSaleDisputed}, excludeID).
+		Scan(&sum).Error
+	return sum, err
+}
+
+func (r *afterSaleRepository) CountOpenByOrder(orderID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.OrdAfterSale{}).
+		Where("order_id = ? AND status IN ?", orderID, []int8{model.AfterSalePending, model.AfterSaleDisputed}).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,25 @@
+// server/internal/repository/analytics_repo.go
+package repository
+
+import (
+	"server/internal/model"
+)
+
+// IAnalyticsRepository 定义了采购统计数据仓库的接口，统计只包含未被拒单或取消的订单
+type IAnalyticsRepository interface {
+	Totals(filter model.AnalyticsFilter) (*model.AnalyticsTotals, error)
+	// RefundTotal 统计期间内订单的售后退款金额
+	RefundTotal(filter model.AnalyticsFilter) (float64, error)
+	// Trend 按日、周或月汇总订单数和金额，时间段按先后排序
+	Trend(filter model.AnalyticsFilter, granularity string) ([]model.SpendPoint, error)
+	// ByCategory 按商品分类汇总明细金额，按金额倒序，ID 为分类ID
+	ByCategory(filter model.AnalyticsFilter) ([]model.SpendGroup, error)
+	// BySupplier 按供应商汇总订单金额，按金额倒序，ID 为供应商组织ID，不含名称
+	BySupplier(filter model.AnalyticsFilter) ([]model.SpendGroup, error)
+	// ByMerchant 按买家汇总订单金额，按金额倒序，ID 为买家组织ID，不含名称
+	ByMerchant(filter model.AnalyticsFilter) ([]model.SpendGroup, error)
+	// TopProducts 列出采购金额最高的商品
+	TopProducts(filter model.AnalyticsFilter, limit int) ([]model.TopProduct, error)
+	// PricesPaid 统计采购金额最高的商品的实际均价，并附带指导价
+	PricesPaid(filter model.AnalyticsFilter, limit int) ([]model.PricePaid, error)
+}
@@ -0,0 +1,129 @@
+// server/internal/repository/analytics_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// analyticsPeriodFormats 各统计粒度对应的 DATE_FORMAT 格式，周按 ISO 周计算
+var analyticsPeriodFormats = map[string]string{
+	model.AnalyticsByDay:   "%Y-%m-%d",
+	model.AnalyticsByWeek:  "%x-W%v",
+	model.AnalyticsByMonth: "%Y-%m",
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository 创建一个新的 analyticsRepository 实例
+func NewAnalyticsRepository(db *gorm.DB) IAnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// orders 返回符合筛选条件的有效订单查询，订单表别名为 o
+func (r *analyticsRepository) orders(filter model.AnalyticsFilter) *gorm.DB {
+	query := r.db.Table("ord_orders AS o").
+		Where("o.created_at >= ? AND o.created_at < ?", filter.StartDate, filter.EndDate).
+		Where("o.status NOT IN ?", []int8{model.OrderStatusRejected, model.OrderStatusCancelled})
+	if filter.MerchantIDs != nil {
+		query = query.Where("o.merchant_id IN ?", filter.MerchantIDs)
+	}
+	if filter.SupplierID != 0 {
+		query = query.Where("o.supplier_id = ?", filter.SupplierID)
+	}
+	return query
+}
+
+func (r *analyticsRepository) Totals(filter model.AnalyticsFilter) (*model.AnalyticsTotals, error) {
+	var totals model.AnalyticsTotals
+	err := r.orders(filter).
+		Select("COUNT(*) AS order_count, COALESCE(SUM(o.total_amount), 0) AS amount").
+		Scan(&totals).Error
+	return &totals, err
+}
+
+func (r *analyticsRepository) RefundTotal(filter model.AnalyticsFilter) (float64, error) {
+	var total float64
+	err := r.orders(filter).
+		Joins("JOIN fin_bills AS b ON b.order_id = o.id").
+		Where("b.bill_type = ?", model.BillTypeRefund).
+		Select("COALESCE(SUM(b.amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *analyticsRepository) Trend(filter model.AnalyticsFilter, granularity string) ([]model.SpendPoint, error) {
+	var points []model.SpendPoint
+	format := analyticsPeriodFormats[granularity]
+	err := r.orders(filter).
+		Select("DATE_FORMAT(o.created_at, ?) AS period, COUNT(*) AS order_count, SUM(o.total_amount) AS amount", format).
+		Group("period").
+		Order("period ASC").
+		Scan(&points).Error
+	return points, err
+}
+
+func (r *analyticsRepository) ByCategory(filter model.AnalyticsFilter) ([]model.SpendGroup, error) {
+	var groups []model.SpendGroup
+	err := r.orders(filter).
+		Joins("JOIN ord_order_items AS i ON i.order_id = o.id").
+		Joins("LEFT JOIN scm_products AS p ON p.id = i.product_id").
+		Joins("LEFT JOIN scm_categories AS c ON c.id = p.category_id").
+		Select("COALESCE(p.category_id, 0) AS id, COALESCE(MAX(c.name), '') AS name, " +
+			"COUNT(DISTINCT o.id) AS order_count, SUM(i.amount) AS amount").
+		Group("COALESCE(p.category_id, 0)").
+		Order("amount DESC").
+		Scan(&groups).Error
+	return groups, err
+}
+
+func (r *analyticsRepository) BySupplier(filter model.AnalyticsFilter) ([]model.SpendGroup, error) {
+	var groups []model.SpendGroup
+	err := r.orders(filter).
+		Select("o.supplier_id AS id, COUNT(*) AS order_count, SUM(o.total_amount) AS amount").
+		Group("o.supplier_id").
+		Order("amount DESC").
+		Scan(&groups).Error
+	return groups, err
+}
+
+func (r *analyticsRepository) ByMerchant(filter model.AnalyticsFilter) ([]model.SpendGroup, error) {
+	var groups []model.SpendGroup
+	err := r.orders(filter).
+		Select("o.merchant_id AS id, COUNT(*) AS order_count, SUM(o.total_amount) AS amount").
+		Group("o.merchant_id").
+		Order("amount DESC").
+		Scan(&groups).Error
+	return groups, err
+}
+
+func (r *analyticsRepository) TopProducts(filter model.AnalyticsFilter, limit int) ([]model.TopProduct, error) {
+	var products []model.TopProduct
+	err := r.orders(filter).
+		Joins("JOIN ord_order_items AS i ON i.order_id = o.id").
+		Select("i.product_id, MAX(i.snap_name) AS name, MAX(i.snap_specs) AS specs, " +
+			"SUM(i.quantity) AS quantity, SUM(i.amount) AS amount").
+		Group("i.product_id").
+		Order("amount DESC").
+		Limit(limit).
+		Scan(&products).Error
+	return products, err
+}
+
+func (r *analyticsRepository) PricesPaid(filter model.AnalyticsFilter, limit int) ([]model.PricePaid, error) {
+	var prices []model.PricePaid
+	err := r.orders(filter).
+		Joins("JOIN ord_order_items AS i ON i.order_id = o.id").
+		Joins("LEFT JOIN scm_guide_prices AS g ON g.product_id = i.product_id").
+		Select("i.product_id, MAX(i.snap_name) AS name, MAX(i.snap_specs) AS specs, " +
+			"SUM(i.quantity) AS quantity, SUM(i.amount) AS amount, MAX(g.guide_price) AS guide_price").
+		Where("i.quantity > 0").
+		Group("i.product_id").
+		Order("amount DESC").
+		Limit(limit).
+		Scan(&prices).Error
+	return prices, err
+}
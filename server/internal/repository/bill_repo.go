@@ -0,0 +1,16 @@
+// server/internal/repository/bill_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IBillRepository 定义了资金流水数据仓库的接口
+type IBillRepository interface {
+	GetDB() *gorm.DB
+	Create(bill *model.FinBill) error
+	// ListByOrder 列出订单的全部资金流水，按ID顺序
+	ListByOrder(orderID uint) ([]model.FinBill, error)
+}
@@ -0,0 +1,31 @@
+// server/internal/repository/bill_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type billRepository struct {
+	db *gorm.DB
+}
+
+// NewBillRepository 创建一个新的 billRepository 实例
+func NewBillRepository(db *gorm.DB) IBillRepository {
+	return &billRepository{db: db}
+}
+
+func (r *billRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *billRepository) Create(bill *model.FinBill) error {
+	return r.db.Create(bill).Error
+}
+
+func (r *billRepository) ListByOrder(orderID uint) ([]model.FinBill, error) {
+	var bills []model.FinBill
+	err := r.db.Where("order_id = ?", orderID).Order("id ASC").Find(&bills).Error
+	return bills, err
+}
@@ -0,0 +1,33 @@
+// server/internal/repository/cart_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ICartRepository 定义了购物车数据仓库的接口
+type ICartRepository interface {
+	GetDB() *gorm.DB
+	Create(cart *model.OrdCart) error
+	GetByID(id uint) (*model.OrdCart, error)
+	// GetByMerchantAndQuote 查找买家购物车中某报价的条目
+	GetByMerchantAndQuote(merchantID, quoteID uint) (*model.OrdCart, error)
+	// ListByQuote 列出所有买家购物车中某报价的条目
+	ListByQuote(quoteID uint) ([]model.OrdCart, error)
+	// CountByMerchant 统计买家购物车中的条目数
+	CountByMerchant(merchantID uint) (int64, error)
+	// LockByMerchant 在事务中锁定买家购物车的所有条目，防止并发结算重复下单
+	LockByMerchant(merchantID uint) ([]model.OrdCart, error)
+	// ListLinesByMerchant 列出买家购物车的所有条目，附带报价、商品和供应商的实时信息，按加入时间排列
+	ListLinesByMerchant(merchantID uint) ([]model.CartLineRow, error)
+	Update(cart *model.OrdCart) error
+	// UpdateAddedPrice 更新条目加入时的单价，用于买家确认价格变化
+	UpdateAddedPrice(id uint, price float64) error
+	Delete(id uint) error
+	// DeleteByMerchant 清空买家的购物车
+	DeleteByMerchant(merchantID uint) error
+	// DeleteByIDs 删除买家购物车中的指定条目
+	DeleteByIDs(merchantID uint, ids []uint) error
+}
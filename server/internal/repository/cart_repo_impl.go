@@ -0,0 +1,102 @@
+// server/internal/repository/cart_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cartRepository struct {
+	db *gorm.DB
+}
+
+// NewCartRepository 创建一个新的 cartRepository 实例
+func NewCartRepository(db *gorm.DB) ICartRepository {
+	return &cartRepository{db: db}
+}
+
+func (r *cartRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *cartRepository) Create(cart *model.OrdCart) error {
+	return r.db.Create(cart).Error
+}
+
+func (r *cartRepository) GetByID(id uint) (*model.OrdCart, error) {
+	var cart model.OrdCart
+	err := r.db.First(&cart, id).Error
+	return &cart, err
+}
+
+func (r *cartRepository) GetByMerchantAndQuote(merchantID, quoteID uint) (*model.OrdCart, error) {
+	var cart model.OrdCart
+	err := r.db.Where("merchant_id = ? AND quote_id = ?", merchantID, quoteID).First(&cart).Error
+	return &cart, err
+}
+
+func (r *cartRepository) ListByQuote(quoteID uint) ([]model.OrdCart, error) {
+	var carts []model.OrdCart
+	err := r.db.Where("quote_id = ?", quoteID).Find(&carts).Error
+	return carts, err
+}
+
+func (r *cartRepository) CountByMerchant(merchantID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.OrdCart{}).Where("merchant_id = ?", merchantID).Count(&count).Error
+	return count, err
+}
+
+func (r *cartRepository) LockByMerchant(merchantID uint) ([]model.OrdCart, error) {
+	var carts []model.OrdCart
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("merchant_id = ?", merchantID).Find(&carts).Error
+	return carts, err
+}
+
+func (r *cartRepository) ListLinesByMerchant(merchantID uint) ([]model.CartLineRow, error) {
+	var rows []model.CartLineRow
+	// 报价或商品可能已被删除，使用 LEFT JOIN 保留购物车条目，并将缺失的字段置为零值
+	err := r.db.Table("ord_carts AS c").
+		Select(`c.id, c.quote_id, c.quantity, c.added_price,
+			COALESCE(q.product_id, 0) AS product_id, COALESCE(q.supplier_id, 0) AS supplier_id,
+			COALESCE(o.name, '') AS supplier_name, COALESCE(o.is_enabled, 0) AS supplier_enabled,
+			COALESCE(q.price, 0) AS price, COALESCE(q.is_enabled, 0) AS quote_enabled,
+			COALESCE(q.review_status, 0) AS review_status, COALESCE(q.batch_reports, '') AS batch_reports,
+			COALESCE(p.name, '') AS product_name, COALESCE(p.specs, '') AS specs, COALESCE(p.unit, '') AS unit,
+			COALESCE(p.image, '') AS image, COALESCE(p.category_id, 0) AS category_id,
+			COALESCE(p.school_id, 0) AS school_id, COALESCE(p.audit_status, 0) AS audit_status,
+			COALESCE(p.is_listed, 0) AS is_listed, COALESCE(p.merged_into, 0) AS merged_into`).
+		Joins("LEFT JOIN scm_product_quotes AS q ON q.id = c.quote_id").
+		Joins("LEFT JOIN scm_products AS p ON p.id = q.product_id").
+		Joins("LEFT JOIN sys_organizations AS o ON o.id = q.supplier_id").
+		Where("c.merchant_id = ?", merchantID).
+		Order("c.created_at ASC, c.id ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *cartRepository) Update(cart *model.OrdCart) error {
+	return r.db.Save(cart).Error
+}
+
+func (r *cartRepository) UpdateAddedPrice(id uint, price float64) error {
+	return r.db.Model(&model.OrdCart{}).Where("id = ?", id).Update("added_price", price).Error
+}
+
+func (r *cartRepository) Delete(id uint) error {
+	return r.db.Delete(&model.OrdCart{}, id).Error
+}
+
+func (r *cartRepository) DeleteByMerchant(merchantID uint) error {
+	return r.db.Where("merchant_id = ?", merchantID).Delete(&model.OrdCart{}).Error
+}
+
+func (r *cartRepository) DeleteByIDs(merchantID uint, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Where("merchant_id = ? AND id IN ?", merchantID, ids).Delete(&model.OrdCart{}).Error
+}
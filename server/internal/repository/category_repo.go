@@ -0,0 +1,26 @@
+// server/internal/repository/category_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ICategoryRepository 定义商品分类仓库接口
+type ICategoryRepository interface {
+	// GetDB 返回底层的 gorm.DB 实例，用于事务等高级操作
+	GetDB() *gorm.DB
+	Create(category *model.ScmCategory) error
+	GetByID(id uint) (*model.ScmCategory, error)
+	Update(category *model.ScmCategory) error
+	Delete(id uint) error
+	// ListAll 获取全部分类，按排序值和ID升序
+	ListAll() ([]model.ScmCategory, error)
+	// CountChildren 统计直接子分类的数量
+	CountChildren(id uint) (int64, error)
+	// CountProducts 统计直接挂在该分类下的商品数量
+	CountProducts(id uint) (int64, error)
+	// ExistsSiblingName 判断同一父级下是否已存在同名分类，excludeID 用于排除自身
+	ExistsSiblingName(parentID uint, name string, excludeID uint) (bool, error)
+}
@@ -0,0 +1,86 @@
+// server/internal/repository/category_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository 创建一个新的 categoryRepository 实例
+func NewCategoryRepository(db *gorm.DB) ICategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *categoryRepository) Create(category *model.ScmCategory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+		return saveCategoryFileRefs(tx, category)
+	})
+}
+
+func (r *categoryRepository) GetByID(id uint) (*model.ScmCategory, error) {
+	var category model.ScmCategory
+	err := r.db.First(&category, id).Error
+	return &category, err
+}
+
+func (r *categoryRepository) Update(category *model.ScmCategory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(category).Error; err != nil {
+			return err
+		}
+		return saveCategoryFileRefs(tx, category)
+	})
+}
+
+func (r *categoryRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.ScmCategory{}, id).Error; err != nil {
+			return err
+		}
+		return deleteFileRefs(tx, model.ScmCategory{}.TableName(), id)
+	})
+}
+
+// saveCategoryFileRefs 登记分类图标引用的文件
+func saveCategoryFileRefs(db *gorm.DB, category *model.ScmCategory) error {
+	owner := model.FileOwner{Kind: model.FileOwnerPublic, ID: category.ID}
+	return saveFileRefs(db, category.TableName(), category.ID, owner, category.Icon)
+}
+
+func (r *categoryRepository) ListAll() ([]model.ScmCategory, error) {
+	var categories []model.ScmCategory
+	err := r.db.Order("sort ASC, id ASC").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) CountChildren(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ScmCategory{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *categoryRepository) CountProducts(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ScmProduct{}).Where("category_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *categoryRepository) ExistsSiblingName(parentID uint, name string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.ScmCategory{}).
+		Where("parent_id = ? AND name = ? AND id <> ?", parentID, name, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
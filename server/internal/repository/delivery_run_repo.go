@@ -0,0 +1,21 @@
+// server/internal/repository/delivery_run_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IDeliveryRunRepository 定义了配送趟次数据仓库的接口
+type IDeliveryRunRepository interface {
+	GetDB() *gorm.DB
+	Create(run *model.OrdDeliveryRun) error
+	GetByID(id uint) (*model.OrdDeliveryRun, error)
+	// Lock 以 FOR UPDATE 方式读取配送趟次，调用方负责开启事务
+	Lock(id uint) (*model.OrdDeliveryRun, error)
+	Update(run *model.OrdDeliveryRun) error
+	Delete(id uint) error
+	// List 按筛选条件分页列出配送趟次，按送达日期和ID倒序
+	List(filter model.DeliveryRunFilter, page, pageSize int) ([]model.OrdDeliveryRun, int64, error)
+}
@@ -0,0 +1,69 @@
+// server/internal/repository/delivery_run_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type deliveryRunRepository struct {
+	db *gorm.DB
+}
+
+// NewDeliveryRunRepository 创建一个新的 deliveryRunRepository 实例
+func NewDeliveryRunRepository(db *gorm.DB) IDeliveryRunRepository {
+	return &deliveryRunRepository{db: db}
+}
+
+func (r *deliveryRunRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *deliveryRunRepository) Create(run *model.OrdDeliveryRun) error {
+	return r.db.Create(run).Error
+}
+
+func (r *deliveryRunRepository) GetByID(id uint) (*model.OrdDeliveryRun, error) {
+	var run model.OrdDeliveryRun
+	err := r.db.First(&run, id).Error
+	return &run, err
+}
+
+func (r *deliveryRunRepository) Lock(id uint) (*model.OrdDeliveryRun, error) {
+	var run model.OrdDeliveryRun
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&run, id).Error
+	return &run, err
+}
+
+func (r *deliveryRunRepository) Update(run *model.OrdDeliveryRun) error {
+	return r.db.Save(run).Error
+}
+
+func (r *deliveryRunRepository) Delete(id uint) error {
+	return r.db.Delete(&model.OrdDeliveryRun{}, id).Error
+}
+
+func (r *deliveryRunRepository) List(filter model.DeliveryRunFilter, page, pageSize int) ([]model.OrdDeliveryRun, int64, error) {
+	var runs []model.OrdDeliveryRun
+	var total int64
+
+	query := r.db.Model(&model.OrdDeliveryRun{}).Where("supplier_id = ?", filter.SupplierID)
+	if filter.DriverID != 0 {
+		query = query.Where("driver_id = ?", filter.DriverID)
+	}
+	if filter.DeliveryDate != nil {
+		query = query.Where("delivery_date = ?", filter.DeliveryDate.Format("2006-01-02"))
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).Order("delivery_date DESC, id DESC").Find(&runs).Error
+	return runs, total, err
+}
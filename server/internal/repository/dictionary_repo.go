@@ -0,0 +1,10 @@
+// server/internal/repository/dictionary_repo.go
+package repository
+
+import "server/internal/model"
+
+// IDictionaryRepository 定义数据字典仓库接口
+type IDictionaryRepository interface {
+	// ListByCodes 获取指定编码下的所有字典项
+	ListByCodes(codes []string) ([]model.SysDictionary, error)
+}
@@ -0,0 +1,27 @@
+// server/internal/repository/dictionary_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type dictionaryRepository struct {
+	db *gorm.DB
+}
+
+// NewDictionaryRepository 创建一个新的 dictionaryRepository 实例
+func NewDictionaryRepository(db *gorm.DB) IDictionaryRepository {
+	return &dictionaryRepository{db: db}
+}
+
+// ListByCodes 获取指定编码下的所有字典项，按排序字段升序
+func (r *dictionaryRepository) ListByCodes(codes []string) ([]model.SysDictionary, error) {
+	var items []model.SysDictionary
+	if len(codes) == 0 {
+		return items, nil
+	}
+	err := r.db.Where("dict_code IN ?", codes).Order("sort ASC, id ASC").Find(&items).Error
+	return items, err
+}
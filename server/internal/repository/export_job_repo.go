@@ -0,0 +1,12 @@
+// server/internal/repository/export_job_repo.go
+package repository
+
+import "server/internal/model"
+
+// IExportJobRepository 定义导出任务仓库接口
+type IExportJobRepository interface {
+	Create(job *model.SysExportJob) error
+	GetByID(id uint) (*model.SysExportJob, error)
+	Update(job *model.SysExportJob) error
+	ListByUser(userID uint, page, pageSize int) ([]model.SysExportJob, int64, error)
+}
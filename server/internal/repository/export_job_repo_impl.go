@@ -0,0 +1,51 @@
+// server/internal/repository/export_job_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type exportJobRepository struct {
+	db *gorm.DB
+}
+
+// NewExportJobRepository 创建一个新的 exportJobRepository 实例
+func NewExportJobRepository(db *gorm.DB) IExportJobRepository {
+	return &exportJobRepository{db: db}
+}
+
+// Create 创建一条导出任务
+func (r *exportJobRepository) Create(job *model.SysExportJob) error {
+	return r.db.Create(job).Error
+}
+
+// GetByID 根据ID获取导出任务
+func (r *exportJobRepository) GetByID(id uint) (*model.SysExportJob, error) {
+	var job model.SysExportJob
+	err := r.db.First(&job, id).Error
+	return &job, err
+}
+
+// Update 更新导出任务
+func (r *exportJobRepository) Update(job *model.SysExportJob) error {
+	return r.db.Save(job).Error
+}
+
+// ListByUser 分页列出某个用户发起的导出任务
+func (r *exportJobRepository) ListByUser(userID uint, page, pageSize int) ([]model.SysExportJob, int64, error) {
+	var jobs []model.SysExportJob
+	var total int64
+
+	query := r.db.Model(&model.SysExportJob{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
@@ -0,0 +1,35 @@
+// server/internal/repository/file_repo.go
+package repository
+
+import (
+	"time"
+
+	"server/internal/model"
+)
+
+// IFileRepository 定义了上传文件数据仓库的接口
+type IFileRepository interface {
+	Create(file *model.SysFile) error
+	GetByHash(hash string) (*model.SysFile, error)
+	GetByKey(key string) (*model.SysFile, error)
+	// ListByKeys 按存储路径批量查找文件
+	ListByKeys(keys []string) ([]model.SysFile, error)
+	Update(file *model.SysFile) error
+	Delete(id uint) error
+	// ListCreatedBefore 按ID顺序分批列出在指定时间之前上传的文件
+	ListCreatedBefore(before time.Time, afterID uint, limit int) ([]model.SysFile, error)
+	// CreateUpload 登记组织上传了文件，同一组织重复上传时忽略
+	CreateUpload(upload *model.SysFileUpload) error
+	// HasUpload 判断组织是否上传过该文件
+	HasUpload(fileID, orgID uint) (bool, error)
+	// CountUploaded 统计 keys 中由组织上传过的文件数
+	CountUploaded(keys []string, orgID uint) (int64, error)
+	// IsReferenced 判断文件是否被业务数据引用
+	IsReferenced(key string) (bool, error)
+	// ListOwners 列出引用了文件的业务数据，用于校验下载权限
+	ListOwners(key string) ([]model.FileOwner, error)
+	// BackfillUploads 为没有上传记录的文件按首次上传人补登记
+	BackfillUploads() error
+	// BackfillReferences 引用表为空时扫描业务表中的文件字段补登记引用
+	BackfillReferences() error
+}
@@ -0,0 +1,227 @@
+// server/internal/repository/file_repo_impl.go
+package repository
+
+import (
+	"regexp"
+	"time"
+
+	"server/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fileKeyPattern 匹配字段值中的文件 Key，字段中可能保存 Key 本身、包含 Key 的地址或 JSON 数组
+var fileKeyPattern = regexp.MustCompile(`files/[0-9a-f]{2}/[0-9a-f]{64}\.[a-z]+`)
+
+// legacyFileColumn 描述一个保存图片或附件的业务字段，只用于补登记引用表之前已保存的数据。
+// OwnerID 为取得对应业务数据ID的 SQL 表达式
+type legacyFileColumn struct {
+	Table   string
+	Column  string
+	Owner   string
+	OwnerID string
+}
+
+// legacyFileColumns 列出引入引用表之前所有保存图片或附件的业务字段
+var legacyFileColumns = []legacyFileColumn{
+	{"scm_categories", "icon", model.FileOwnerPublic, "id"},
+	{"scm_products", "image", model.FileOwnerPublic, "id"},
+	{"scm_products", "static_certs", model.FileOwnerPublic, "id"},
+	{"scm_master_products", "image", model.FileOwnerPublic, "id"},
+	{"scm_master_products", "static_certs", model.FileOwnerPublic, "id"},
+	{"scm_product_quotes", "batch_reports", model.FileOwnerQuote, "id"},
+	{"scm_supplier_staffs", "health_cert", model.FileOwnerSupplier, "supplier_id"},
+	{"ord_orders", "receipt_vouchers", model.FileOwnerOrder, "id"},
+	{"ord_orders", "receipt_signature", model.FileOwnerOrder, "id"},
+	{"ord_after_sales", "evidences", model.FileOwnerOrder, "order_id"},
+	{"ord_item_traces", "cert_snapshot", model.FileOwnerOrder, "(SELECT i.order_id FROM ord_order_items AS i WHERE i.id = ord_item_traces.order_item_id)"},
+	{"ord_item_traces", "qc_cert_image", model.FileOwnerOrder, "(SELECT i.order_id FROM ord_order_items AS i WHERE i.id = ord_item_traces.order_item_id)"},
+	{"sys_users", "avatar", model.FileOwnerPublic, "id"},
+	{"sys_banners", "image_url", model.FileOwnerPublic, "id"},
+}
+
+// fileOwnerScanLimit 查找文件引用方时最多返回的记录数
+const fileOwnerScanLimit = 20
+
+// fileBackfillBatch 补登记引用时每批扫描的记录数
+const fileBackfillBatch = 500
+
+type fileRepository struct {
+	db *gorm.DB
+}
+
+// NewFileRepository 创建一个新的 fileRepository 实例
+func NewFileRepository(db *gorm.DB) IFileRepository {
+	return &fileRepository{db: db}
+}
+
+func (r *fileRepository) Create(file *model.SysFile) error {
+	return r.db.Create(file).Error
+}
+
+func (r *fileRepository) GetByHash(hash string) (*model.SysFile, error) {
+	var file model.SysFile
+	err := r.db.Where("hash = ?", hash).First(&file).Error
+	return &file, err
+}
+
+func (r *fileRepository) GetByKey(key string) (*model.SysFile, error) {
+	var file model.SysFile
+	err := r.db.Where("`key` = ?", key).First(&file).Error
+	return &file, err
+}
+
+func (r *fileRepository) ListByKeys(keys []string) ([]model.SysFile, error) {
+	var files []model.SysFile
+	if len(keys) == 0 {
+		return files, nil
+	}
+	err := r.db.Where("`key` IN ?", keys).Find(&files).Error
+	return files, err
+}
+
+func (r *fileRepository) Update(file *model.SysFile) error {
+	return r.db.Save(file).Error
+}
+
+func (r *fileRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("file_id = ?", id).Delete(&model.SysFileUpload{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.SysFile{}, id).Error
+	})
+}
+
+func (r *fileRepository) ListCreatedBefore(before time.Time, afterID uint, limit int) ([]model.SysFile, error) {
+	var files []model.SysFile
+	err := r.db.Where("created_at < ? AND id > ?", before, afterID).
+		Order("id ASC").Limit(limit).Find(&files).Error
+	return files, err
+}
+
+func (r *fileRepository) CreateUpload(upload *model.SysFileUpload) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(upload).Error
+}
+
+func (r *fileRepository) HasUpload(fileID, orgID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.SysFileUpload{}).
+		Where("file_id = ? AND org_id = ?", fileID, orgID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *fileRepository) CountUploaded(keys []string, orgID uint) (int64, error) {
+	var count int64
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	err := r.db.Model(&model.SysFile{}).
+		Joins("JOIN sys_file_uploads AS u ON u.file_id = sys_files.id").
+		Where("sys_files.`key` IN ? AND u.org_id = ?", keys, orgID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *fileRepository) IsReferenced(key string) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.SysFileReference{}).Where("file_key = ?", key).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *fileRepository) ListOwners(key string) ([]model.FileOwner, error) {
+	var owners []model.FileOwner
+	err := r.db.Model(&model.SysFileReference{}).
+		Select("DISTINCT owner_kind AS kind, owner_id AS id").
+		Where("file_key = ?", key).
+		Limit(fileOwnerScanLimit).
+		Scan(&owners).Error
+	return owners, err
+}
+
+func (r *fileRepository) BackfillUploads() error {
+	return r.db.Exec(`INSERT INTO sys_file_uploads (file_id, org_id, uploader_id, created_at)
+		SELECT f.id, f.org_id, f.uploader_id, f.created_at FROM sys_files AS f
+		WHERE NOT EXISTS (SELECT 1 FROM sys_file_uploads AS u WHERE u.file_id = f.id AND u.org_id = f.org_id)`).Error
+}
+
+func (r *fileRepository) BackfillReferences() error {
+	var count int64
+	if err := r.db.Model(&model.SysFileReference{}).Limit(1).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	type row struct {
+		ID      uint
+		OwnerID uint
+		Value   string
+	}
+	for _, col := range legacyFileColumns {
+		var rows []row
+		err := r.db.Table(col.Table).
+			Select("id, "+col.OwnerID+" AS owner_id, "+col.Column+" AS value").
+			Where(col.Column+" LIKE ?", "%files/%").
+			FindInBatches(&rows, fileBackfillBatch, func(tx *gorm.DB, batch int) error {
+				var refs []model.SysFileReference
+				for _, rw := range rows {
+					for _, key := range fileKeysIn(rw.Value) {
+						refs = append(refs, model.SysFileReference{
+							FileKey: key, Source: col.Table, RecordID: rw.ID, OwnerKind: col.Owner, OwnerID: rw.OwnerID,
+						})
+					}
+				}
+				if len(refs) == 0 {
+					return nil
+				}
+				return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&refs).Error
+			}).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveFileRefs 以 values 中出现的文件 Key 替换业务记录登记的文件引用，
+// 保存图片或附件字段的仓库方法都要在同一事务中调用，否则文件会被当作孤儿文件清理
+func saveFileRefs(db *gorm.DB, source string, recordID uint, owner model.FileOwner, values ...string) error {
+	if err := deleteFileRefs(db, source, recordID); err != nil {
+		return err
+	}
+	keys := fileKeysIn(values...)
+	if len(keys) == 0 {
+		return nil
+	}
+	refs := make([]model.SysFileReference, len(keys))
+	for i, key := range keys {
+		refs[i] = model.SysFileReference{
+			FileKey: key, Source: source, RecordID: recordID, OwnerKind: owner.Kind, OwnerID: owner.ID,
+		}
+	}
+	return db.Create(&refs).Error
+}
+
+// deleteFileRefs 删除业务记录登记的全部文件引用
+func deleteFileRefs(db *gorm.DB, source string, recordID uint) error {
+	return db.Where("source = ? AND record_id = ?", source, recordID).Delete(&model.SysFileReference{}).Error
+}
+
+// fileKeysIn 提取字段值中出现的文件 Key 并去重
+func fileKeysIn(values ...string) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, v := range values {
+		for _, key := range fileKeyPattern.FindAllString(v, -1) {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
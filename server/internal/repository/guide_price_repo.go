@@ -0,0 +1,22 @@
+// server/internal/repository/guide_price_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IGuidePriceRepository 定义了指导价数据仓库的接口
+type IGuidePriceRepository interface {
+	GetDB() *gorm.DB
+	// GetByProduct 查找商品的指导价
+	GetByProduct(productID uint) (*model.ScmGuidePrice, error)
+	// Save 新建或更新指导价
+	Save(guide *model.ScmGuidePrice) error
+	DeleteByProduct(productID uint) error
+	// ListBySchool 分页列出学校设置的指导价，附带商品名称、规格和单位
+	ListBySchool(schoolID uint, keyword string, page, pageSize int) ([]model.GuidePriceItem, int64, error)
+	// ListQuoteBand 分页列出报价及其对应的指导价
+	ListQuoteBand(filter model.QuoteBandFilter, page, pageSize int) ([]model.QuoteBandItem, int64, error)
+}
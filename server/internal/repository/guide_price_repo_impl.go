@@ -0,0 +1,86 @@
+// server/internal/repository/guide_price_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type guidePriceRepository struct {
+	db *gorm.DB
+}
+
+// NewGuidePriceRepository 创建一个新的 guidePriceRepository 实例
+func NewGuidePriceRepository(db *gorm.DB) IGuidePriceRepository {
+	return &guidePriceRepository{db: db}
+}
+
+func (r *guidePriceRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *guidePriceRepository) GetByProduct(productID uint) (*model.ScmGuidePrice, error) {
+	var guide model.ScmGuidePrice
+	err := r.db.Where("product_id = ?", productID).First(&guide).Error
+	return &guide, err
+}
+
+func (r *guidePriceRepository) Save(guide *model.ScmGuidePrice) error {
+	return r.db.Save(guide).Error
+}
+
+func (r *guidePriceRepository) DeleteByProduct(productID uint) error {
+	return r.db.Where("product_id = ?", productID).Delete(&model.ScmGuidePrice{}).Error
+}
+
+func (r *guidePriceRepository) ListBySchool(schoolID uint, keyword string, page, pageSize int) ([]model.GuidePriceItem, int64, error) {
+	var items []model.GuidePriceItem
+	var total int64
+
+	query := r.db.Table("scm_guide_prices AS g").
+		Joins("JOIN scm_products AS p ON p.id = g.product_id").
+		Where("g.school_id = ?", schoolID)
+	if keyword != "" {
+		query = query.Where("p.name LIKE ?", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Select("g.*, p.name AS product_name, p.specs, p.unit").
+		Offset(offset).Limit(pageSize).Order("g.id DESC").Scan(&items).Error
+	return items, total, err
+}
+
+func (r *guidePriceRepository) ListQuoteBand(filter model.QuoteBandFilter, page, pageSize int) ([]model.QuoteBandItem, int64, error) {
+	var items []model.QuoteBandItem
+	var total int64
+
+	query := r.db.Table("scm_product_quotes AS q").
+		Joins("JOIN scm_products AS p ON p.id = q.product_id").
+		Joins("JOIN sys_organizations AS o ON o.id = q.supplier_id").
+		Joins("LEFT JOIN scm_guide_prices AS g ON g.product_id = q.product_id")
+	if filter.SchoolID != 0 {
+		query = query.Where("p.school_id = ?", filter.SchoolID)
+	}
+	if filter.ReviewStatus != nil {
+		query = query.Where("q.review_status = ?", *filter.ReviewStatus)
+	}
+	if filter.OutOfBand {
+		query = query.Where("g.id IS NOT NULL AND q.is_enabled = ?", true).
+			Where("q.price > g.guide_price * (1 + g.deviation / 100) OR q.price < g.guide_price * (1 - g.deviation / 100)")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Select("q.id AS quote_id, q.product_id, p.name AS product_name, p.specs, p.unit, " +
+		"q.supplier_id, o.name AS supplier_name, q.price, q.is_enabled, q.review_status, g.guide_price, g.deviation").
+		Offset(offset).Limit(pageSize).Order("q.updated_at DESC, q.id DESC").Scan(&items).Error
+	return items, total, err
+}
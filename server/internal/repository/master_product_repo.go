@@ -0,0 +1,18 @@
+// server/internal/repository/master_product_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IMasterProductRepository 定义了平台标准商品数据仓库的接口
+type IMasterProductRepository interface {
+	GetDB() *gorm.DB
+	Create(master *model.ScmMasterProduct) error
+	GetByID(id uint) (*model.ScmMasterProduct, error)
+	ListByIDs(ids []uint) ([]model.ScmMasterProduct, error)
+	Update(master *model.ScmMasterProduct) error
+	List(filter model.MasterProductListFilter, page, pageSize int) ([]model.ScmMasterProduct, int64, error)
+}
@@ -0,0 +1,83 @@
+// server/internal/repository/master_product_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type masterProductRepository struct {
+	db *gorm.DB
+}
+
+// NewMasterProductRepository 创建一个新的 masterProductRepository 实例
+func NewMasterProductRepository(db *gorm.DB) IMasterProductRepository {
+	return &masterProductRepository{db: db}
+}
+
+func (r *masterProductRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *masterProductRepository) Create(master *model.ScmMasterProduct) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(master).Error; err != nil {
+			return err
+		}
+		return saveMasterFileRefs(tx, master)
+	})
+}
+
+func (r *masterProductRepository) GetByID(id uint) (*model.ScmMasterProduct, error) {
+	var master model.ScmMasterProduct
+	err := r.db.First(&master, id).Error
+	return &master, err
+}
+
+func (r *masterProductRepository) ListByIDs(ids []uint) ([]model.ScmMasterProduct, error) {
+	var masters []model.ScmMasterProduct
+	err := r.db.Where("id IN ?", ids).Order("id ASC").Find(&masters).Error
+	return masters, err
+}
+
+func (r *masterProductRepository) Update(master *model.ScmMasterProduct) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(master).Error; err != nil {
+			return err
+		}
+		return saveMasterFileRefs(tx, master)
+	})
+}
+
+// saveMasterFileRefs 登记标准商品图片和资质引用的文件
+func saveMasterFileRefs(db *gorm.DB, master *model.ScmMasterProduct) error {
+	owner := model.FileOwner{Kind: model.FileOwnerPublic, ID: master.ID}
+	return saveFileRefs(db, master.TableName(), master.ID, owner, master.Image, master.StaticCerts)
+}
+
+func (r *masterProductRepository) List(filter model.MasterProductListFilter, page, pageSize int) ([]model.ScmMasterProduct, int64, error) {
+	var masters []model.ScmMasterProduct
+	var total int64
+
+	query := r.db.Model(&model.ScmMasterProduct{})
+	if filter.CategoryID != 0 {
+		query = query.Where("category_id = ?", filter.CategoryID)
+	}
+	if filter.IsEnabled != nil {
+		query = query.Where("is_enabled = ?", *filter.IsEnabled)
+	}
+	if filter.Keyword != "" {
+		query = query.Where("name LIKE ?", "%"+filter.Keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&masters).Error; err != nil {
+		return nil, 0, err
+	}
+	return masters, total, nil
+}
@@ -0,0 +1,18 @@
+// server/internal/repository/notification_repo.go
+package repository
+
+import (
+	"server/internal/model"
+)
+
+// INotificationRepository 定义了站内通知数据仓库的接口
+type INotificationRepository interface {
+	Create(notification *model.SysNotification) error
+	// List 分页列出组织的通知，按ID倒序
+	List(orgID uint, unreadOnly bool, page, pageSize int) ([]model.SysNotification, int64, error)
+	CountUnread(orgID uint) (int64, error)
+	// MarkRead 将组织的一条通知标记为已读
+	MarkRead(orgID, id uint) error
+	// MarkAllRead 将组织的全部通知标记为已读
+	MarkAllRead(orgID uint) error
+}
@@ -0,0 +1,60 @@
+// server/internal/repository/notification_repo_impl.go
+package repository
+
+import (
+	"time"
+
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository 创建一个新的 notificationRepository 实例
+func NewNotificationRepository(db *gorm.DB) INotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(notification *model.SysNotification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r *notificationRepository) List(orgID uint, unreadOnly bool, page, pageSize int) ([]model.SysNotification, int64, error) {
+	var notifications []model.SysNotification
+	var total int64
+
+	query := r.db.Model(&model.SysNotification{}).Where("org_id = ?", orgID)
+	if unreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *notificationRepository) CountUnread(orgID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.SysNotification{}).
+		Where("org_id = ? AND is_read = ?", orgID, false).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(orgID, id uint) error {
+	return r.db.Model(&model.SysNotification{}).
+		Where("id = ? AND org_id = ? AND is_read = ?", id, orgID, false).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()}).Error
+}
+
+func (r *notificationRepository) MarkAllRead(orgID uint) error {
+	return r.db.Model(&model.SysNotification{}).
+		Where("org_id = ? AND is_read = ?", orgID, false).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()}).Error
+}
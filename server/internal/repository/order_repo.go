@@ -0,0 +1,66 @@
+// server/internal/repository/order_repo.go
+package repository
+
+import (
+	"time"
+
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IOrderRepository 定义订单仓库接口
+type IOrderRepository interface {
+	// GetDB 返回底层的 gorm.DB 实例，用于事务等高级操作
+	GetDB() *gorm.DB
+	// List 按筛选条件分页列出订单
+	List(filter model.OrderListFilter, page, pageSize int) ([]model.OrdOrder, int64, error)
+	// CountByStatus 按状态统计符合筛选条件的订单数，忽略筛选条件中的状态
+	CountByStatus(filter model.OrderListFilter) ([]model.OrderStatusCount, error)
+	GetByID(id uint) (*model.OrdOrder, error)
+	ListByIDs(ids []uint) ([]model.OrdOrder, error)
+	CreateOrder(order *model.OrdOrder) error
+	// UpdateStatus 仅当订单仍处于 from 状态时将其变更为 to 状态，并同时更新 fields 中的字段；返回受影响的行数
+	UpdateStatus(id uint, from, to int8, fields map[string]interface{}) (int64, error)
+	CreateLog(log *model.OrdOrderLog) error
+	// ListLogs 按时间顺序列出订单的状态变更记录
+	ListLogs(orderID uint) ([]model.OrdOrderLog, error)
+	CreateItems(items []model.OrdOrderItem) error
+	UpdateItem(item *model.OrdOrderItem) error
+	// LockItem 以 FOR UPDATE 方式读取订单明细，调用方负责开启事务
+	LockItem(id uint) (*model.OrdOrderItem, error)
+	// Lock 以 FOR UPDATE 方式读取订单，调用方负责开启事务
+	Lock(id uint) (*model.OrdOrder, error)
+	// ListForPicking 以 FOR UPDATE 方式列出供应商某送达日期下处于指定状态的所有订单，调用方负责开启事务
+	ListForPicking(supplierID uint, deliveryDate time.Time, status int8) ([]model.OrdOrder, error)
+	// ListByDeliveryRun 列出配送趟次内的所有订单
+	ListByDeliveryRun(runID uint) ([]model.OrdOrder, error)
+	// SetDeliveryRun 将一批仍属于 fromRunID 的订单改为属于 toRunID；返回受影响的行数
+	SetDeliveryRun(orderIDs []uint, fromRunID, toRunID uint) (int64, error)
+	// ListTracesByItems 列出一批订单明细的溯源记录
+	ListTracesByItems(itemIDs []uint) ([]model.OrdItemTrace, error)
+	CreateTrace(trace *model.OrdItemTrace) error
+	// UpdateTraceDriver 更新一批订单明细溯源记录上的司机
+	UpdateTraceDriver(itemIDs []uint, driverID uint) error
+	// SetItemsPickLine 将一批订单明细归入分拣行
+	SetItemsPickLine(itemIDs []uint, pickLineID uint) error
+	// UpdateTotal 仅当订单仍处于 status 状态时更新订单总金额；返回受影响的行数
+	UpdateTotal(id uint, status int8, total float64) (int64, error)
+	// UpdateReceipt 保存订单的验收信息（收货凭证、签名、验收人、验收时间）及验收后的总金额
+	UpdateReceipt(order *model.OrdOrder) error
+	CreatePriceAdjustment(adjustment *model.OrdPriceAdjustment) error
+	// ListPriceAdjustments 列出订单的调价记录
+	ListPriceAdjustments(orderID uint) ([]model.OrdPriceAdjustment, error)
+	// ListByCheckout 列出一次结算拆分出的所有订单
+	ListByCheckout(checkoutID uint) ([]model.OrdOrder, error)
+	// ListItemsByOrders 列出一批订单的明细
+	ListItemsByOrders(orderIDs []uint) ([]model.OrdOrderItem, error)
+	// ListItemsByPickLines 列出归入一批分拣行的订单明细，按ID排列
+	ListItemsByPickLines(lineIDs []uint) ([]model.OrdOrderItem, error)
+	CreateCheckout(checkout *model.OrdCheckout) error
+	// GetCheckoutByToken 按结算令牌查找买家的结算批次
+	GetCheckoutByToken(merchantID uint, token string) (*model.OrdCheckout, error)
+	UpdateCheckout(checkout *model.OrdCheckout) error
+	// SupplierPerformance 统计一批供应商自 since 起的订单履约情况
+	SupplierPerformance(supplierIDs []uint, since time.Time) (map[uint]*model.SupplierPerformance, error)
+}
@@ -0,0 +1,305 @@
+// server/internal/repository/order_repo_impl.go
+package repository
+
+import (
+	"time"
+
+	"server/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type orderRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository 创建一个新的 orderRepository 实例
+func NewOrderRepository(db *gorm.DB) IOrderRepository {
+	return &orderRepository{db: db}
+}
+
+func (r *orderRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+// List 按筛选条件分页列出订单，按下单时间倒序
+func (r *orderRepository) List(filter model.OrderListFilter, page, pageSize int) ([]model.OrdOrder, int64, error) {
+	var orders []model.OrdOrder
+	var total int64
+
+	query := r.filtered(filter)
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+func (r *orderRepository) GetByID(id uint) (*model.OrdOrder, error) {
+	var order model.OrdOrder
+	err := r.db.First(&order, id).Error
+	return &order, err
+}
+
+func (r *orderRepository) ListByIDs(ids []uint) ([]model.OrdOrder, error) {
+	var orders []model.OrdOrder
+	if len(ids) == 0 {
+		return orders, nil
+	}
+	err := r.db.Where("id IN ?", ids).Order("id ASC").Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) UpdateStatus(id uint, from, to int8, fields map[string]interface{}) (int64, error) {
+	updates := map[string]interface{}{"status": to}
+	for k, v := range fields {
+		updates[k] = v
+	}
+	result := r.db.Model(&model.OrdOrder{}).Where("id = ? AND status = ?", id, from).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+func (r *orderRepository) CreateLog(log *model.OrdOrderLog) error {
+	return r.db.Create(log).Error
+}
+
+func (r *orderRepository) ListLogs(orderID uint) ([]model.OrdOrderLog, error) {
+	var logs []model.OrdOrderLog
+	err := r.db.Where("order_id = ?", orderID).Order("id ASC").Find(&logs).Error
+	return logs, err
+}
+
+func (r *orderRepository) CreateOrder(order *model.OrdOrder) error {
+	return r.db.Create(order).Error
+}
+
+func (r *orderRepository) CreateItems(items []model.OrdOrderItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.Create(&items).Error
+}
+
+func (r *orderRepository) UpdateItem(item *model.OrdOrderItem) error {
+	return r.db.Save(item).Error
+}
+
+func (r *orderRepository) LockItem(id uint) (*model.OrdOrderItem, error) {
+	var item model.OrdOrderItem
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, id).Error
+	return &item, err
+}
+
+func (r *orderRepository) Lock(id uint) (*model.OrdOrder, error) {
+	var order model.OrdOrder
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error
+	return &order, err
+}
+
+func (r *orderRepository) ListForPicking(supplierID uint, deliveryDate time.Time, status int8) ([]model.OrdOrder, error) {
+	var orders []model.OrdOrder
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("supplier_id = ? AND delivery_date = ? AND status = ?", supplierID, deliveryDate.Format("2006-01-02"), status).
+		Order("id ASC").Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) ListByDeliveryRun(runID uint) ([]model.OrdOrder, error) {
+	var orders []model.OrdOrder
+	err := r.db.Where("delivery_run_id = ?", runID).Order("id ASC").Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) SetDeliveryRun(orderIDs []uint, fromRunID, toRunID uint) (int64, error) {
+	if len(orderIDs) == 0 {
+		return 0, nil
+	}
+	result := r.db.Model(&model.OrdOrder{}).
+		Where("id IN ? AND delivery_run_id = ?", orderIDs, fromRunID).
+		Update("delivery_run_id", toRunID)
+	return result.RowsAffected, result.Error
+}
+
+func (r *orderRepository) ListTracesByItems(itemIDs []uint) ([]model.OrdItemTrace, error) {
+	var traces []model.OrdItemTrace
+	if len(itemIDs) == 0 {
+		return traces, nil
+	}
+	err := r.db.Where("order_item_id IN ?", itemIDs).Find(&traces).Error
+	return traces, err
+}
+
+func (r *orderRepository) CreateTrace(trace *model.OrdItemTrace) error {
+	return r.db.Create(trace).Error
+}
+
+func (r *orderRepository) UpdateTraceDriver(itemIDs []uint, driverID uint) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.OrdItemTrace{}).Where("order_item_id IN ?", itemIDs).Update("driver_id", driverID).Error
+}
+
+func (r *orderRepository) SetItemsPickLine(itemIDs []uint, pickLineID uint) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&model.OrdOrderItem{}).Where("id IN ?", itemIDs).Update("pick_line_id", pickLineID).Error
+}
+
+func (r *orderRepository) UpdateTotal(id uint, status int8, total float64) (int64, error) {
+	result := r.db.Model(&model.OrdOrder{}).
+		Where("id = ? AND status = ?", id, status).
+		Update("total_amount", total)
+	return result.RowsAffected, result.Error
+}
+
+func (r *orderRepository) UpdateReceipt(order *model.OrdOrder) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.OrdOrder{}).Where("id = ?", order.ID).Updates(map[string]interface{}{
+			"receipt_vouchers":  order.ReceiptVouchers,
+			"receipt_signature": order.ReceiptSignature,
+			"receiver_name":     order.ReceiverName,
+			"received_at":       order.ReceivedAt,
+			"total_amount":      order.TotalAmount,
+		}).Error
+		if err != nil {
+			return err
+		}
+		owner := model.FileOwner{Kind: model.FileOwnerOrder, ID: order.ID}
+		return saveFileRefs(tx, order.TableName(), order.ID, owner, order.ReceiptVouchers, order.ReceiptSignature)
+	})
+}
+
+func (r *orderRepository) CreatePriceAdjustment(adjustment *model.OrdPriceAdjustment) error {
+	return r.db.Create(adjustment).Error
+}
+
+func (r *orderRepository) ListPriceAdjustments(orderID uint) ([]model.OrdPriceAdjustment, error) {
+	var adjustments []model.OrdPriceAdjustment
+	err := r.db.Where("order_id = ?", orderID).Order("id ASC").Find(&adjustments).Error
+	return adjustments, err
+}
+
+func (r *orderRepository) ListByCheckout(checkoutID uint) ([]model.OrdOrder, error) {
+	var orders []model.OrdOrder
+	err := r.db.Where("checkout_id = ?", checkoutID).Order("id ASC").Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) ListItemsByPickLines(lineIDs []uint) ([]model.OrdOrderItem, error) {
+	var items []model.OrdOrderItem
+	if len(lineIDs) == 0 {
+		return items, nil
+	}
+	err := r.db.Where("pick_line_id IN ?", lineIDs).Order("id ASC").Find(&items).Error
+	return items, err
+}
+
+func (r *orderRepository) ListItemsByOrders(orderIDs []uint) ([]model.OrdOrderItem, error) {
+	var items []model.OrdOrderItem
+	if len(orderIDs) == 0 {
+		return items, nil
+	}
+	err := r.db.Where("order_id IN ?", orderIDs).Order("id ASC").Find(&items).Error
+	return items, err
+}
+
+func (r *orderRepository) CreateCheckout(checkout *model.OrdCheckout) error {
+	return r.db.Create(checkout).Error
+}
+
+func (r *orderRepository) GetCheckoutByToken(merchantID uint, token string) (*model.OrdCheckout, error) {
+	var checkout model.OrdCheckout
+	err := r.db.Where("merchant_id = ? AND token = ?", merchantID, token).First(&checkout).Error
+	return &checkout, err
+}
+
+func (r *orderRepository) UpdateCheckout(checkout *model.OrdCheckout) error {
+	return r.db.Save(checkout).Error
+}
+
+func (r *orderRepository) CountByStatus(filter model.OrderListFilter) ([]model.OrderStatusCount, error) {
+	var counts []model.OrderStatusCount
+	err := r.filtered(filter).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Order("status ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// filtered 按筛选条件中除状态以外的字段构建订单查询
+func (r *orderRepository) filtered(filter model.OrderListFilter) *gorm.DB {
+	query := r.db.Model(&model.OrdOrder{})
+	if filter.MerchantIDs != nil {
+		query = query.Where("merchant_id IN ?", filter.MerchantIDs)
+	}
+	if filter.SupplierID != 0 {
+		query = query.Where("supplier_id = ?", filter.SupplierID)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("created_at >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("created_at < ?", *filter.EndDate)
+	}
+	if filter.DeliveryDate != nil {
+		query = query.Where("delivery_date = ?", filter.DeliveryDate.Format("2006-01-02"))
+	}
+	if filter.OrderNo != "" {
+		query = query.Where("order_no LIKE ?", "%"+filter.OrderNo+"%")
+	}
+	return query
+}
+
+// SupplierPerformance 统计供应商的订单数、完成数、售后数和送达时效
+func (r *orderRepository) SupplierPerformance(supplierIDs []uint, since time.Time) (map[uint]*model.SupplierPerformance, error) {
+	var orderStats []model.SupplierPerformance
+	err := r.db.Model(&model.OrdOrder{}).
+		Select("supplier_id, COUNT(*) AS order_count, "+
+			"SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS completed_count, "+
+			"MAX(arrival_time) AS last_delivery_at, "+
+			"AVG(CASE WHEN arrival_time IS NOT NULL THEN TIMESTAMPDIFF(MINUTE, created_at, arrival_time) / 60 END) AS avg_delivery_hours",
+			model.OrderStatusCompleted).
+		Where("supplier_id IN ? AND created_at >= ?", supplierIDs, since).
+		Group("supplier_id").
+		Scan(&orderStats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var afterSales []struct {
+		SupplierID uint
+		Count      int64
+	}
+	err = r.db.Table("ord_after_sales AS a").
+		Select("o.supplier_id, COUNT(*) AS count").
+		Joins("JOIN ord_order_items AS i ON i.id = a.order_item_id").
+		Joins("JOIN ord_orders AS o ON o.id = i.order_id").
+		Where("o.supplier_id IN ? AND o.created_at >= ?", supplierIDs, since).
+		Group("o.supplier_id").
+		Scan(&afterSales).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint]*model.SupplierPerformance, len(orderStats))
+	for i := range orderStats {
+		result[orderStats[i].SupplierID] = &orderStats[i]
+	}
+	for _, a := range afterSales {
+		if p, ok := result[a.SupplierID]; ok {
+			p.AfterSaleCount = a.Count
+		}
+	}
+	return result, nil
+}
@@ -17,8 +17,12 @@ type IOrganizationRepository interface {
 	GetByID(id uint) (*model.SysOrganization, error)
 	// List 分页列出组织，可按组织类型筛选
 	List(page, pageSize int, orgTypes []int8, parentID *uint) ([]model.SysOrganization, int64, error)
+	// ListByIDs 根据一批ID获取组织信息
+	ListByIDs(ids []uint) ([]model.SysOrganization, error)
 	// Update 更新一个已有的组织
 	Update(org *model.SysOrganization) error
 	// Delete 根据ID删除一个组织
 	Delete(id uint) error
+	// ListIDsByParents 获取指定父级下、指定类型的所有组织ID
+	ListIDsByParents(parentIDs []uint, orgTypes []int8) ([]uint, error)
 }
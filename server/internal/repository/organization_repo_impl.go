@@ -63,3 +63,25 @@ func (repo *organizationRepository) Update(org *model.SysOrganization) error {
 func (repo *organizationRepository) Delete(id uint) error {
 	return repo.db.Delete(&model.SysOrganization{}, id).Error
 }
+
+func (repo *organizationRepository) ListByIDs(ids []uint) ([]model.SysOrganization, error) {
+	var orgs []model.SysOrganization
+	if len(ids) == 0 {
+		return orgs, nil
+	}
+	err := repo.db.Where("id IN ?", ids).Find(&orgs).Error
+	return orgs, err
+}
+
+func (repo *organizationRepository) ListIDsByParents(parentIDs []uint, orgTypes []int8) ([]uint, error) {
+	var ids []uint
+	if len(parentIDs) == 0 {
+		return ids, nil
+	}
+	query := repo.db.Model(&model.SysOrganization{}).Where("parent_id IN ?", parentIDs)
+	if len(orgTypes) > 0 {
+		query = query.Where("org_type IN ?", orgTypes)
+	}
+	err := query.Pluck("id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,29 @@
+// server/internal/repository/pick_list_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IPickListRepository 定义了分拣单数据仓库的接口
+type IPickListRepository interface {
+	GetDB() *gorm.DB
+	Create(list *model.OrdPickList) error
+	GetByID(id uint) (*model.OrdPickList, error)
+	// Lock 以 FOR UPDATE 方式读取分拣单，调用方负责开启事务
+	Lock(id uint) (*model.OrdPickList, error)
+	Update(list *model.OrdPickList) error
+	// UpdateFields 只更新 fields 中列出的分拣单字段
+	UpdateFields(id uint, fields map[string]interface{}) error
+	// List 按筛选条件分页列出分拣单，按送达日期和ID倒序
+	List(filter model.PickListFilter, page, pageSize int) ([]model.OrdPickList, int64, error)
+	CreateLines(lines []model.OrdPickLine) error
+	GetLine(id uint) (*model.OrdPickLine, error)
+	UpdateLine(line *model.OrdPickLine) error
+	// ListLines 列出分拣单的所有分拣行，按商品和收货食堂排列
+	ListLines(pickListID uint) ([]model.OrdPickLine, error)
+	// ListUnfinishedOrders 列出一批订单中仍有明细在未完成分拣单上的订单ID
+	ListUnfinishedOrders(orderIDs []uint) ([]uint, error)
+}
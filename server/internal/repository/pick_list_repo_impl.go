@@ -0,0 +1,102 @@
+// server/internal/repository/pick_list_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type pickListRepository struct {
+	db *gorm.DB
+}
+
+// NewPickListRepository 创建一个新的 pickListRepository 实例
+func NewPickListRepository(db *gorm.DB) IPickListRepository {
+	return &pickListRepository{db: db}
+}
+
+func (r *pickListRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *pickListRepository) Create(list *model.OrdPickList) error {
+	return r.db.Create(list).Error
+}
+
+func (r *pickListRepository) GetByID(id uint) (*model.OrdPickList, error) {
+	var list model.OrdPickList
+	err := r.db.First(&list, id).Error
+	return &list, err
+}
+
+func (r *pickListRepository) Lock(id uint) (*model.OrdPickList, error) {
+	var list model.OrdPickList
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&list, id).Error
+	return &list, err
+}
+
+func (r *pickListRepository) Update(list *model.OrdPickList) error {
+	return r.db.Save(list).Error
+}
+
+func (r *pickListRepository) UpdateFields(id uint, fields map[string]interface{}) error {
+	return r.db.Model(&model.OrdPickList{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *pickListRepository) List(filter model.PickListFilter, page, pageSize int) ([]model.OrdPickList, int64, error) {
+	var lists []model.OrdPickList
+	var total int64
+
+	query := r.db.Model(&model.OrdPickList{}).Where("supplier_id = ?", filter.SupplierID)
+	if filter.DeliveryDate != nil {
+		query = query.Where("delivery_date = ?", filter.DeliveryDate.Format("2006-01-02"))
+	}
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).Order("delivery_date DESC, id DESC").Find(&lists).Error
+	return lists, total, err
+}
+
+func (r *pickListRepository) CreateLines(lines []model.OrdPickLine) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	return r.db.Create(&lines).Error
+}
+
+func (r *pickListRepository) GetLine(id uint) (*model.OrdPickLine, error) {
+	var line model.OrdPickLine
+	err := r.db.First(&line, id).Error
+	return &line, err
+}
+
+func (r *pickListRepository) UpdateLine(line *model.OrdPickLine) error {
+	return r.db.Save(line).Error
+}
+
+func (r *pickListRepository) ListLines(pickListID uint) ([]model.OrdPickLine, error) {
+	var lines []model.OrdPickLine
+	err := r.db.Where("pick_list_id = ?", pickListID).Order("product_id ASC, dest_id ASC").Find(&lines).Error
+	return lines, err
+}
+
+func (r *pickListRepository) ListUnfinishedOrders(orderIDs []uint) ([]uint, error) {
+	var ids []uint
+	if len(orderIDs) == 0 {
+		return ids, nil
+	}
+	err := r.db.Model(&model.OrdOrderItem{}).
+		Joins("JOIN ord_pick_lines AS l ON l.id = ord_order_items.pick_line_id").
+		Joins("JOIN ord_pick_lists AS p ON p.id = l.pick_list_id").
+		Where("ord_order_items.order_id IN ? AND p.status <> ?", orderIDs, model.PickListDone).
+		Distinct().Pluck("ord_order_items.order_id", &ids).Error
+	return ids, err
+}
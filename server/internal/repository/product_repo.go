@@ -0,0 +1,41 @@
+// server/internal/repository/product_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IProductRepository 定义商品(SPU)仓库接口
+type IProductRepository interface {
+	// GetDB 返回底层的 gorm.DB 实例，用于事务等高级操作
+	GetDB() *gorm.DB
+	Create(product *model.ScmProduct) error
+	GetByID(id uint) (*model.ScmProduct, error)
+	Update(product *model.ScmProduct) error
+	// List 按筛选条件分页列出商品，已被合并的商品不会出现在结果中
+	List(filter model.ProductListFilter, page, pageSize int) ([]model.ScmProduct, int64, error)
+	// CreateAudit 写入一条审核记录
+	CreateAudit(audit *model.ScmProductAudit) error
+	// ListAudits 按时间顺序列出商品的审核记录
+	ListAudits(productID uint) ([]model.ScmProductAudit, error)
+	// CountEnabledQuotes 统计商品当前启用且无需复核或已复核通过的报价数量
+	CountEnabledQuotes(productID uint) (int64, error)
+	// ListByIDs 按ID批量查询商品，不保证返回顺序
+	ListByIDs(ids []uint) ([]model.ScmProduct, error)
+	// ListAfterID 按ID升序返回大于 afterID 的一批商品，用于全量遍历
+	ListAfterID(afterID uint, limit int) ([]model.ScmProduct, error)
+	// ListDuplicateCandidates 列出同一学校、同一分类下未被合并且未被驳回的商品，用于重复检测
+	ListDuplicateCandidates(schoolID, categoryID, excludeID uint) ([]model.ScmProduct, error)
+	// ListMasterCopies 列出由某个平台标准商品生成的学校副本（不含已合并的）
+	ListMasterCopies(masterID uint) ([]model.ScmProduct, error)
+	// ExistingMasterCopies 返回学校已拥有的标准商品ID集合
+	ExistingMasterCopies(schoolID uint, masterIDs []uint) (map[uint]bool, error)
+	// SyncMasterCopies 将标准商品的修改同步到所有学校副本，规格已锁定的副本不会修改规格和单位
+	SyncMasterCopies(master *model.ScmMasterProduct) error
+	// UnlistByIDs 将指定学校下的一批商品下架，返回实际下架的数量
+	UnlistByIDs(schoolID uint, ids []uint) (int64, error)
+	// LockSpecs 只更新规格锁定标记，避免整行保存覆盖并发修改
+	LockSpecs(id uint) error
+}
@@ -0,0 +1,185 @@
+// server/internal/repository/product_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type productRepository struct {
+	db *gorm.DB
+}
+
+// NewProductRepository 创建一个新的 productRepository 实例
+func NewProductRepository(db *gorm.DB) IProductRepository {
+	return &productRepository{db: db}
+}
+
+func (r *productRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *productRepository) Create(product *model.ScmProduct) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		return saveProductFileRefs(tx, product.ID, product.Image, product.StaticCerts)
+	})
+}
+
+func (r *productRepository) GetByID(id uint) (*model.ScmProduct, error) {
+	var product model.ScmProduct
+	err := r.db.First(&product, id).Error
+	return &product, err
+}
+
+func (r *productRepository) Update(product *model.ScmProduct) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(product).Error; err != nil {
+			return err
+		}
+		return saveProductFileRefs(tx, product.ID, product.Image, product.StaticCerts)
+	})
+}
+
+func (r *productRepository) List(filter model.ProductListFilter, page, pageSize int) ([]model.ScmProduct, int64, error) {
+	var products []model.ScmProduct
+	var total int64
+
+	query := r.db.Model(&model.ScmProduct{}).Where("merged_into = 0")
+	if filter.SchoolID != 0 {
+		query = query.Where("school_id = ?", filter.SchoolID)
+	}
+	if filter.CreatorID != 0 {
+		query = query.Where("creator_id = ?", filter.CreatorID)
+	}
+	if filter.CategoryID != 0 {
+		query = query.Where("category_id = ?", filter.CategoryID)
+	}
+	if filter.AuditStatus != nil {
+		query = query.Where("audit_status = ?", *filter.AuditStatus)
+	}
+	if filter.IsListed != nil {
+		query = query.Where("is_listed = ?", *filter.IsListed)
+	}
+	if filter.Keyword != "" {
+		query = query.Where("name LIKE ?", "%"+filter.Keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&products).Error; err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+func (r *productRepository) CreateAudit(audit *model.ScmProductAudit) error {
+	return r.db.Create(audit).Error
+}
+
+func (r *productRepository) ListAudits(productID uint) ([]model.ScmProductAudit, error) {
+	var audits []model.ScmProductAudit
+	err := r.db.Where("product_id = ?", productID).Order("id ASC").Find(&audits).Error
+	return audits, err
+}
+
+func (r *productRepository) CountEnabledQuotes(productID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.ScmProductQuote{}).
+		Where("product_id = ? AND is_enabled = ?", productID, true).
+		Where("review_status IN ?", []int8{model.QuoteReviewNone, model.QuoteReviewAccepted}).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *productRepository) ListByIDs(ids []uint) ([]model.ScmProduct, error) {
+	var products []model.ScmProduct
+	err := r.db.Where("id IN ?", ids).Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) ListAfterID(afterID uint, limit int) ([]model.ScmProduct, error) {
+	var products []model.ScmProduct
+	err := r.db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) ListDuplicateCandidates(schoolID, categoryID, excludeID uint) ([]model.ScmProduct, error) {
+	var products []model.ScmProduct
+	err := r.db.Where("school_id = ? AND category_id = ? AND id <> ? AND merged_into = 0 AND audit_status <> ?",
+		schoolID, categoryID, excludeID, model.ProductAuditRejected).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) ListMasterCopies(masterID uint) ([]model.ScmProduct, error) {
+	var products []model.ScmProduct
+	err := r.db.Where("master_id = ? AND merged_into = 0", masterID).Order("school_id ASC").Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) ExistingMasterCopies(schoolID uint, masterIDs []uint) (map[uint]bool, error) {
+	var ids []uint
+	err := r.db.Model(&model.ScmProduct{}).
+		Where("school_id = ? AND master_id IN ? AND merged_into = 0", schoolID, masterIDs).
+		Pluck("master_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		existing[id] = true
+	}
+	return existing, nil
+}
+
+func (r *productRepository) SyncMasterCopies(master *model.ScmMasterProduct) error {
+	err := r.db.Model(&model.ScmProduct{}).
+		Where("master_id = ? AND merged_into = 0", master.ID).
+		Updates(map[string]interface{}{
+			"category_id":  master.CategoryID,
+			"name":         master.Name,
+			"image":        master.Image,
+			"static_certs": master.StaticCerts,
+		}).Error
+	if err != nil {
+		return err
+	}
+	var copyIDs []uint
+	if err := r.db.Model(&model.ScmProduct{}).Where("master_id = ? AND merged_into = 0", master.ID).Pluck("id", &copyIDs).Error; err != nil {
+		return err
+	}
+	for _, id := range copyIDs {
+		if err := saveProductFileRefs(r.db, id, master.Image, master.StaticCerts); err != nil {
+			return err
+		}
+	}
+
+	// 规格只同步到尚未锁定的副本
+	return r.db.Model(&model.ScmProduct{}).
+		Where("master_id = ? AND merged_into = 0 AND specs_locked = ?", master.ID, false).
+		Updates(map[string]interface{}{"specs": master.Specs, "unit": master.Unit}).Error
+}
+
+func (r *productRepository) UnlistByIDs(schoolID uint, ids []uint) (int64, error) {
+	result := r.db.Model(&model.ScmProduct{}).
+		Where("school_id = ? AND id IN ? AND is_listed = ?", schoolID, ids, true).
+		Update("is_listed", false)
+	return result.RowsAffected, result.Error
+}
+
+func (r *productRepository) LockSpecs(id uint) error {
+	return r.db.Model(&model.ScmProduct{}).Where("id = ?", id).Update("specs_locked", true).Error
+}
+
+// saveProductFileRefs 登记商品图片和资质引用的文件
+func saveProductFileRefs(db *gorm.DB, productID uint, image, staticCerts string) error {
+	owner := model.FileOwner{Kind: model.FileOwnerPublic, ID: productID}
+	return saveFileRefs(db, model.ScmProduct{}.TableName(), productID, owner, image, staticCerts)
+}
@@ -0,0 +1,47 @@
+// server/internal/repository/quote_repo.go
+package repository
+
+import (
+	"time"
+
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IQuoteRepository 定义了供应商报价数据仓库的接口
+type IQuoteRepository interface {
+	GetDB() *gorm.DB
+	Create(quote *model.ScmProductQuote) error
+	GetByID(id uint) (*model.ScmProductQuote, error)
+	// GetByProductAndSupplier 查找供应商对某商品的报价，每个供应商对同一商品只能有一条报价
+	GetByProductAndSupplier(productID, supplierID uint) (*model.ScmProductQuote, error)
+	Update(quote *model.ScmProductQuote) error
+	// LockByID 查询报价并加行锁（SELECT ... FOR UPDATE），需在事务中调用
+	LockByID(id uint) (*model.ScmProductQuote, error)
+	// UpdateBatchReports 只更新报价的检测报告列
+	UpdateBatchReports(id uint, raw string) error
+	// UpdateFields 只更新 fields 中列出的报价字段
+	UpdateFields(id uint, fields map[string]interface{}) error
+	// ListByProduct 列出商品的所有报价
+	ListByProduct(productID uint) ([]model.ScmProductQuote, error)
+	// ListBySupplier 分页列出供应商的报价（不含因商品合并而停用的报价），附带商品名称、规格和单位
+	ListBySupplier(supplierID uint, keyword string, page, pageSize int) ([]model.QuoteListItem, int64, error)
+	CreateHistory(history *model.ScmQuotePriceHistory) error
+	// DeletePendingHistory 删除报价尚未生效的预约调价
+	DeletePendingHistory(quoteID uint) error
+	// ListPendingHistory 列出一批报价尚未生效的预约调价
+	ListPendingHistory(quoteIDs []uint) ([]model.ScmQuotePriceHistory, error)
+	// ListDueHistory 列出生效时间已到但尚未生效的预约调价
+	ListDueHistory(now time.Time) ([]model.ScmQuotePriceHistory, error)
+	// MarkHistoryApplied 将预约调价标记为已生效，并记录生效时的原价格
+	MarkHistoryApplied(id uint, oldPrice float64) error
+	// MoveHistory 将一个商品的价格记录全部转移到另一个商品，用于合并重复商品
+	MoveHistory(fromProductID, toProductID uint) error
+	// ListHistory 按生效时间顺序列出已生效的价格记录
+	ListHistory(filter model.PriceHistoryFilter) ([]model.ScmQuotePriceHistory, error)
+	// ListEnabledByProducts 列出一批商品的所有启用报价，不包含已禁用供应商和价格待复核的报价，按价格从低到高排列
+	ListEnabledByProducts(productIDs []uint) ([]model.QuoteCompareRow, error)
+	// PricesAt 返回一批报价在指定时间点的生效价格，该时间点之前没有价格记录的报价不会出现在结果中
+	PricesAt(quoteIDs []uint, at time.Time) (map[uint]float64, error)
+}
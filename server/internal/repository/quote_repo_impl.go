@@ -0,0 +1,179 @@
+// server/internal/repository/quote_repo_impl.go
+package repository
+
+import (
+	"time"
+
+	"server/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type quoteRepository struct {
+	db *gorm.DB
+}
+
+// NewQuoteRepository 创建一个新的 quoteRepository 实例
+func NewQuoteRepository(db *gorm.DB) IQuoteRepository {
+	return &quoteRepository{db: db}
+}
+
+func (r *quoteRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *quoteRepository) Create(quote *model.ScmProductQuote) error {
+	return r.db.Create(quote).Error
+}
+
+func (r *quoteRepository) GetByID(id uint) (*model.ScmProductQuote, error) {
+	var quote model.ScmProductQuote
+	err := r.db.First(&quote, id).Error
+	return &quote, err
+}
+
+func (r *quoteRepository) GetByProductAndSupplier(productID, supplierID uint) (*model.ScmProductQuote, error) {
+	var quote model.ScmProductQuote
+	err := r.db.Where("product_id = ? AND supplier_id = ?", productID, supplierID).First(&quote).Error
+	return &quote, err
+}
+
+func (r *quoteRepository) Update(quote *model.ScmProductQuote) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(quote).Error; err != nil {
+			return err
+		}
+		return saveQuoteFileRefs(tx, quote.ID, quote.BatchReports)
+	})
+}
+
+func (r *quoteRepository) LockByID(id uint) (*model.ScmProductQuote, error) {
+	var quote model.ScmProductQuote
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&quote, id).Error
+	return &quote, err
+}
+
+func (r *quoteRepository) UpdateBatchReports(id uint, raw string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.ScmProductQuote{}).Where("id = ?", id).Update("batch_reports", raw).Error; err != nil {
+			return err
+		}
+		return saveQuoteFileRefs(tx, id, raw)
+	})
+}
+
+// saveQuoteFileRefs 登记报价检测报告引用的文件
+func saveQuoteFileRefs(db *gorm.DB, quoteID uint, batchReports string) error {
+	owner := model.FileOwner{Kind: model.FileOwnerQuote, ID: quoteID}
+	return saveFileRefs(db, model.ScmProductQuote{}.TableName(), quoteID, owner, batchReports)
+}
+
+func (r *quoteRepository) UpdateFields(id uint, fields map[string]interface{}) error {
+	return r.db.Model(&model.ScmProductQuote{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *quoteRepository) ListByProduct(productID uint) ([]model.ScmProductQuote, error) {
+	var quotes []model.ScmProductQuote
+	err := r.db.Where("product_id = ?", productID).Order("id ASC").Find(&quotes).Error
+	return quotes, err
+}
+
+func (r *quoteRepository) ListBySupplier(supplierID uint, keyword string, page, pageSize int) ([]model.QuoteListItem, int64, error) {
+	var items []model.QuoteListItem
+	var total int64
+
+	query := r.db.Table("scm_product_quotes AS q").
+		Joins("JOIN scm_products AS p ON p.id = q.product_id").
+		Where("q.supplier_id = ? AND q.merged_into = 0", supplierID)
+	if keyword != "" {
+		query = query.Where("p.name LIKE ?", "%"+keyword+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	err := query.Select("q.*, p.name AS product_name, p.specs, p.unit").
+		Offset(offset).Limit(pageSize).Order("q.id DESC").Scan(&items).Error
+	return items, total, err
+}
+
+func (r *quoteRepository) CreateHistory(history *model.ScmQuotePriceHistory) error {
+	return r.db.Create(history).Error
+}
+
+func (r *quoteRepository) DeletePendingHistory(quoteID uint) error {
+	return r.db.Where("quote_id = ? AND applied = ?", quoteID, false).Delete(&model.ScmQuotePriceHistory{}).Error
+}
+
+func (r *quoteRepository) ListPendingHistory(quoteIDs []uint) ([]model.ScmQuotePriceHistory, error) {
+	var histories []model.ScmQuotePriceHistory
+	err := r.db.Where("quote_id IN ? AND applied = ?", quoteIDs, false).Find(&histories).Error
+	return histories, err
+}
+
+func (r *quoteRepository) ListDueHistory(now time.Time) ([]model.ScmQuotePriceHistory, error) {
+	var histories []model.ScmQuotePriceHistory
+	err := r.db.Where("applied = ? AND effective_from <= ?", false, now).
+		Order("effective_from ASC").Find(&histories).Error
+	return histories, err
+}
+
+func (r *quoteRepository) MarkHistoryApplied(id uint, oldPrice float64) error {
+	return r.db.Model(&model.ScmQuotePriceHistory{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"applied": true, "old_price": oldPrice}).Error
+}
+
+func (r *quoteRepository) MoveHistory(fromProductID, toProductID uint) error {
+	return r.db.Model(&model.ScmQuotePriceHistory{}).Where("product_id = ?", fromProductID).
+		Update("product_id", toProductID).Error
+}
+
+func (r *quoteRepository) ListHistory(filter model.PriceHistoryFilter) ([]model.ScmQuotePriceHistory, error) {
+	var histories []model.ScmQuotePriceHistory
+
+	query := r.db.Where("product_id = ? AND applied = ?", filter.ProductID, true)
+	if filter.SupplierID != 0 {
+		query = query.Where("supplier_id = ?", filter.SupplierID)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("effective_from >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("effective_from < ?", *filter.EndDate)
+	}
+
+	err := query.Order("effective_from ASC, id ASC").Find(&histories).Error
+	return histories, err
+}
+
+func (r *quoteRepository) ListEnabledByProducts(productIDs []uint) ([]model.QuoteCompareRow, error) {
+	var rows []model.QuoteCompareRow
+	err := r.db.Table("scm_product_quotes AS q").
+		Select("q.id, q.product_id, q.supplier_id, o.name AS supplier_name, q.price, q.batch_reports").
+		Joins("JOIN sys_organizations AS o ON o.id = q.supplier_id").
+		Where("q.product_id IN ? AND q.is_enabled = ? AND o.is_enabled = ?", productIDs, true, true).
+		Where("q.review_status IN ?", []int8{model.QuoteReviewNone, model.QuoteReviewAccepted}).
+		Order("q.price ASC, q.id ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *quoteRepository) PricesAt(quoteIDs []uint, at time.Time) (map[uint]float64, error) {
+	var histories []model.ScmQuotePriceHistory
+	latest := r.db.Model(&model.ScmQuotePriceHistory{}).
+		Select("MAX(id)").
+		Where("quote_id IN ? AND applied = ? AND effective_from <= ?", quoteIDs, true, at).
+		Group("quote_id")
+	if err := r.db.Where("id IN (?)", latest).Find(&histories).Error; err != nil {
+		return nil, err
+	}
+
+	prices := make(map[uint]float64, len(histories))
+	for _, h := range histories {
+		prices[h.QuoteID] = h.Price
+	}
+	return prices, nil
+}
@@ -0,0 +1,37 @@
+// server/internal/repository/standing_order_repo.go
+package repository
+
+import (
+	"time"
+
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// IStandingOrderRepository 定义了定期订单数据仓库的接口
+type IStandingOrderRepository interface {
+	GetDB() *gorm.DB
+	Create(standing *model.OrdStandingOrder) error
+	GetByID(id uint) (*model.OrdStandingOrder, error)
+	Update(standing *model.OrdStandingOrder) error
+	// UpdateFields 只更新 fields 中列出的模板字段
+	UpdateFields(id uint, fields map[string]interface{}) error
+	// UpdateLastRunDate 仅当模板仍处于启用状态时更新最近执行日期，不覆盖其他字段
+	UpdateLastRunDate(id uint, date time.Time) error
+	// Delete 删除模板及其商品，执行记录保留
+	Delete(id uint) error
+	// ListByMerchant 分页列出买家的定期订单模板，按ID倒序
+	ListByMerchant(merchantID uint, page, pageSize int) ([]model.OrdStandingOrder, int64, error)
+	// ListActive 列出所有启用中的模板
+	ListActive() ([]model.OrdStandingOrder, error)
+	// ReplaceItems 用 items 整体替换模板的商品
+	ReplaceItems(standingOrderID uint, items []model.OrdStandingOrderItem) error
+	// ListLines 列出模板商品及其报价、商品的实时信息，结构与购物车条目一致，AddedPrice 为当前报价
+	ListLines(standingOrderID uint) ([]model.CartLineRow, error)
+	// GetRun 查找模板某送达日期的执行记录
+	GetRun(standingOrderID uint, deliveryDate time.Time) (*model.OrdStandingOrderRun, error)
+	CreateRun(run *model.OrdStandingOrderRun) error
+	// ListRuns 分页列出模板的执行记录，按送达日期倒序
+	ListRuns(standingOrderID uint, page, pageSize int) ([]model.OrdStandingOrderRun, int64, error)
+}
@@ -0,0 +1,131 @@
+// server/internal/repository/standing_order_repo_impl.go
+package repository
+
+import (
+	"time"
+
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type standingOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewStandingOrderRepository 创建一个新的 standingOrderRepository 实例
+func NewStandingOrderRepository(db *gorm.DB) IStandingOrderRepository {
+	return &standingOrderRepository{db: db}
+}
+
+func (r *standingOrderRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *standingOrderRepository) Create(standing *model.OrdStandingOrder) error {
+	return r.db.Create(standing).Error
+}
+
+func (r *standingOrderRepository) GetByID(id uint) (*model.OrdStandingOrder, error) {
+	var standing model.OrdStandingOrder
+	err := r.db.First(&standing, id).Error
+	return &standing, err
+}
+
+func (r *standingOrderRepository) Update(standing *model.OrdStandingOrder) error {
+	return r.db.Save(standing).Error
+}
+
+func (r *standingOrderRepository) UpdateFields(id uint, fields map[string]interface{}) error {
+	return r.db.Model(&model.OrdStandingOrder{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *standingOrderRepository) UpdateLastRunDate(id uint, date time.Time) error {
+	return r.db.Model(&model.OrdStandingOrder{}).
+		Where("id = ? AND status = ?", id, model.StandingOrderActive).
+		Update("last_run_date", date).Error
+}
+
+func (r *standingOrderRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("standing_order_id = ?", id).Delete(&model.OrdStandingOrderItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.OrdStandingOrder{}, id).Error
+	})
+}
+
+func (r *standingOrderRepository) ListByMerchant(merchantID uint, page, pageSize int) ([]model.OrdStandingOrder, int64, error) {
+	var list []model.OrdStandingOrder
+	var total int64
+
+	query := r.db.Model(&model.OrdStandingOrder{}).Where("merchant_id = ?", merchantID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).Order("id DESC").Find(&list).Error
+	return list, total, err
+}
+
+func (r *standingOrderRepository) ListActive() ([]model.OrdStandingOrder, error) {
+	var list []model.OrdStandingOrder
+	err := r.db.Where("status = ?", model.StandingOrderActive).Order("id ASC").Find(&list).Error
+	return list, err
+}
+
+func (r *standingOrderRepository) ReplaceItems(standingOrderID uint, items []model.OrdStandingOrderItem) error {
+	if err := r.db.Where("standing_order_id = ?", standingOrderID).Delete(&model.OrdStandingOrderItem{}).Error; err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	return r.db.Create(&items).Error
+}
+
+func (r *standingOrderRepository) ListLines(standingOrderID uint) ([]model.CartLineRow, error) {
+	var rows []model.CartLineRow
+	// 报价或商品可能已被删除，使用 LEFT JOIN 保留模板商品，并将缺失的字段置为零值
+	err := r.db.Table("ord_standing_order_items AS s").
+		Select(`s.id, s.quote_id, s.quantity, COALESCE(q.price, 0) AS added_price,
+			COALESCE(q.product_id, 0) AS product_id, COALESCE(q.supplier_id, 0) AS supplier_id,
+			COALESCE(o.name, '') AS supplier_name, COALESCE(o.is_enabled, 0) AS supplier_enabled,
+			COALESCE(q.price, 0) AS price, COALESCE(q.is_enabled, 0) AS quote_enabled,
+			COALESCE(q.review_status, 0) AS review_status, COALESCE(q.batch_reports, '') AS batch_reports,
+			COALESCE(p.name, '') AS product_name, COALESCE(p.specs, '') AS specs, COALESCE(p.unit, '') AS unit,
+			COALESCE(p.image, '') AS image, COALESCE(p.category_id, 0) AS category_id,
+			COALESCE(p.school_id, 0) AS school_id, COALESCE(p.audit_status, 0) AS audit_status,
+			COALESCE(p.is_listed, 0) AS is_listed, COALESCE(p.merged_into, 0) AS merged_into`).
+		Joins("LEFT JOIN scm_product_quotes AS q ON q.id = s.quote_id").
+		Joins("LEFT JOIN scm_products AS p ON p.id = q.product_id").
+		Joins("LEFT JOIN sys_organizations AS o ON o.id = q.supplier_id").
+		Where("s.standing_order_id = ?", standingOrderID).
+		Order("s.id ASC").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *standingOrderRepository) GetRun(standingOrderID uint, deliveryDate time.Time) (*model.OrdStandingOrderRun, error) {
+	var run model.OrdStandingOrderRun
+	err := r.db.Where("standing_order_id = ? AND delivery_date = ?", standingOrderID, deliveryDate.Format("2006-01-02")).
+		First(&run).Error
+	return &run, err
+}
+
+func (r *standingOrderRepository) CreateRun(run *model.OrdStandingOrderRun) error {
+	return r.db.Create(run).Error
+}
+
+func (r *standingOrderRepository) ListRuns(standingOrderID uint, page, pageSize int) ([]model.OrdStandingOrderRun, int64, error) {
+	var runs []model.OrdStandingOrderRun
+	var total int64
+
+	query := r.db.Model(&model.OrdStandingOrderRun{}).Where("standing_order_id = ?", standingOrderID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Offset(offset).Limit(pageSize).Order("delivery_date DESC, id DESC").Find(&runs).Error
+	return runs, total, err
+}
@@ -0,0 +1,21 @@
+// server/internal/repository/supplier_staff_repo.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ISupplierStaffRepository 定义了供应商员工数据仓库的接口
+type ISupplierStaffRepository interface {
+	GetDB() *gorm.DB
+	Create(staff *model.ScmSupplierStaff) error
+	GetByID(id uint) (*model.ScmSupplierStaff, error)
+	Update(staff *model.ScmSupplierStaff) error
+	Delete(id uint) error
+	// IsReferenced 判断员工是否已被分拣单、配送趟次或溯源记录引用
+	IsReferenced(id uint) (bool, error)
+	// List 分页列出员工，按健康证到期时间升序排列
+	List(filter model.SupplierStaffFilter, page, pageSize int) ([]model.ScmSupplierStaff, int64, error)
+}
@@ -0,0 +1,109 @@
+// server/internal/repository/supplier_staff_repo_impl.go
+package repository
+
+import (
+	"server/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type supplierStaffRepository struct {
+	db *gorm.DB
+}
+
+// NewSupplierStaffRepository 创建一个新的 supplierStaffRepository 实例
+func NewSupplierStaffRepository(db *gorm.DB) ISupplierStaffRepository {
+	return &supplierStaffRepository{db: db}
+}
+
+func (r *supplierStaffRepository) GetDB() *gorm.DB {
+	return r.db
+}
+
+func (r *supplierStaffRepository) Create(staff *model.ScmSupplierStaff) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(staff).Error; err != nil {
+			return err
+		}
+		return saveStaffFileRefs(tx, staff)
+	})
+}
+
+func (r *supplierStaffRepository) GetByID(id uint) (*model.ScmSupplierStaff, error) {
+	var staff model.ScmSupplierStaff
+	err := r.db.First(&staff, id).Error
+	return &staff, err
+}
+
+func (r *supplierStaffRepository) Update(staff *model.ScmSupplierStaff) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(staff).Error; err != nil {
+			return err
+		}
+		return saveStaffFileRefs(tx, staff)
+	})
+}
+
+func (r *supplierStaffRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.ScmSupplierStaff{}, id).Error; err != nil {
+			return err
+		}
+		return deleteFileRefs(tx, model.ScmSupplierStaff{}.TableName(), id)
+	})
+}
+
+// saveStaffFileRefs 登记员工健康证引用的文件，健康证只对所属供应商可见
+func saveStaffFileRefs(db *gorm.DB, staff *model.ScmSupplierStaff) error {
+	owner := model.FileOwner{Kind: model.FileOwnerSupplier, ID: staff.SupplierID}
+	return saveFileRefs(db, staff.TableName(), staff.ID, owner, staff.HealthCert)
+}
+
+func (r *supplierStaffRepository) IsReferenced(id uint) (bool, error) {
+	refs := []struct {
+		model  interface{}
+		column string
+	}{
+		{&model.OrdPickList{}, "picker_id"},
+		{&model.OrdDeliveryRun{}, "driver_id"},
+		{&model.OrdItemTrace{}, "driver_id"},
+	}
+	for _, ref := range refs {
+		var count int64
+		if err := r.db.Model(ref.model).Where(ref.column+" = ?", id).Limit(1).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *supplierStaffRepository) List(filter model.SupplierStaffFilter, page, pageSize int) ([]model.ScmSupplierStaff, int64, error) {
+	var staffs []model.ScmSupplierStaff
+	var total int64
+
+	query := r.db.Model(&model.ScmSupplierStaff{}).Where("supplier_id = ?", filter.SupplierID)
+	if filter.RoleType != 0 {
+		query = query.Where("role_type = ?", filter.RoleType)
+	}
+	if filter.Keyword != "" {
+		like := "%" + filter.Keyword + "%"
+		query = query.Where("name LIKE ? OR mobile LIKE ?", like, like)
+	}
+	if filter.ExpireBy != nil {
+		query = query.Where("health_cert_expiry IS NULL OR health_cert_expiry < ?", *filter.ExpireBy)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * pageSize
+	// 未登记有效期的排在最前，其余按到期时间升序
+	err := query.Offset(offset).Limit(pageSize).
+		Order("health_cert_expiry IS NOT NULL, health_cert_expiry ASC, id DESC").
+		Find(&staffs).Error
+	return staffs, total, err
+}
@@ -90,3 +90,35 @@ func PlatformAdminAuth() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RoleAuth 是一个通用的授权中间件，只允许指定角色的用户访问
+func RoleAuth(roles ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(c *gin.Context) {
+		claims, exists := c.Get(ContextUserClaimsKey)
+		if !exists {
+			c.JSON(http.StatusForbidden, gin.H{"error": "无法获取用户信息，禁止访问"})
+			c.Abort()
+			return
+		}
+
+		userClaims, ok := claims.(*jwt.CustomClaims)
+		if !ok || userClaims == nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "用户信息格式错误，禁止访问"})
+			c.Abort()
+			return
+		}
+
+		if !allowed[userClaims.Role] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "您的角色无权访问该功能"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
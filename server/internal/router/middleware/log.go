@@ -4,6 +4,7 @@ package middleware
 import (
 	"bytes"
 	"io"
+	"strings"
 
 	"server/internal/service"
 	"server/pkg/jwt"
@@ -18,11 +19,16 @@ func LogOperation(logService service.ILogService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// 读取 body 内容，因为 Gin 的 c.Request.Body 是一个只读流，读完即空。
 		// 我们需要先读出来，再重新写回去，以便后续的 c.ShouldBindJSON 能正常工作。
+		// 文件上传(multipart)的请求体可能很大且为二进制内容，不读取也不记录
 		var bodyBytes []byte
-		if c.Request.Body != nil {
-			bodyBytes, _ = io.ReadAll(c.Request.Body)
+		if strings.HasPrefix(c.ContentType(), "multipart/") {
+			bodyBytes = []byte("[multipart/form-data]")
+		} else {
+			if c.Request.Body != nil {
+				bodyBytes, _ = io.ReadAll(c.Request.Body)
+			}
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 		}
-		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 
 		// 先执行请求的业务逻辑
 		c.Next()
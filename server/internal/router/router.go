@@ -1,13 +1,18 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
+	"server/internal/config"
 	"server/internal/handler"
+	"server/internal/model"
 	"server/internal/repository"
 	"server/internal/router/middleware"
 	"server/internal/service"
 	"server/pkg/database" // 确保导入 database 包
+	"server/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 )
@@ -32,22 +37,90 @@ func Init() *gin.Engine {
 	})
 
 	// --- 依赖注入 ---
+	fileStore, err := newFileStorage()
+	if err != nil {
+		panic(err)
+	}
+
 	userRepo := repository.NewUserRepository(database.DB)
 	roleRepo := repository.NewRoleRepository(database.DB)
 	orgRepo := repository.NewOrganizationRepository(database.DB)
 	logRepo := repository.NewLogRepository(database.DB)
+	orderRepo := repository.NewOrderRepository(database.DB)
+	dictRepo := repository.NewDictionaryRepository(database.DB)
+	exportJobRepo := repository.NewExportJobRepository(database.DB)
+	categoryRepo := repository.NewCategoryRepository(database.DB)
+	productRepo := repository.NewProductRepository(database.DB)
+	quoteRepo := repository.NewQuoteRepository(database.DB)
+	guidePriceRepo := repository.NewGuidePriceRepository(database.DB)
+	masterProductRepo := repository.NewMasterProductRepository(database.DB)
+	supplierStaffRepo := repository.NewSupplierStaffRepository(database.DB)
+	fileRepo := repository.NewFileRepository(database.DB)
+	cartRepo := repository.NewCartRepository(database.DB)
+	pickListRepo := repository.NewPickListRepository(database.DB)
+	deliveryRunRepo := repository.NewDeliveryRunRepository(database.DB)
+	afterSaleRepo := repository.NewAfterSaleRepository(database.DB)
+	analyticsRepo := repository.NewAnalyticsRepository(database.DB)
+	standingOrderRepo := repository.NewStandingOrderRepository(database.DB)
+	notificationRepo := repository.NewNotificationRepository(database.DB)
 
 	authService := service.NewAuthService(userRepo, roleRepo)
 	accountService := service.NewAccountService(userRepo, roleRepo)
 	schoolService := service.NewSchoolService(orgRepo, userRepo, roleRepo)
 	logService := service.NewLogService(logRepo)
 	supplierService := service.NewSupplierService(orgRepo, userRepo, roleRepo)
+	importService := service.NewImportService(orgRepo, userRepo)
+	categoryService := service.NewCategoryService(categoryRepo)
+	fileService := service.NewFileService(fileRepo, orderRepo, orgRepo, quoteRepo, productRepo, fileStore, fileOptions())
+	productSearchService := service.NewProductSearchService(productRepo, orgRepo, fileService)
+	productService := service.NewProductService(productRepo, orgRepo, categoryService, productSearchService, fileService)
+	guidePriceService := service.NewGuidePriceService(guidePriceRepo, productRepo, quoteRepo, productService)
+	quoteService := service.NewQuoteService(quoteRepo, productRepo, orgRepo, productService, guidePriceService)
+	batchReportService := service.NewBatchReportService(quoteRepo, productRepo, categoryService, productService)
+	comparisonService := service.NewComparisonService(productRepo, quoteRepo, orderRepo, orgRepo, categoryService)
+	masterProductService := service.NewMasterProductService(masterProductRepo, productRepo, orgRepo, categoryService, productSearchService)
+	supplierStaffService := service.NewSupplierStaffService(supplierStaffRepo)
+	cartService := service.NewCartService(cartRepo, quoteRepo, productRepo, orgRepo, categoryService, fileService)
+	orderService := service.NewOrderService(orderRepo, orgRepo, categoryService, fileService)
+	pickingService := service.NewPickingService(pickListRepo, orderRepo, orgRepo, supplierStaffRepo, supplierStaffService)
+	deliveryService := service.NewDeliveryService(deliveryRunRepo, orderRepo, orgRepo, supplierStaffRepo, supplierStaffService)
+	receivingService := service.NewReceivingService(orderRepo, afterSaleRepo, orgRepo, fileRepo, fileService)
+	afterSaleService := service.NewAfterSaleService(afterSaleRepo, orderRepo, orgRepo, fileRepo, fileService)
+	analyticsService := service.NewAnalyticsService(analyticsRepo, orderRepo, orgRepo)
+	notificationService := service.NewNotificationService(notificationRepo)
+	standingOrderService := service.NewStandingOrderService(standingOrderRepo, quoteRepo, orgRepo, orderService, categoryService, notificationService)
+	exportService := service.NewExportService(schoolService, supplierService, accountService, logService, orgRepo, orderRepo, roleRepo, dictRepo, exportJobRepo)
 
 	authHandler := handler.NewAuthHandler(authService)
 	accountHandler := handler.NewAccountHandler(accountService)
 	schoolHandler := handler.NewSchoolHandler(schoolService)
 	logHandler := handler.NewLogHandler(logService)
 	supplierHandler := handler.NewSupplierHandler(supplierService)
+	importHandler := handler.NewImportHandler(importService)
+	exportHandler := handler.NewExportHandler(exportService)
+	categoryHandler := handler.NewCategoryHandler(categoryService)
+	productHandler := handler.NewProductHandler(productService, productSearchService)
+	quoteHandler := handler.NewQuoteHandler(quoteService)
+	batchReportHandler := handler.NewBatchReportHandler(batchReportService)
+	comparisonHandler := handler.NewComparisonHandler(comparisonService)
+	guidePriceHandler := handler.NewGuidePriceHandler(guidePriceService)
+	masterProductHandler := handler.NewMasterProductHandler(masterProductService)
+	supplierStaffHandler := handler.NewSupplierStaffHandler(supplierStaffService)
+	fileHandler := handler.NewFileHandler(fileService)
+	cartHandler := handler.NewCartHandler(cartService)
+	orderHandler := handler.NewOrderHandler(orderService)
+	pickingHandler := handler.NewPickingHandler(pickingService)
+	deliveryHandler := handler.NewDeliveryHandler(deliveryService)
+	receivingHandler := handler.NewReceivingHandler(receivingService)
+	afterSaleHandler := handler.NewAfterSaleHandler(afterSaleService)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+	standingOrderHandler := handler.NewStandingOrderHandler(standingOrderService)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	// --- 后台任务 ---
+	quoteService.StartScheduler(time.Minute)              // 每分钟执行一次到期的预约调价
+	fileService.StartCleanup(time.Hour)                   // 每小时清理一次孤儿文件
+	standingOrderService.StartScheduler(10 * time.Minute) // 每 10 分钟生成一次到期的定期订单
 
 	// --- 路由注册 ---
 	apiGroup := r.Group("/api/v1")
@@ -58,6 +131,9 @@ func Init() *gin.Engine {
 			sysGroup.POST("/login", authHandler.Login)
 		}
 
+		// 本地存储的签名下载地址，凭签名访问，不需要认证
+		apiGroup.GET("/files/raw/*key", fileHandler.Raw)
+
 		// Use a middleware to log operations on subsequent groups
 		apiGroup.Use(middleware.LogOperation(logService))
 
@@ -101,6 +177,251 @@ func Init() *gin.Engine {
 			supplierGroup.PUT("/:id/status", supplierHandler.UpdateSupplierStatus)
 		}
 
+		// 批量导入路由，各导入类型沿用对应单条创建接口的权限要求
+		importGroup := apiGroup.Group("/imports")
+		importGroup.Use(middleware.AuthMiddleware())
+		{
+			importGroup.GET("/:type/template", importHandler.DownloadTemplate)
+			importGroup.POST("/schools", middleware.PlatformAdminAuth(), importHandler.ImportSchools)
+			importGroup.POST("/suppliers", middleware.RoleAuth(model.RoleSchoolAdmin), importHandler.ImportSuppliers)
+			importGroup.POST("/accounts", middleware.CanCreateUsers(roleRepo), importHandler.ImportAccounts)
+		}
+
+		// 导出路由，各数据源的权限在导出服务中按角色校验
+		exportGroup := apiGroup.Group("/exports")
+		exportGroup.Use(middleware.AuthMiddleware())
+		{
+			exportGroup.GET("/:source", exportHandler.Export)
+			exportGroup.GET("/:source/columns", exportHandler.Columns)
+		}
+
+		exportJobGroup := apiGroup.Group("/export-jobs")
+		exportJobGroup.Use(middleware.AuthMiddleware())
+		{
+			exportJobGroup.GET("", exportHandler.ListJobs)
+			exportJobGroup.GET("/:id", exportHandler.GetJob)
+			exportJobGroup.GET("/:id/download", exportHandler.DownloadJob)
+		}
+
+		// 商品分类路由，分类树对所有登录用户开放，维护操作仅限平台
+		categoryGroup := apiGroup.Group("/categories")
+		categoryGroup.Use(middleware.AuthMiddleware())
+		{
+			categoryGroup.GET("/tree", categoryHandler.Tree)
+			categoryGroup.POST("", middleware.PlatformAdminAuth(), categoryHandler.Create)
+			categoryGroup.PUT("/sort", middleware.PlatformAdminAuth(), categoryHandler.Sort)
+			categoryGroup.PUT("/:id", middleware.PlatformAdminAuth(), categoryHandler.Update)
+			categoryGroup.PUT("/:id/report-policy", middleware.PlatformAdminAuth(), categoryHandler.ReportPolicy)
+			categoryGroup.PUT("/:id/move", middleware.PlatformAdminAuth(), categoryHandler.Move)
+			categoryGroup.DELETE("/:id", middleware.PlatformAdminAuth(), categoryHandler.Delete)
+		}
+
+		// 商品(SPU)路由：供应商提交与修改，学校审核与上下架，食堂查询已上架商品
+		supplierRoles := middleware.RoleAuth(model.RoleSupplierAdmin, model.RoleSupplierStaff)
+		schoolRoles := middleware.RoleAuth(model.RoleSchoolAdmin, model.RoleSchoolStaff)
+		buyerRoles := middleware.RoleAuth(model.RoleCanteenAdmin, model.RoleCanteenStaff, model.RoleMerchantAdmin, model.RoleMerchantStaff)
+		// 已上架商品按学校划分，只对本校管理员和食堂/商户开放
+		listedRoles := middleware.RoleAuth(model.RoleSchoolAdmin, model.RoleSchoolStaff,
+			model.RoleCanteenAdmin, model.RoleCanteenStaff, model.RoleMerchantAdmin, model.RoleMerchantStaff)
+		schoolAdmin := middleware.RoleAuth(model.RoleSchoolAdmin)
+		supplierAdmin := middleware.RoleAuth(model.RoleSupplierAdmin)
+		productGroup := apiGroup.Group("/products")
+		productGroup.Use(middleware.AuthMiddleware())
+		{
+			productGroup.GET("", productHandler.List)
+			productGroup.GET("/audit-queue", schoolRoles, productHandler.AuditQueue)
+			productGroup.GET("/listed", listedRoles, productHandler.Listed)
+			productGroup.GET("/search", productHandler.Search)
+			productGroup.POST("/search/rebuild", middleware.PlatformAdminAuth(), productHandler.RebuildIndex)
+			productGroup.GET("/:id", productHandler.GetByID)
+			productGroup.GET("/:id/price-history", quoteHandler.PriceHistory)
+			productGroup.GET("/:id/batch-reports", batchReportHandler.ProductReports)
+			productGroup.GET("/:id/duplicates", productHandler.Duplicates)
+			productGroup.POST("", supplierRoles, productHandler.Submit)
+			productGroup.PUT("/:id", supplierRoles, productHandler.Update)
+			productGroup.PUT("/:id/certs", supplierRoles, productHandler.UpdateCerts)
+			productGroup.POST("/:id/approve", schoolRoles, productHandler.Approve)
+			productGroup.POST("/:id/reject", schoolRoles, productHandler.Reject)
+			productGroup.POST("/:id/merge", schoolRoles, productHandler.Merge)
+			productGroup.POST("/:id/list", schoolAdmin, productHandler.ListOnShelf)
+			productGroup.POST("/:id/unlist", schoolAdmin, productHandler.Unlist)
+			productGroup.POST("/batch-unlist", schoolAdmin, productHandler.BatchUnlist)
+		}
+
+		// 平台标准商品库路由：平台维护并下发，学校浏览和订阅
+		masterGroup := apiGroup.Group("/master-products")
+		masterGroup.Use(middleware.AuthMiddleware())
+		{
+			masterGroup.GET("", masterProductHandler.List)
+			masterGroup.POST("", middleware.PlatformAdminAuth(), masterProductHandler.Create)
+			masterGroup.POST("/push", middleware.PlatformAdminAuth(), masterProductHandler.Push)
+			masterGroup.POST("/subscribe", schoolAdmin, masterProductHandler.Subscribe)
+			masterGroup.PUT("/:id", middleware.PlatformAdminAuth(), masterProductHandler.Update)
+			masterGroup.PUT("/:id/status", middleware.PlatformAdminAuth(), masterProductHandler.UpdateStatus)
+			masterGroup.GET("/:id/copies", middleware.PlatformAdminAuth(), masterProductHandler.Copies)
+		}
+
+		// 供应商员工路由：司机与分拣员管理，供应商管理员维护
+		staffGroup := apiGroup.Group("/supplier-staff")
+		staffGroup.Use(middleware.AuthMiddleware(), supplierRoles)
+		{
+			staffGroup.GET("", supplierStaffHandler.List)
+			staffGroup.GET("/warnings", supplierStaffHandler.Warnings)
+			staffGroup.POST("", supplierAdmin, supplierStaffHandler.Create)
+			staffGroup.PUT("/:id", supplierAdmin, supplierStaffHandler.Update)
+			staffGroup.PUT("/:id/status", supplierAdmin, supplierStaffHandler.UpdateStatus)
+			staffGroup.DELETE("/:id", supplierAdmin, supplierStaffHandler.Delete)
+		}
+
+		// 文件路由：上传、获取签名下载地址
+		fileGroup := apiGroup.Group("/files")
+		fileGroup.Use(middleware.AuthMiddleware())
+		{
+			fileGroup.POST("", fileHandler.Upload)
+			fileGroup.GET("/url", fileHandler.SignURL)
+			fileGroup.POST("/urls", fileHandler.SignURLs)
+			fileGroup.POST("/cleanup", middleware.PlatformAdminAuth(), fileHandler.Cleanup)
+		}
+
+		// 报价(SKU)路由：供应商报价、调价、启停与批次检测报告
+		quoteGroup := apiGroup.Group("/quotes")
+		quoteGroup.Use(middleware.AuthMiddleware(), supplierRoles)
+		{
+			quoteGroup.GET("", quoteHandler.ListMine)
+			quoteGroup.POST("", quoteHandler.Create)
+			quoteGroup.PUT("/:id/price", quoteHandler.UpdatePrice)
+			quoteGroup.PUT("/:id/status", quoteHandler.UpdateStatus)
+			quoteGroup.POST("/reports/validate", batchReportHandler.Validate)
+			quoteGroup.GET("/:id/reports", batchReportHandler.QuoteReports)
+			quoteGroup.POST("/:id/reports", batchReportHandler.Upload)
+			quoteGroup.DELETE("/:id/reports/:batchNo", batchReportHandler.Remove)
+		}
+
+		// 指导价路由：学校设置指导价、复核超价报价，查看超出价格区间的报价
+		guidePriceGroup := apiGroup.Group("/guide-prices")
+		guidePriceGroup.Use(middleware.AuthMiddleware(), schoolRoles)
+		{
+			guidePriceGroup.GET("", guidePriceHandler.List)
+			guidePriceGroup.GET("/report", guidePriceHandler.Report)
+			guidePriceGroup.GET("/review-queue", guidePriceHandler.ReviewQueue)
+			guidePriceGroup.PUT("/:productId", schoolAdmin, guidePriceHandler.Set)
+			guidePriceGroup.DELETE("/:productId", schoolAdmin, guidePriceHandler.Delete)
+			guidePriceGroup.POST("/review-queue/:id/approve", guidePriceHandler.ApproveQuote)
+			guidePriceGroup.POST("/review-queue/:id/reject", guidePriceHandler.RejectQuote)
+		}
+
+		// 比价路由：按商品、按分类比价，以及按采购清单挑选最低价报价
+		comparisonGroup := apiGroup.Group("/comparisons")
+		comparisonGroup.Use(middleware.AuthMiddleware())
+		{
+			comparisonGroup.GET("/products/:id", comparisonHandler.Product)
+			comparisonGroup.GET("/categories/:id", comparisonHandler.Category)
+			comparisonGroup.POST("/basket", comparisonHandler.Basket)
+		}
+
+		// 购物车路由：仅食堂和商户可用
+		cartGroup := apiGroup.Group("/cart")
+		cartGroup.Use(middleware.AuthMiddleware(), buyerRoles)
+		{
+			cartGroup.GET("", cartHandler.Get)
+			cartGroup.DELETE("", cartHandler.Clear)
+			cartGroup.POST("/items", cartHandler.Add)
+			cartGroup.PUT("/items/:id", cartHandler.Update)
+			cartGroup.DELETE("/items/:id", cartHandler.Remove)
+			cartGroup.POST("/confirm-prices", cartHandler.ConfirmPrices)
+		}
+
+		// 订单路由
+		orderGroup := apiGroup.Group("/orders")
+		orderGroup.Use(middleware.AuthMiddleware())
+		{
+			orderGroup.POST("/checkout", buyerRoles, orderHandler.Checkout)
+			orderGroup.GET("/inbox", supplierRoles, orderHandler.Inbox)
+			orderGroup.GET("/:id", orderHandler.Get)
+			orderGroup.PUT("/:id/status", orderHandler.UpdateStatus)
+			orderGroup.POST("/:id/accept", supplierRoles, orderHandler.Accept)
+			orderGroup.POST("/:id/reject", supplierRoles, orderHandler.Reject)
+			orderGroup.PUT("/:id/items", supplierRoles, orderHandler.AdjustItems)
+			orderGroup.POST("/:id/receive", buyerRoles, receivingHandler.Receive)
+			orderGroup.GET("/:id/receipt", receivingHandler.GetReceipt)
+		}
+
+		// 分拣路由：按送达日期汇总已接单订单生成分拣单
+		pickingGroup := apiGroup.Group("/picking")
+		pickingGroup.Use(middleware.AuthMiddleware(), supplierRoles)
+		{
+			pickingGroup.GET("", pickingHandler.List)
+			pickingGroup.POST("", pickingHandler.Create)
+			pickingGroup.GET("/:id", pickingHandler.Get)
+			pickingGroup.GET("/:id/sheet", pickingHandler.Sheet)
+			pickingGroup.PUT("/:id/picker", pickingHandler.AssignPicker)
+			pickingGroup.PUT("/:id/lines/:lineId", pickingHandler.PickLine)
+			pickingGroup.POST("/:id/complete", pickingHandler.Complete)
+		}
+
+		// 配送路由：排车、指派司机、登记出发和送达
+		deliveryGroup := apiGroup.Group("/deliveries")
+		deliveryGroup.Use(middleware.AuthMiddleware(), supplierRoles)
+		{
+			deliveryGroup.GET("", deliveryHandler.List)
+			deliveryGroup.POST("", deliveryHandler.Create)
+			deliveryGroup.GET("/drivers/:driverId/tasks", deliveryHandler.DriverTasks)
+			deliveryGroup.GET("/:id", deliveryHandler.Get)
+			deliveryGroup.PUT("/:id/driver", deliveryHandler.AssignDriver)
+			deliveryGroup.DELETE("/:id", deliveryHandler.Cancel)
+			deliveryGroup.POST("/:id/depart", deliveryHandler.Depart)
+			deliveryGroup.POST("/:id/orders/:orderId/arrive", deliveryHandler.Arrive)
+		}
+
+		// 售后路由：买家申请、撤销和申诉，供应商审核，学校仲裁
+		afterSaleGroup := apiGroup.Group("/after-sales")
+		afterSaleGroup.Use(middleware.AuthMiddleware())
+		{
+			afterSaleGroup.GET("", afterSaleHandler.List)
+			afterSaleGroup.POST("", buyerRoles, afterSaleHandler.Apply)
+			afterSaleGroup.GET("/:id", afterSaleHandler.Get)
+			afterSaleGroup.POST("/:id/cancel", buyerRoles, afterSaleHandler.Cancel)
+			afterSaleGroup.POST("/:id/approve", supplierRoles, afterSaleHandler.Approve)
+			afterSaleGroup.POST("/:id/reject", supplierRoles, afterSaleHandler.Reject)
+			afterSaleGroup.POST("/:id/dispute", buyerRoles, afterSaleHandler.Dispute)
+			afterSaleGroup.POST("/:id/arbitrate", schoolRoles, afterSaleHandler.Arbitrate)
+		}
+
+		// 采购统计路由：统计范围由服务按调用方所属组织限定
+		analyticsGroup := apiGroup.Group("/analytics")
+		analyticsGroup.Use(middleware.AuthMiddleware())
+		{
+			analyticsGroup.GET("/summary", analyticsHandler.Summary)
+			analyticsGroup.GET("/trend", analyticsHandler.Trend)
+			analyticsGroup.GET("/breakdown", analyticsHandler.Breakdown)
+			analyticsGroup.GET("/top-products", analyticsHandler.TopProducts)
+			analyticsGroup.GET("/prices", analyticsHandler.PricesPaid)
+		}
+
+		// 定期订单路由：食堂和商户维护定期下单模板
+		standingGroup := apiGroup.Group("/standing-orders")
+		standingGroup.Use(middleware.AuthMiddleware(), buyerRoles)
+		{
+			standingGroup.GET("", standingOrderHandler.List)
+			standingGroup.POST("", standingOrderHandler.Create)
+			standingGroup.GET("/:id", standingOrderHandler.Get)
+			standingGroup.PUT("/:id", standingOrderHandler.Update)
+			standingGroup.DELETE("/:id", standingOrderHandler.Delete)
+			standingGroup.POST("/:id/pause", standingOrderHandler.Pause)
+			standingGroup.POST("/:id/resume", standingOrderHandler.Resume)
+			standingGroup.PUT("/:id/skip-dates", standingOrderHandler.SetSkipDates)
+			standingGroup.GET("/:id/runs", standingOrderHandler.ListRuns)
+		}
+
+		// 站内通知路由
+		notificationGroup := apiGroup.Group("/notifications")
+		notificationGroup.Use(middleware.AuthMiddleware())
+		{
+			notificationGroup.GET("", notificationHandler.List)
+			notificationGroup.PUT("/read-all", notificationHandler.MarkAllRead)
+			notificationGroup.PUT("/:id/read", notificationHandler.MarkRead)
+		}
+
 		// 其他受保护的路由组
 		protectedGroup := apiGroup.Group("")
 		protectedGroup.Use(middleware.AuthMiddleware())
@@ -124,3 +445,39 @@ func Init() *gin.Engine {
 
 	return r
 }
+
+// newFileStorage 根据配置创建文件存储后端
+func newFileStorage() (storage.Storage, error) {
+	cfg := config.Cfg.Storage
+	secret := cfg.SignSecret
+	if secret == "" {
+		secret = config.Cfg.Jwt.Secret
+	}
+	store, err := storage.New(storage.Config{
+		Driver: cfg.Driver,
+		Local:  storage.LocalConfig{Dir: cfg.Local.Dir, BaseURL: cfg.Local.BaseURL},
+		S3: storage.S3Config{
+			Endpoint:  cfg.S3.Endpoint,
+			AccessKey: cfg.S3.AccessKey,
+			SecretKey: cfg.S3.SecretKey,
+			Bucket:    cfg.S3.Bucket,
+			Region:    cfg.S3.Region,
+			UseSSL:    cfg.S3.UseSSL,
+		},
+	}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("初始化文件存储失败: %w", err)
+	}
+	return store, nil
+}
+
+// fileOptions 根据配置生成文件服务的运行参数
+func fileOptions() service.FileOptions {
+	cfg := config.Cfg.Storage
+	return service.FileOptions{
+		MaxSize:      cfg.MaxSizeMB << 20,
+		AllowedTypes: cfg.AllowedTypes,
+		SignExpire:   time.Duration(cfg.SignExpire) * time.Minute,
+		OrphanGrace:  time.Duration(cfg.OrphanGraceHr) * time.Hour,
+	}
+}
@@ -18,6 +18,8 @@ var subordinateRoleMap = map[string]string{
 	"platform_admin": "platform_staff",
 	"school_admin":   "school_staff",
 	"supplier_admin": "supplier_staff",
+	"canteen_admin":  "canteen_staff",
+	"merchant_admin": "merchant_staff",
 }
 
 // IAccountService 定义账号服务接口
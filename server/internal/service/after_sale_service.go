@@ -0,0 +1,503 @@
+// server/internal/service/after_sale_service.go
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// IAfterSaleService 定义售后服务接口
+type IAfterSaleService interface {
+	// Apply 买家对已收货订单的明细申请售后，退款金额不能超过明细金额减去已申请和已退款的金额
+	Apply(req *model.ApplyAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error)
+	// List 分页列出当前用户可见的售后记录
+	List(filter model.AfterSaleFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdAfterSale, int64, error)
+	Get(id uint, claims *jwt.CustomClaims) (*model.OrdAfterSale, error)
+	// Cancel 买家撤销待审核或申诉中的售后
+	Cancel(id uint, claims *jwt.CustomClaims) (*model.OrdAfterSale, error)
+	// Approve 供应商同意售后，按申请金额生成退款流水
+	Approve(id uint, req *model.HandleAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error)
+	// Reject 供应商驳回售后，须填写意见
+	Reject(id uint, req *model.HandleAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error)
+	// Dispute 买家对供应商的驳回提出申诉，交由所属学校仲裁，每条售后只能申诉一次
+	Dispute(id uint, req *model.DisputeAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error)
+	// Arbitrate 学校仲裁申诉中的售后，支持退款时生成退款流水，仲裁结果为最终结果
+	Arbitrate(id uint, req *model.ArbitrateAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error)
+}
+
+// afterSaleService 实现了 IAfterSaleService 接口
+type afterSaleService struct {
+	afterSaleRepo repository.IAfterSaleRepository
+	orderRepo     repository.IOrderRepository
+	orgRepo       repository.IOrganizationRepository
+	fileRepo      repository.IFileRepository
+	fileService   IFileService
+}
+
+// NewAfterSaleService 创建一个新的 afterSaleService 实例
+func NewAfterSaleService(afterSaleRepo repository.IAfterSaleRepository, orderRepo repository.IOrderRepository, orgRepo repository.IOrganizationRepository, fileRepo repository.IFileRepository, fileService IFileService) IAfterSaleService {
+	return &afterSaleService{
+		afterSaleRepo: afterSaleRepo,
+		orderRepo:     orderRepo,
+		orgRepo:       orgRepo,
+		fileRepo:      fileRepo,
+		fileService:   fileService,
+	}
+}
+
+// Apply 申请售后
+func (s *afterSaleService) Apply(req *model.ApplyAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, errors.New("仅食堂和商户可以申请售后")
+	}
+	evidences := uniqueKeys(req.Evidences)
+	if err := checkUploadedFiles(s.fileRepo, evidences, claims.OrgID); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(evidences)
+	if err != nil {
+		return nil, err
+	}
+
+	var afterSale *model.OrdAfterSale
+	err = s.afterSaleRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		orderRepo := repository.NewOrderRepository(tx)
+		afterSaleRepo := repository.NewAfterSaleRepository(tx)
+
+		// 1. 锁定订单明细，同一明细的并发申请依次校验可退金额
+		item, err := orderRepo.LockItem(req.OrderItemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("订单明细不存在")
+			}
+			return err
+		}
+		order, err := getVisibleOrder(orderRepo, s.orgRepo, item.OrderID, claims)
+		if err != nil {
+			return err
+		}
+		if order.Status != model.OrderStatusReceived && order.Status != model.OrderStatusCompleted {
+			return fmt.Errorf("订单处于 [%s] 状态，收货后才能申请售后", orderStatusLabel(order.Status))
+		}
+		if req.Quantity > item.Quantity {
+			return fmt.Errorf("售后数量不能超过明细数量 %d", item.Quantity)
+		}
+		if req.Type == model.AfterSaleTypeReturn && req.Quantity == 0 {
+			return errors.New("退货退款需填写退货数量")
+		}
+
+		// 2. 可退金额为明细金额减去已占用的售后金额
+		used, err := afterSaleRepo.SumActiveByItem(item.ID, 0)
+		if err != nil {
+			return err
+		}
+		available := roundMoney(item.Amount - used)
+		amount := roundMoney(req.Amount)
+		if amount > available {
+			return fmt.Errorf("退款金额不能超过可退金额 %.2f", available)
+		}
+
+		afterSale = &model.OrdAfterSale{
+			OrderID:     order.ID,
+			OrderItemID: item.ID,
+			MerchantID:  order.MerchantID,
+			SupplierID:  order.SupplierID,
+			Source:      model.AfterSaleSourceApply,
+			Type:        req.Type,
+			Quantity:    req.Quantity,
+			Reason:      req.Reason,
+			Evidences:   string(raw),
+			ApplyAmount: amount,
+			Status:      model.AfterSalePending,
+			OperatorID:  claims.UserID,
+		}
+		return afterSaleRepo.Create(afterSale)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.fill(afterSale)
+}
+
+// List 分页列出售后记录：买家和供应商只能查看自己的售后，学校可以查看本校买家的售后，平台不做限制
+func (s *afterSaleService) List(filter model.AfterSaleFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdAfterSale, int64, error) {
+	filter.MerchantIDs = nil
+	filter.SupplierID = 0
+	switch {
+	case isPlatformRole(claims.Role):
+	case isBuyerRole(claims.Role):
+		filter.MerchantIDs = []uint{claims.OrgID}
+	case isSupplierRole(claims.Role):
+		filter.SupplierID = claims.OrgID
+	case isSchoolRole(claims.Role):
+		buyerIDs, err := buyerOrgIDsOfSchool(s.orgRepo, claims.OrgID)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.MerchantIDs = buyerIDs
+	default:
+		return nil, 0, errors.New("当前角色无权查看售后")
+	}
+
+	afterSales, total, err := s.afterSaleRepo.List(filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.fillList(afterSales); err != nil {
+		return nil, 0, err
+	}
+	return afterSales, total, nil
+}
+
+// Get 获取售后详情
+func (s *afterSaleService) Get(id uint, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	afterSale, err := s.getVisible(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.fill(afterSale)
+}
+
+// Cancel 买家撤销售后
+func (s *afterSaleService) Cancel(id uint, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, errors.New("仅申请方可以撤销售后")
+	}
+	afterSale, err := s.getVisible(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if afterSale.Status != model.AfterSalePending && afterSale.Status != model.AfterSaleDisputed {
+		return nil, fmt.Errorf("售后处于 [%s] 状态，不能撤销", afterSaleStatusLabel(afterSale.Status))
+	}
+	return s.resolve(afterSale, model.AfterSaleCancelled, nil, claims)
+}
+
+// Approve 供应商同意售后
+func (s *afterSaleService) Approve(id uint, req *model.HandleAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	afterSale, err := s.getPendingForSupplier(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(afterSale, model.AfterSaleApproved, map[string]interface{}{
+		"refund_amount":   afterSale.ApplyAmount,
+		"supplier_remark": req.Remark,
+	}, claims)
+}
+
+// Reject 供应商驳回售后
+func (s *afterSaleService) Reject(id uint, req *model.HandleAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	if req.Remark == "" {
+		return nil, errors.New("驳回售后需填写意见")
+	}
+	afterSale, err := s.getPendingForSupplier(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.resolve(afterSale, model.AfterSaleRejected, map[string]interface{}{
+		"supplier_remark": req.Remark,
+	}, claims)
+}
+
+// Dispute 买家申诉
+func (s *afterSaleService) Dispute(id uint, req *model.DisputeAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, errors.New("仅申请方可以提出申诉")
+	}
+	afterSale, err := s.getVisible(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if afterSale.Status != model.AfterSaleRejected {
+		return nil, errors.New("只有被驳回的售后可以申诉")
+	}
+	if afterSale.Arbitrated {
+		return nil, errors.New("该售后已经学校仲裁，不能再次申诉")
+	}
+	return s.resolve(afterSale, model.AfterSaleDisputed, map[string]interface{}{
+		"dispute_reason": req.Reason,
+	}, claims)
+}
+
+// Arbitrate 学校仲裁
+func (s *afterSaleService) Arbitrate(id uint, req *model.ArbitrateAfterSaleRequest, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	if !isSchoolRole(claims.Role) {
+		return nil, errors.New("仅学校可以仲裁售后")
+	}
+	afterSale, err := s.getVisible(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if afterSale.Status != model.AfterSaleDisputed {
+		return nil, errors.New("只有申诉中的售后可以仲裁")
+	}
+
+	fields := map[string]interface{}{
+		"arbitrated":         true,
+		"arbitration_remark": req.Remark,
+	}
+	if !req.Approve {
+		return s.resolve(afterSale, model.AfterSaleRejected, fields, claims)
+	}
+	amount := afterSale.ApplyAmount
+	if req.Amount != nil {
+		amount = roundMoney(*req.Amount)
+		if amount > afterSale.ApplyAmount {
+			return nil, fmt.Errorf("仲裁退款金额不能超过申请金额 %.2f", afterSale.ApplyAmount)
+		}
+	}
+	fields["refund_amount"] = amount
+	return s.resolve(afterSale, model.AfterSaleApproved, fields, claims)
+}
+
+// resolve 变更售后状态。同意退款时生成退款流水；订单的售后全部处理完毕后，已收货的订单自动完成。
+func (s *afterSaleService) resolve(afterSale *model.OrdAfterSale, to int8, fields map[string]interface{}, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	now := time.Now()
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	fields["handler_id"] = claims.UserID
+	fields["handled_at"] = now
+
+	err := s.afterSaleRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		afterSaleRepo := repository.NewAfterSaleRepository(tx)
+		orderRepo := repository.NewOrderRepository(tx)
+
+		// 被驳回的售后经申诉、仲裁后会重新占用可退金额，需锁定明细后重新校验
+		if to == model.AfterSaleDisputed || to == model.AfterSaleApproved {
+			amount := afterSale.ApplyAmount
+			if to == model.AfterSaleApproved {
+				amount = fields["refund_amount"].(float64)
+			}
+			if err := checkRefundable(orderRepo, afterSaleRepo, afterSale, amount); err != nil {
+				return err
+			}
+		}
+
+		affected, err := afterSaleRepo.UpdateStatus(afterSale.ID, afterSale.Status, to, fields)
+		if err != nil {
+			return fmt.Errorf("更新售后状态失败: %w", err)
+		}
+		if affected == 0 {
+			return errors.New("售后状态已变化，请刷新后重试")
+		}
+
+		if to == model.AfterSaleApproved {
+			if err := s.postRefundBill(repository.NewBillRepository(tx), afterSale, fields["refund_amount"].(float64), now); err != nil {
+				return err
+			}
+		}
+		return completeSettledOrder(orderRepo, afterSaleRepo, afterSale.OrderID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.afterSaleRepo.GetByID(afterSale.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.fill(updated)
+}
+
+// checkRefundable 锁定售后对应的订单明细，校验明细金额减去其他售后占用的金额后仍足够退款
+func checkRefundable(orderRepo repository.IOrderRepository, afterSaleRepo repository.IAfterSaleRepository, afterSale *model.OrdAfterSale, amount float64) error {
+	item, err := orderRepo.LockItem(afterSale.OrderItemID)
+	if err != nil {
+		return err
+	}
+	used, err := afterSaleRepo.SumActiveByItem(item.ID, afterSale.ID)
+	if err != nil {
+		return err
+	}
+	if available := roundMoney(item.Amount - used); amount > available {
+		return fmt.Errorf("退款金额不能超过可退金额 %.2f", available)
+	}
+	return nil
+}
+
+// postRefundBill 为通过的售后生成退款流水，退款从供应商的订单收入中支出
+func (s *afterSaleService) postRefundBill(billRepo repository.IBillRepository, afterSale *model.OrdAfterSale, amount float64, now time.Time) error {
+	schoolID, err := schoolIDOf(s.orgRepo, afterSale.MerchantID)
+	if err != nil {
+		return err
+	}
+	bill := &model.FinBill{
+		BillNo:      fmt.Sprintf("RF%s%08d", now.Format("20060102"), afterSale.ID),
+		OrderID:     afterSale.OrderID,
+		AfterSaleID: afterSale.ID,
+		SchoolID:    schoolID,
+		SupplierID:  afterSale.SupplierID,
+		Amount:      amount,
+		BillType:    model.BillTypeRefund,
+		IoDirection: model.BillExpense,
+	}
+	if err := billRepo.Create(bill); err != nil {
+		return fmt.Errorf("生成退款流水失败: %w", err)
+	}
+	return nil
+}
+
+// completeSettledOrder 已收货的订单没有待处理的售后时，由系统将订单完成
+func completeSettledOrder(orderRepo repository.IOrderRepository, afterSaleRepo repository.IAfterSaleRepository, orderID uint) error {
+	order, err := orderRepo.GetByID(orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != model.OrderStatusReceived {
+		return nil
+	}
+	open, err := afterSaleRepo.CountOpenByOrder(orderID)
+	if err != nil {
+		return err
+	}
+	if open > 0 {
+		return nil
+	}
+	return transitionOrder(orderRepo, order, model.OrderStatusCompleted, model.OrderActorSystem, nil, "售后处理完毕，自动完成")
+}
+
+// getPendingForSupplier 获取本供应商待审核的售后
+func (s *afterSaleService) getPendingForSupplier(id uint, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	if !isSupplierRole(claims.Role) {
+		return nil, errors.New("仅供应商可以审核售后")
+	}
+	afterSale, err := s.getVisible(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if afterSale.Status != model.AfterSalePending {
+		return nil, fmt.Errorf("售后处于 [%s] 状态，不能审核", afterSaleStatusLabel(afterSale.Status))
+	}
+	return afterSale, nil
+}
+
+// getVisible 获取当前用户有权查看的售后，范围与订单一致
+func (s *afterSaleService) getVisible(id uint, claims *jwt.CustomClaims) (*model.OrdAfterSale, error) {
+	afterSale, err := s.afterSaleRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("售后记录不存在")
+		}
+		return nil, err
+	}
+
+	switch {
+	case isPlatformRole(claims.Role):
+		return afterSale, nil
+	case isBuyerRole(claims.Role):
+		if afterSale.MerchantID == claims.OrgID {
+			return afterSale, nil
+		}
+	case isSupplierRole(claims.Role):
+		if afterSale.SupplierID == claims.OrgID {
+			return afterSale, nil
+		}
+	case isSchoolRole(claims.Role):
+		buyerIDs, err := buyerOrgIDsOfSchool(s.orgRepo, claims.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		for _, buyerID := range buyerIDs {
+			if buyerID == afterSale.MerchantID {
+				return afterSale, nil
+			}
+		}
+	}
+	return nil, errors.New("无权查看此售后")
+}
+
+// fill 为单条售后填充订单号、商品名称和凭证图片
+func (s *afterSaleService) fill(afterSale *model.OrdAfterSale) (*model.OrdAfterSale, error) {
+	list := []model.OrdAfterSale{*afterSale}
+	if err := s.fillList(list); err != nil {
+		return nil, err
+	}
+	return &list[0], nil
+}
+
+// fillList 为售后记录填充订单号、商品名称和凭证图片，凭证地址生成失败时只记录日志
+func (s *afterSaleService) fillList(afterSales []model.OrdAfterSale) error {
+	if len(afterSales) == 0 {
+		return nil
+	}
+	orderIDs := make([]uint, 0, len(afterSales))
+	for _, a := range afterSales {
+		orderIDs = append(orderIDs, a.OrderID)
+	}
+	orderIDs = uniqueIDs(orderIDs)
+	orders, err := s.orderRepo.ListByIDs(orderIDs)
+	if err != nil {
+		return err
+	}
+	items, err := s.orderRepo.ListItemsByOrders(orderIDs)
+	if err != nil {
+		return err
+	}
+	orderNos := make(map[uint]string, len(orders))
+	for _, order := range orders {
+		orderNos[order.ID] = order.OrderNo
+	}
+	names := make(map[uint]string, len(items))
+	for _, item := range items {
+		names[item.ID] = item.SnapName
+	}
+
+	evidences := make([][]string, len(afterSales))
+	var keys []string
+	for i := range afterSales {
+		afterSales[i].OrderNo = orderNos[afterSales[i].OrderID]
+		afterSales[i].ProductName = names[afterSales[i].OrderItemID]
+		afterSales[i].EvidenceImages = []model.ImageURLs{}
+		if afterSales[i].Evidences == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(afterSales[i].Evidences), &evidences[i]); err != nil {
+			log.Printf("解析售后 #%d 凭证失败: %v", afterSales[i].ID, err)
+			continue
+		}
+		keys = append(keys, evidences[i]...)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	urls, err := s.fileService.ImageURLs(keys)
+	if err != nil {
+		log.Printf("生成售后凭证地址失败: %v", err)
+		return nil
+	}
+	for i := range afterSales {
+		for _, key := range evidences[i] {
+			if u, ok := urls[key]; ok {
+				afterSales[i].EvidenceImages = append(afterSales[i].EvidenceImages, *u)
+			}
+		}
+	}
+	return nil
+}
+
+// afterSaleStatusLabel 返回售后状态的中文名称
+func afterSaleStatusLabel(status int8) string {
+	switch status {
+	case model.AfterSalePending:
+		return "待审核"
+	case model.AfterSaleApproved:
+		return "已通过"
+	case model.AfterSaleRejected:
+		return "已驳回"
+	case model.AfterSaleDisputed:
+		return "申诉中"
+	case model.AfterSaleCancelled:
+		return "已撤销"
+	}
+	return fmt.Sprintf("未知状态(%d)", status)
+}
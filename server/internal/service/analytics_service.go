@@ -0,0 +1,357 @@
+// server/internal/service/analytics_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+)
+
+// analyticsCacheTTL 统计结果的缓存时间，统计数据允许有几分钟的延迟
+const analyticsCacheTTL = 5 * time.Minute
+
+// analyticsCacheLimit 缓存条目数上限，超出时先清理过期条目，仍超出则清空
+const analyticsCacheLimit = 1000
+
+// IAnalyticsService 定义采购统计服务接口。统计范围按调用方所属组织限定：
+// 买家只能统计自己的采购，供应商只能统计自己的销售，学校可以统计本校所有买家，平台不做限制。
+type IAnalyticsService interface {
+	// Summary 返回采购概览：订单数、金额、退款和各状态订单数
+	Summary(query model.AnalyticsQuery, claims *jwt.CustomClaims) (*model.AnalyticsSummary, error)
+	// Trend 按日、周或月返回采购金额趋势
+	Trend(query model.AnalyticsQuery, granularity string, claims *jwt.CustomClaims) ([]model.SpendPoint, error)
+	// Breakdown 按分类、供应商或食堂返回采购金额分布
+	Breakdown(query model.AnalyticsQuery, groupBy string, claims *jwt.CustomClaims) ([]model.SpendGroup, error)
+	// TopProducts 返回采购金额最高的商品
+	TopProducts(query model.AnalyticsQuery, limit int, claims *jwt.CustomClaims) ([]model.TopProduct, error)
+	// PricesPaid 返回采购金额最高的商品的实际均价与指导价对比
+	PricesPaid(query model.AnalyticsQuery, limit int, claims *jwt.CustomClaims) ([]model.PricePaid, error)
+}
+
+// analyticsCacheEntry 缓存的统计结果
+type analyticsCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+// analyticsService 实现了 IAnalyticsService 接口
+type analyticsService struct {
+	analyticsRepo repository.IAnalyticsRepository
+	orderRepo     repository.IOrderRepository
+	orgRepo       repository.IOrganizationRepository
+
+	mu    sync.Mutex
+	cache map[string]analyticsCacheEntry
+}
+
+// NewAnalyticsService 创建一个新的 analyticsService 实例
+func NewAnalyticsService(analyticsRepo repository.IAnalyticsRepository, orderRepo repository.IOrderRepository, orgRepo repository.IOrganizationRepository) IAnalyticsService {
+	return &analyticsService{
+		analyticsRepo: analyticsRepo,
+		orderRepo:     orderRepo,
+		orgRepo:       orgRepo,
+		cache:         make(map[string]analyticsCacheEntry),
+	}
+}
+
+// Summary 采购概览
+func (s *analyticsService) Summary(query model.AnalyticsQuery, claims *jwt.CustomClaims) (*model.AnalyticsSummary, error) {
+	filter, err := s.scope(query, claims)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.cached(analyticsCacheKey("summary", filter, ""), func() (interface{}, error) {
+		totals, err := s.analyticsRepo.Totals(filter)
+		if err != nil {
+			return nil, err
+		}
+		refund, err := s.analyticsRepo.RefundTotal(filter)
+		if err != nil {
+			return nil, err
+		}
+		start, end := filter.StartDate, filter.EndDate
+		counts, err := s.orderRepo.CountByStatus(model.OrderListFilter{
+			MerchantIDs: filter.MerchantIDs,
+			SupplierID:  filter.SupplierID,
+			StartDate:   &start,
+			EndDate:     &end,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		summary := &model.AnalyticsSummary{
+			StartDate:    filter.StartDate.Format("2006-01-02"),
+			EndDate:      filter.EndDate.AddDate(0, 0, -1).Format("2006-01-02"),
+			OrderCount:   totals.OrderCount,
+			TotalAmount:  roundMoney(totals.Amount),
+			RefundAmount: roundMoney(refund),
+			NetAmount:    roundMoney(totals.Amount - refund),
+			StatusCounts: counts,
+		}
+		if totals.OrderCount > 0 {
+			summary.AvgOrderAmount = roundMoney(totals.Amount / float64(totals.OrderCount))
+		}
+		return summary, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*model.AnalyticsSummary), nil
+}
+
+// Trend 采购金额趋势
+func (s *analyticsService) Trend(query model.AnalyticsQuery, granularity string, claims *jwt.CustomClaims) ([]model.SpendPoint, error) {
+	switch granularity {
+	case model.AnalyticsByDay, model.AnalyticsByWeek, model.AnalyticsByMonth:
+	default:
+		return nil, fmt.Errorf("不支持的统计粒度 [%s]", granularity)
+	}
+	filter, err := s.scope(query, claims)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.cached(analyticsCacheKey("trend", filter, granularity), func() (interface{}, error) {
+		points, err := s.analyticsRepo.Trend(filter, granularity)
+		if err != nil {
+			return nil, err
+		}
+		for i := range points {
+			points[i].Amount = roundMoney(points[i].Amount)
+		}
+		return points, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]model.SpendPoint), nil
+}
+
+// Breakdown 采购金额分布
+func (s *analyticsService) Breakdown(query model.AnalyticsQuery, groupBy string, claims *jwt.CustomClaims) ([]model.SpendGroup, error) {
+	var load func(model.AnalyticsFilter) ([]model.SpendGroup, error)
+	switch groupBy {
+	case model.AnalyticsGroupCategory:
+		load = s.analyticsRepo.ByCategory
+	case model.AnalyticsGroupSupplier:
+		load = s.analyticsRepo.BySupplier
+	case model.AnalyticsGroupCanteen:
+		load = s.analyticsRepo.ByMerchant
+	default:
+		return nil, fmt.Errorf("不支持的分组维度 [%s]", groupBy)
+	}
+	filter, err := s.scope(query, claims)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.cached(analyticsCacheKey("breakdown", filter, groupBy), func() (interface{}, error) {
+		groups, err := load(filter)
+		if err != nil {
+			return nil, err
+		}
+		if groupBy == model.AnalyticsGroupCategory {
+			for i := range groups {
+				if groups[i].ID == 0 {
+					groups[i].Name = "未分类"
+				}
+			}
+		} else if err := s.fillOrgNames(groups); err != nil {
+			return nil, err
+		}
+
+		total := 0.0
+		for _, g := range groups {
+			total += g.Amount
+		}
+		for i := range groups {
+			groups[i].Amount = roundMoney(groups[i].Amount)
+			if total > 0 {
+				groups[i].Share = roundMoney(groups[i].Amount / total * 100)
+			}
+		}
+		return groups, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]model.SpendGroup), nil
+}
+
+// TopProducts 采购金额最高的商品
+func (s *analyticsService) TopProducts(query model.AnalyticsQuery, limit int, claims *jwt.CustomClaims) ([]model.TopProduct, error) {
+	filter, err := s.scope(query, claims)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.cached(analyticsCacheKey("top-products", filter, fmt.Sprint(limit)), func() (interface{}, error) {
+		products, err := s.analyticsRepo.TopProducts(filter, limit)
+		if err != nil {
+			return nil, err
+		}
+		for i := range products {
+			products[i].Amount = roundMoney(products[i].Amount)
+		}
+		return products, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]model.TopProduct), nil
+}
+
+// PricesPaid 实际均价与指导价对比
+func (s *analyticsService) PricesPaid(query model.AnalyticsQuery, limit int, claims *jwt.CustomClaims) ([]model.PricePaid, error) {
+	filter, err := s.scope(query, claims)
+	if err != nil {
+		return nil, err
+	}
+	value, err := s.cached(analyticsCacheKey("prices", filter, fmt.Sprint(limit)), func() (interface{}, error) {
+		prices, err := s.analyticsRepo.PricesPaid(filter, limit)
+		if err != nil {
+			return nil, err
+		}
+		for i := range prices {
+			p := &prices[i]
+			p.Amount = roundMoney(p.Amount)
+			if p.Quantity > 0 {
+				p.AvgPrice = roundMoney(p.Amount / float64(p.Quantity))
+			}
+			if p.GuidePrice != nil && *p.GuidePrice > 0 {
+				deviation := roundMoney((p.AvgPrice - *p.GuidePrice) / *p.GuidePrice * 100)
+				p.Deviation = &deviation
+			}
+		}
+		return prices, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.([]model.PricePaid), nil
+}
+
+// scope 校验时间范围，并按调用方所属组织收窄统计范围
+func (s *analyticsService) scope(query model.AnalyticsQuery, claims *jwt.CustomClaims) (model.AnalyticsFilter, error) {
+	var filter model.AnalyticsFilter
+
+	// 1. 时间范围：结束日期当天包含在内，默认统计最近 30 天
+	end := startOfDay(time.Now()).AddDate(0, 0, 1)
+	if query.EndDate != nil {
+		end = startOfDay(*query.EndDate).AddDate(0, 0, 1)
+	}
+	start := end.AddDate(0, 0, -30)
+	if query.StartDate != nil {
+		start = startOfDay(*query.StartDate)
+	}
+	if !start.Before(end) {
+		return filter, errors.New("开始日期不能晚于结束日期")
+	}
+	if end.Sub(start) > model.AnalyticsMaxDays*24*time.Hour {
+		return filter, fmt.Errorf("统计时间跨度不能超过 %d 天", model.AnalyticsMaxDays)
+	}
+	filter.StartDate = start
+	filter.EndDate = end
+
+	// 2. 组织范围
+	filter.SupplierID = query.SupplierID
+	if query.MerchantID != 0 {
+		filter.MerchantIDs = []uint{query.MerchantID}
+	}
+	switch {
+	case isPlatformRole(claims.Role):
+	case isBuyerRole(claims.Role):
+		if query.MerchantID != 0 && query.MerchantID != claims.OrgID {
+			return filter, errors.New("无权查看其他单位的采购统计")
+		}
+		filter.MerchantIDs = []uint{claims.OrgID}
+	case isSupplierRole(claims.Role):
+		if query.SupplierID != 0 && query.SupplierID != claims.OrgID {
+			return filter, errors.New("无权查看其他供应商的销售统计")
+		}
+		filter.SupplierID = claims.OrgID
+	case isSchoolRole(claims.Role):
+		buyerIDs, err := buyerOrgIDsOfSchool(s.orgRepo, claims.OrgID)
+		if err != nil {
+			return filter, err
+		}
+		if query.MerchantID == 0 {
+			filter.MerchantIDs = buyerIDs
+			break
+		}
+		for _, id := range buyerIDs {
+			if id == query.MerchantID {
+				return filter, nil
+			}
+		}
+		return filter, errors.New("无权查看其他学校的采购统计")
+	default:
+		return filter, errors.New("当前角色无权查看采购统计")
+	}
+	return filter, nil
+}
+
+// fillOrgNames 为按供应商或买家分组的统计填充组织名称
+func (s *analyticsService) fillOrgNames(groups []model.SpendGroup) error {
+	ids := make([]uint, 0, len(groups))
+	for _, g := range groups {
+		ids = append(ids, g.ID)
+	}
+	orgs, err := s.orgRepo.ListByIDs(ids)
+	if err != nil {
+		return err
+	}
+	names := make(map[uint]string, len(orgs))
+	for _, org := range orgs {
+		names[org.ID] = org.Name
+	}
+	for i := range groups {
+		groups[i].Name = names[groups[i].ID]
+	}
+	return nil
+}
+
+// cached 返回缓存的统计结果，缓存不存在或已过期时调用 load 重新统计。
+// 缓存的结果会被多个请求共享，调用方不能修改。
+func (s *analyticsService) cached(key string, load func() (interface{}, error)) (interface{}, error) {
+	now := time.Now()
+	s.mu.Lock()
+	entry, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= analyticsCacheLimit {
+		for k, e := range s.cache {
+			if !now.Before(e.expiresAt) {
+				delete(s.cache, k)
+			}
+		}
+		if len(s.cache) >= analyticsCacheLimit {
+			s.cache = make(map[string]analyticsCacheEntry)
+		}
+	}
+	s.cache[key] = analyticsCacheEntry{value: value, expiresAt: now.Add(analyticsCacheTTL)}
+	return value, nil
+}
+
+// analyticsCacheKey 生成统计结果的缓存键，买家范围为 nil（不限制）和为空（无可统计的买家）需要区分
+func analyticsCacheKey(kind string, filter model.AnalyticsFilter, extra string) string {
+	merchants := "*"
+	if filter.MerchantIDs != nil {
+		merchants = fmt.Sprint(filter.MerchantIDs)
+	}
+	return fmt.Sprintf("%s|%s|%d|%d|%d|%s", kind, merchants, filter.SupplierID,
+		filter.StartDate.Unix(), filter.EndDate.Unix(), extra)
+}
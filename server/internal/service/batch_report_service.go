@@ -0,0 +1,319 @@
+// server/internal/service/batch_report_service.go
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// IBatchReportService 定义报价批次检测报告服务接口
+type IBatchReportService interface {
+	// Upload 供应商为自己的报价上传检测报告，同一批次号重复上传时覆盖原报告
+	Upload(quoteID uint, req *model.UploadBatchReportRequest, claims *jwt.CustomClaims) (*model.QuoteReports, error)
+	// Validate 只校验检测报告并返回计算出的有效期，不保存
+	Validate(req *model.ValidateBatchReportRequest, claims *jwt.CustomClaims) (*model.BatchReportView, error)
+	Remove(quoteID uint, batchNo string, claims *jwt.CustomClaims) error
+	// QuoteReports 供应商查看自己报价的检测报告及可下单状态
+	QuoteReports(quoteID uint, claims *jwt.CustomClaims) (*model.QuoteReports, error)
+	// ProductReports 查看商品各报价的检测报告，供应商只能看到自己的报价
+	ProductReports(productID uint, claims *jwt.CustomClaims) ([]model.QuoteReports, error)
+}
+
+// batchReportService 实现了 IBatchReportService 接口
+type batchReportService struct {
+	quoteRepo       repository.IQuoteRepository
+	productRepo     repository.IProductRepository
+	categoryService ICategoryService
+	productService  IProductService
+}
+
+// NewBatchReportService 创建一个新的 batchReportService 实例
+func NewBatchReportService(quoteRepo repository.IQuoteRepository, productRepo repository.IProductRepository, categoryService ICategoryService, productService IProductService) IBatchReportService {
+	return &batchReportService{
+		quoteRepo:       quoteRepo,
+		productRepo:     productRepo,
+		categoryService: categoryService,
+		productService:  productService,
+	}
+}
+
+// Upload 上传检测报告
+func (s *batchReportService) Upload(quoteID uint, req *model.UploadBatchReportRequest, claims *jwt.CustomClaims) (*model.QuoteReports, error) {
+	quote, policy, err := s.ownQuoteWithPolicy(quoteID, claims)
+	if err != nil {
+		return nil, err
+	}
+	report, err := buildBatchReport(req, policy, claims, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// 锁定报价行后再读写报告列表，避免并发上传或删除互相覆盖
+	err = s.quoteRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewQuoteRepository(tx)
+		locked, err := txRepo.LockByID(quote.ID)
+		if err != nil {
+			return err
+		}
+
+		// 覆盖同批次号的旧报告，按检测日期从新到旧保存，只保留最近的若干份
+		reports := []model.BatchReport{*report}
+		for _, r := range parseBatchReports(locked.BatchReports) {
+			if r.BatchNo != report.BatchNo {
+				reports = append(reports, r)
+			}
+		}
+		sortBatchReports(reports)
+		if len(reports) > model.MaxBatchReports {
+			reports = reports[:model.MaxBatchReports]
+		}
+
+		raw, err := json.Marshal(reports)
+		if err != nil {
+			return err
+		}
+		if err := txRepo.UpdateBatchReports(quote.ID, string(raw)); err != nil {
+			return fmt.Errorf("保存检测报告失败: %w", err)
+		}
+		quote.BatchReports = string(raw)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buildQuoteReports(quote, policy, time.Now()), nil
+}
+
+// Validate 校验检测报告
+func (s *batchReportService) Validate(req *model.ValidateBatchReportRequest, claims *jwt.CustomClaims) (*model.BatchReportView, error) {
+	_, policy, err := s.ownQuoteWithPolicy(req.QuoteID, claims)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	report, err := buildBatchReport(&req.UploadBatchReportRequest, policy, claims, now)
+	if err != nil {
+		return nil, err
+	}
+	view := viewBatchReport(*report, now)
+	return &view, nil
+}
+
+// Remove 删除指定批次的检测报告
+func (s *batchReportService) Remove(quoteID uint, batchNo string, claims *jwt.CustomClaims) error {
+	quote, _, err := s.ownQuoteWithPolicy(quoteID, claims)
+	if err != nil {
+		return err
+	}
+
+	return s.quoteRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewQuoteRepository(tx)
+		locked, err := txRepo.LockByID(quote.ID)
+		if err != nil {
+			return err
+		}
+
+		reports := parseBatchReports(locked.BatchReports)
+		kept := make([]model.BatchReport, 0, len(reports))
+		for _, r := range reports {
+			if r.BatchNo != batchNo {
+				kept = append(kept, r)
+			}
+		}
+		if len(kept) == len(reports) {
+			return errors.New("检测报告不存在")
+		}
+
+		raw, err := json.Marshal(kept)
+		if err != nil {
+			return err
+		}
+		return txRepo.UpdateBatchReports(quote.ID, string(raw))
+	})
+}
+
+// QuoteReports 查看自己报价的检测报告
+func (s *batchReportService) QuoteReports(quoteID uint, claims *jwt.CustomClaims) (*model.QuoteReports, error) {
+	quote, policy, err := s.ownQuoteWithPolicy(quoteID, claims)
+	if err != nil {
+		return nil, err
+	}
+	return buildQuoteReports(quote, policy, time.Now()), nil
+}
+
+// ProductReports 查看商品各报价的检测报告
+func (s *batchReportService) ProductReports(productID uint, claims *jwt.CustomClaims) ([]model.QuoteReports, error) {
+	detail, err := s.productService.GetProduct(productID, claims)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.categoryService.ReportPolicy(detail.Product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	quotes, err := s.quoteRepo.ListByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := make([]model.QuoteReports, 0, len(quotes))
+	for i := range quotes {
+		if isSupplierRole(claims.Role) && quotes[i].SupplierID != claims.OrgID {
+			continue
+		}
+		result = append(result, *buildQuoteReports(&quotes[i], policy, now))
+	}
+	return result, nil
+}
+
+// ownQuoteWithPolicy 获取当前供应商自己的报价及商品所在分类的检测报告要求
+func (s *batchReportService) ownQuoteWithPolicy(quoteID uint, claims *jwt.CustomClaims) (*model.ScmProductQuote, *model.ReportPolicy, error) {
+	quote, err := s.quoteRepo.GetByID(quoteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, errors.New("报价不存在")
+		}
+		return nil, nil, err
+	}
+	if quote.SupplierID != claims.OrgID {
+		return nil, nil, errors.New("无权操作其他供应商的报价")
+	}
+	product, err := s.productRepo.GetByID(quote.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	policy, err := s.categoryService.ReportPolicy(product.CategoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return quote, policy, nil
+}
+
+// buildBatchReport 校验上传请求并生成检测报告，有效期从检测日当天起算
+func buildBatchReport(req *model.UploadBatchReportRequest, policy *model.ReportPolicy, claims *jwt.CustomClaims, now time.Time) (*model.BatchReport, error) {
+	produced, err := time.ParseInLocation("2006-01-02", req.ProductionDate, time.Local)
+	if err != nil {
+		return nil, errors.New("生产日期格式错误")
+	}
+	inspected, err := time.ParseInLocation("2006-01-02", req.InspectionDate, time.Local)
+	if err != nil {
+		return nil, errors.New("检测日期格式错误")
+	}
+	today := startOfDay(now)
+	if inspected.After(today) {
+		return nil, errors.New("检测日期不能晚于今天")
+	}
+	if produced.After(inspected) {
+		return nil, errors.New("生产日期不能晚于检测日期")
+	}
+
+	// 农残超标的报告不能判定为合格
+	for _, r := range req.Residues {
+		if r.Value > r.Limit && req.Result == model.BatchReportPass {
+			return nil, fmt.Errorf("[%s] 残留量 %.4g 超过限量 %.4g，检测结论不能为合格", r.Name, r.Value, r.Limit)
+		}
+	}
+
+	expires := inspected.AddDate(0, 0, policy.ValidDays-1)
+	if expires.Before(today) {
+		return nil, fmt.Errorf("检测报告已超过 %d 天有效期", policy.ValidDays)
+	}
+
+	residues := req.Residues
+	if residues == nil {
+		residues = []model.ResidueItem{}
+	}
+	return &model.BatchReport{
+		BatchNo:        req.BatchNo,
+		ProductionDate: req.ProductionDate,
+		InspectionDate: req.InspectionDate,
+		Lab:            req.Lab,
+		Result:         req.Result,
+		Residues:       residues,
+		Attachment:     req.Attachment,
+		ExpiresOn:      expires.Format("2006-01-02"),
+		UploadedAt:     now,
+		UploaderID:     claims.UserID,
+	}, nil
+}
+
+// checkBatchReports 按检测报告要求判断报价能否下单：需要至少一份合格且未过期的报告，且最近一批检测合格
+func checkBatchReports(policy *model.ReportPolicy, raw string, now time.Time) error {
+	if !policy.Required {
+		return nil
+	}
+	reports := parseBatchReports(raw)
+	if len(reports) == 0 {
+		return errors.New("该分类商品需附带批次检测报告")
+	}
+	sortBatchReports(reports)
+	if reports[0].Result != model.BatchReportPass {
+		return fmt.Errorf("最近批次 [%s] 检测不合格", reports[0].BatchNo)
+	}
+	for _, r := range reports {
+		if viewBatchReport(r, now).Valid {
+			return nil
+		}
+	}
+	return errors.New("批次检测报告已过期")
+}
+
+// buildQuoteReports 组装报价的检测报告及可下单状态
+func buildQuoteReports(quote *model.ScmProductQuote, policy *model.ReportPolicy, now time.Time) *model.QuoteReports {
+	result := &model.QuoteReports{
+		QuoteID:    quote.ID,
+		SupplierID: quote.SupplierID,
+		Policy:     *policy,
+		Orderable:  true,
+		Reports:    []model.BatchReportView{},
+	}
+	if err := checkBatchReports(policy, quote.BatchReports, now); err != nil {
+		result.Orderable = false
+		result.Reason = err.Error()
+	}
+	for _, r := range parseBatchReports(quote.BatchReports) {
+		result.Reports = append(result.Reports, viewBatchReport(r, now))
+	}
+	return result
+}
+
+// viewBatchReport 计算检测报告的有效状态，有效期当天仍视为有效
+func viewBatchReport(r model.BatchReport, now time.Time) model.BatchReportView {
+	view := model.BatchReportView{BatchReport: r}
+	expires, err := time.ParseInLocation("2006-01-02", r.ExpiresOn, time.Local)
+	view.Expired = err != nil || expires.Before(startOfDay(now))
+	view.Valid = !view.Expired && r.Result == model.BatchReportPass
+	return view
+}
+
+// parseBatchReports 解析报价的 BatchReports 列，无法解析的旧数据视为没有报告
+func parseBatchReports(raw string) []model.BatchReport {
+	var reports []model.BatchReport
+	if raw == "" {
+		return reports
+	}
+	if err := json.Unmarshal([]byte(raw), &reports); err != nil {
+		return nil
+	}
+	return reports
+}
+
+// sortBatchReports 按检测日期从新到旧排序，同一天的按上传时间排序
+func sortBatchReports(reports []model.BatchReport) {
+	sort.SliceStable(reports, func(i, j int) bool {
+		if reports[i].InspectionDate != reports[j].InspectionDate {
+			return reports[i].InspectionDate > reports[j].InspectionDate
+		}
+		return reports[i].UploadedAt.After(reports[j].UploadedAt)
+	})
+}
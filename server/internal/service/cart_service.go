@@ -0,0 +1,349 @@
+// server/internal/service/cart_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// ICartService 定义买家（食堂、商户）购物车服务接口
+type ICartService interface {
+	// GetCart 获取当前买家的购物车，按供应商分组并附带实时报价及各条目的问题
+	GetCart(claims *jwt.CustomClaims) (*model.CartView, error)
+	// AddItem 将报价加入购物车，已有该报价时累加数量并以当前价格作为加入价格
+	AddItem(req *model.AddCartItemRequest, claims *jwt.CustomClaims) (*model.OrdCart, error)
+	UpdateItem(id uint, req *model.UpdateCartItemRequest, claims *jwt.CustomClaims) (*model.OrdCart, error)
+	RemoveItem(id uint, claims *jwt.CustomClaims) error
+	ClearCart(claims *jwt.CustomClaims) error
+	// ConfirmPrices 买家确认价格变化，将所有条目的加入价格更新为当前报价
+	ConfirmPrices(claims *jwt.CustomClaims) (*model.CartView, error)
+}
+
+// cartService 实现了 ICartService 接口
+type cartService struct {
+	cartRepo        repository.ICartRepository
+	quoteRepo       repository.IQuoteRepository
+	productRepo     repository.IProductRepository
+	orgRepo         repository.IOrganizationRepository
+	categoryService ICategoryService
+	fileService     IFileService
+}
+
+// NewCartService 创建一个新的 cartService 实例
+func NewCartService(cartRepo repository.ICartRepository, quoteRepo repository.IQuoteRepository, productRepo repository.IProductRepository, orgRepo repository.IOrganizationRepository, categoryService ICategoryService, fileService IFileService) ICartService {
+	return &cartService{
+		cartRepo:        cartRepo,
+		quoteRepo:       quoteRepo,
+		productRepo:     productRepo,
+		orgRepo:         orgRepo,
+		categoryService: categoryService,
+		fileService:     fileService,
+	}
+}
+
+// GetCart 获取当前买家的购物车
+func (s *cartService) GetCart(claims *jwt.CustomClaims) (*model.CartView, error) {
+	schoolID, err := s.buyerSchoolID(claims)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.cartRepo.ListLinesByMerchant(claims.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[uint]*model.ReportPolicy)
+	keys := make([]string, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.Image)
+	}
+	images, err := s.fileService.ImageURLs(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &model.CartView{Groups: []model.CartSupplierGroup{}, Invalid: []model.CartLine{}}
+	groupIndex := make(map[uint]int)
+	now := time.Now()
+	for _, row := range rows {
+		policy, err := lineReportPolicy(s.categoryService, policies, row)
+		if err != nil {
+			return nil, err
+		}
+		line := buildCartLine(row, schoolID, policy, now)
+		line.Image = images[row.Image]
+
+		view.ItemCount++
+		if !line.Orderable {
+			view.InvalidCount++
+		}
+		if line.PriceChanged {
+			view.PriceChangedCount++
+		}
+		if row.SupplierID == 0 {
+			view.Invalid = append(view.Invalid, line)
+			continue
+		}
+
+		idx, ok := groupIndex[row.SupplierID]
+		if !ok {
+			idx = len(view.Groups)
+			groupIndex[row.SupplierID] = idx
+			view.Groups = append(view.Groups, model.CartSupplierGroup{
+				SupplierID:   row.SupplierID,
+				SupplierName: row.SupplierName,
+				Lines:        []model.CartLine{},
+			})
+		}
+		group := &view.Groups[idx]
+		group.Lines = append(group.Lines, line)
+		if line.Orderable {
+			group.Subtotal = roundMoney(group.Subtotal + line.Amount)
+			view.Total = roundMoney(view.Total + line.Amount)
+		}
+	}
+	return view, nil
+}
+
+// AddItem 将报价加入购物车
+func (s *cartService) AddItem(req *model.AddCartItemRequest, claims *jwt.CustomClaims) (*model.OrdCart, error) {
+	schoolID, err := s.buyerSchoolID(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 只允许加入当前可下单的报价
+	row, err := s.quoteLine(req.QuoteID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := lineReportPolicy(s.categoryService, map[uint]*model.ReportPolicy{}, *row)
+	if err != nil {
+		return nil, err
+	}
+	if line := buildCartLine(*row, schoolID, policy, time.Now()); !line.Orderable {
+		return nil, errors.New(line.Reason)
+	}
+
+	// 2. 已有该报价时累加数量并保留加入时的价格，以便继续提示价格变动；否则新增条目
+	cart, err := s.cartRepo.GetByMerchantAndQuote(claims.OrgID, req.QuoteID)
+	if err == nil {
+		if cart.Quantity+req.Quantity > model.MaxCartQuantity {
+			return nil, fmt.Errorf("单个商品数量不能超过 %d", model.MaxCartQuantity)
+		}
+		cart.Quantity += req.Quantity
+		if err := s.cartRepo.Update(cart); err != nil {
+			return nil, fmt.Errorf("加入购物车失败: %w", err)
+		}
+		return cart, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	count, err := s.cartRepo.CountByMerchant(claims.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= model.MaxCartItems {
+		return nil, fmt.Errorf("购物车最多只能容纳 %d 个商品", model.MaxCartItems)
+	}
+	cart = &model.OrdCart{
+		MerchantID: claims.OrgID,
+		QuoteID:    req.QuoteID,
+		Quantity:   req.Quantity,
+		AddedPrice: row.Price,
+	}
+	if err := s.cartRepo.Create(cart); err != nil {
+		return nil, fmt.Errorf("加入购物车失败: %w", err)
+	}
+	return cart, nil
+}
+
+// UpdateItem 修改购物车条目的数量
+func (s *cartService) UpdateItem(id uint, req *model.UpdateCartItemRequest, claims *jwt.CustomClaims) (*model.OrdCart, error) {
+	cart, err := s.getOwnItem(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	cart.Quantity = req.Quantity
+	if err := s.cartRepo.Update(cart); err != nil {
+		return nil, fmt.Errorf("更新购物车失败: %w", err)
+	}
+	return cart, nil
+}
+
+// RemoveItem 从购物车中移除条目
+func (s *cartService) RemoveItem(id uint, claims *jwt.CustomClaims) error {
+	if _, err := s.getOwnItem(id, claims); err != nil {
+		return err
+	}
+	return s.cartRepo.Delete(id)
+}
+
+// ClearCart 清空当前买家的购物车
+func (s *cartService) ClearCart(claims *jwt.CustomClaims) error {
+	if !isBuyerRole(claims.Role) {
+		return errors.New("仅食堂和商户可以使用购物车")
+	}
+	return s.cartRepo.DeleteByMerchant(claims.OrgID)
+}
+
+// ConfirmPrices 将所有价格发生变化的条目的加入价格更新为当前报价
+func (s *cartService) ConfirmPrices(claims *jwt.CustomClaims) (*model.CartView, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, errors.New("仅食堂和商户可以使用购物车")
+	}
+	rows, err := s.cartRepo.ListLinesByMerchant(claims.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if row.ProductID == 0 || roundMoney(row.Price) == roundMoney(row.AddedPrice) {
+			continue
+		}
+		if err := s.cartRepo.UpdateAddedPrice(row.ID, row.Price); err != nil {
+			return nil, fmt.Errorf("确认价格失败: %w", err)
+		}
+	}
+	return s.GetCart(claims)
+}
+
+// buyerSchoolID 校验当前用户是买家，并返回其所属学校的ID
+func (s *cartService) buyerSchoolID(claims *jwt.CustomClaims) (uint, error) {
+	if !isBuyerRole(claims.Role) {
+		return 0, errors.New("仅食堂和商户可以使用购物车")
+	}
+	return schoolIDOf(s.orgRepo, claims.OrgID)
+}
+
+// getOwnItem 获取当前买家自己的购物车条目
+func (s *cartService) getOwnItem(id uint, claims *jwt.CustomClaims) (*model.OrdCart, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, errors.New("仅食堂和商户可以使用购物车")
+	}
+	cart, err := s.cartRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("购物车条目不存在")
+		}
+		return nil, err
+	}
+	if cart.MerchantID != claims.OrgID {
+		return nil, errors.New("无权操作其他买家的购物车")
+	}
+	return cart, nil
+}
+
+// quoteLine 按报价组装一条尚未加入购物车的条目，用于加入前的校验
+func (s *cartService) quoteLine(quoteID uint) (*model.CartLineRow, error) {
+	quote, err := s.quoteRepo.GetByID(quoteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("报价不存在")
+		}
+		return nil, err
+	}
+	product, err := s.productRepo.GetByID(quote.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	supplier, err := s.orgRepo.GetByID(quote.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CartLineRow{
+		QuoteID:         quote.ID,
+		AddedPrice:      quote.Price,
+		ProductID:       product.ID,
+		SupplierID:      supplier.ID,
+		SupplierName:    supplier.Name,
+		SupplierEnabled: supplier.IsEnabled,
+		Price:           quote.Price,
+		QuoteEnabled:    quote.IsEnabled,
+		ReviewStatus:    quote.ReviewStatus,
+		BatchReports:    quote.BatchReports,
+		ProductName:     product.Name,
+		Specs:           product.Specs,
+		Unit:            product.Unit,
+		Image:           product.Image,
+		CategoryID:      product.CategoryID,
+		SchoolID:        product.SchoolID,
+		AuditStatus:     product.AuditStatus,
+		IsListed:        product.IsListed,
+		MergedInto:      product.MergedInto,
+	}, nil
+}
+
+// lineReportPolicy 获取条目所属分类的检测报告要求，报价已被删除的条目返回 nil
+func lineReportPolicy(categoryService ICategoryService, cache map[uint]*model.ReportPolicy, row model.CartLineRow) (*model.ReportPolicy, error) {
+	if row.ProductID == 0 {
+		return nil, nil
+	}
+	if policy, ok := cache[row.CategoryID]; ok {
+		return policy, nil
+	}
+	policy, err := categoryService.ReportPolicy(row.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	cache[row.CategoryID] = policy
+	return policy, nil
+}
+
+// buildCartLine 组装购物车条目并校验其能否下单。
+// 报价被删除或停用、价格待复核、商品下架或检测报告失效都会使条目不可下单；价格变化只做提示，由买家确认。
+func buildCartLine(row model.CartLineRow, schoolID uint, policy *model.ReportPolicy, now time.Time) model.CartLine {
+	line := model.CartLine{
+		ID:          row.ID,
+		QuoteID:     row.QuoteID,
+		ProductID:   row.ProductID,
+		ProductName: row.ProductName,
+		Specs:       row.Specs,
+		Unit:        row.Unit,
+		Quantity:    row.Quantity,
+		AddedPrice:  row.AddedPrice,
+		Price:       row.Price,
+		Amount:      roundMoney(row.Price * float64(row.Quantity)),
+		Issues:      []string{},
+	}
+	block := func(issue, reason string) {
+		line.Issues = append(line.Issues, issue)
+		if line.Reason == "" {
+			line.Reason = reason
+		}
+	}
+
+	if row.ProductID == 0 {
+		block(model.CartIssueQuoteRemoved, "报价已被删除")
+		return line
+	}
+	if !row.QuoteEnabled || !row.SupplierEnabled {
+		block(model.CartIssueQuoteDisabled, "供应商已停止供货")
+	}
+	if row.ReviewStatus != model.QuoteReviewNone && row.ReviewStatus != model.QuoteReviewAccepted {
+		block(model.CartIssueQuoteReviewing, "报价价格待学校复核")
+	}
+	if row.SchoolID != schoolID || row.AuditStatus != model.ProductAuditApproved || !row.IsListed || row.MergedInto != 0 {
+		block(model.CartIssueProductUnlisted, "商品已下架")
+	}
+	if policy != nil {
+		if err := checkBatchReports(policy, row.BatchReports, now); err != nil {
+			block(model.CartIssueReportInvalid, err.Error())
+		}
+	}
+	line.Orderable = line.Reason == ""
+
+	if roundMoney(row.Price) != roundMoney(row.AddedPrice) {
+		line.PriceChanged = true
+		line.Issues = append(line.Issues, model.CartIssuePriceChanged)
+	}
+	return line
+}
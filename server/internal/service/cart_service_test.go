@@ -0,0 +1,80 @@
+// server/internal/service/cart_service_test.go
+package service
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"server/internal/model"
+)
+
+func TestBuildCartLine(t *testing.T) {
+	now := time.Date(2026, 5, 10, 9, 0, 0, 0, time.Local)
+	const schoolID = 7
+
+	base := model.CartLineRow{
+		ID: 1, QuoteID: 2, ProductID: 3, Quantity: 4,
+		AddedPrice: 2.5, Price: 2.5,
+		QuoteEnabled: true, SupplierEnabled: true,
+		ReviewStatus: model.QuoteReviewNone,
+		SchoolID:     schoolID, AuditStatus: model.ProductAuditApproved, IsListed: true,
+		BatchReports: "[]",
+	}
+	reports := func(rs ...model.BatchReport) string {
+		raw, _ := json.Marshal(rs)
+		return string(raw)
+	}
+	required := &model.ReportPolicy{Required: true, ValidDays: 7}
+
+	tests := []struct {
+		name      string
+		mutate    func(row *model.CartLineRow)
+		policy    *model.ReportPolicy
+		orderable bool
+		issues    []string
+	}{
+		{"可下单", nil, nil, true, []string{}},
+		{"报价已删除", func(r *model.CartLineRow) { r.ProductID = 0 }, nil, false, []string{model.CartIssueQuoteRemoved}},
+		{"报价停用", func(r *model.CartLineRow) { r.QuoteEnabled = false }, nil, false, []string{model.CartIssueQuoteDisabled}},
+		{"供应商停用", func(r *model.CartLineRow) { r.SupplierEnabled = false }, nil, false, []string{model.CartIssueQuoteDisabled}},
+		{"价格待复核", func(r *model.CartLineRow) { r.ReviewStatus = model.QuoteReviewPending }, nil, false, []string{model.CartIssueQuoteReviewing}},
+		{"复核驳回", func(r *model.CartLineRow) { r.ReviewStatus = model.QuoteReviewRejected }, nil, false, []string{model.CartIssueQuoteReviewing}},
+		{"复核通过", func(r *model.CartLineRow) { r.ReviewStatus = model.QuoteReviewAccepted }, nil, true, []string{}},
+		{"商品下架", func(r *model.CartLineRow) { r.IsListed = false }, nil, false, []string{model.CartIssueProductUnlisted}},
+		{"商品被合并", func(r *model.CartLineRow) { r.MergedInto = 9 }, nil, false, []string{model.CartIssueProductUnlisted}},
+		{"其他学校的商品", func(r *model.CartLineRow) { r.SchoolID = schoolID + 1 }, nil, false, []string{model.CartIssueProductUnlisted}},
+		{"缺少检测报告", nil, required, false, []string{model.CartIssueReportInvalid}},
+		{"检测报告有效", func(r *model.CartLineRow) {
+			r.BatchReports = reports(model.BatchReport{BatchNo: "B1", InspectionDate: "2026-05-09", Result: model.BatchReportPass, ExpiresOn: "2026-05-10"})
+		}, required, true, []string{}},
+		{"检测报告过期", func(r *model.CartLineRow) {
+			r.BatchReports = reports(model.BatchReport{BatchNo: "B1", InspectionDate: "2026-05-01", Result: model.BatchReportPass, ExpiresOn: "2026-05-09"})
+		}, required, false, []string{model.CartIssueReportInvalid}},
+		{"价格变化只提示", func(r *model.CartLineRow) { r.Price = 3 }, nil, true, []string{model.CartIssuePriceChanged}},
+		{"多个问题同时记录", func(r *model.CartLineRow) {
+			r.QuoteEnabled = false
+			r.IsListed = false
+			r.Price = 3
+		}, nil, false, []string{model.CartIssueQuoteDisabled, model.CartIssueProductUnlisted, model.CartIssuePriceChanged}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := base
+			if tt.mutate != nil {
+				tt.mutate(&row)
+			}
+			line := buildCartLine(row, schoolID, tt.policy, now)
+			if line.Orderable != tt.orderable {
+				t.Errorf("Orderable = %v, want %v (reason %q)", line.Orderable, tt.orderable, line.Reason)
+			}
+			if !reflect.DeepEqual(line.Issues, tt.issues) {
+				t.Errorf("Issues = %v, want %v", line.Issues, tt.issues)
+			}
+			if line.Orderable != (line.Reason == "") {
+				t.Errorf("Reason = %q inconsistent with Orderable = %v", line.Reason, line.Orderable)
+			}
+		})
+	}
+}
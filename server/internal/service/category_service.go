@@ -0,0 +1,350 @@
+// server/internal/service/category_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"server/internal/model"
+	"server/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ICategoryService 定义商品分类服务接口
+type ICategoryService interface {
+	CreateCategory(req *model.CreateCategoryRequest) (*model.ScmCategory, error)
+	UpdateCategory(id uint, req *model.UpdateCategoryRequest) error
+	MoveCategory(id uint, req *model.MoveCategoryRequest) error
+	SortCategories(req *model.SortCategoriesRequest) error
+	DeleteCategory(id uint) error
+	// GetTree 返回完整的分类树，结果会被缓存直到分类发生变更
+	GetTree() ([]*model.CategoryNode, error)
+	// EnsureLeafCategory 校验分类存在且为叶子分类，商品只能挂在叶子分类上
+	EnsureLeafCategory(id uint) error
+	// SetReportPolicy 设置分类的批次检测报告要求
+	SetReportPolicy(id uint, req *model.UpdateReportPolicyRequest) error
+	// ReportPolicy 返回分类生效的检测报告要求，分类自身或任一上级要求检测报告时即需要
+	ReportPolicy(id uint) (*model.ReportPolicy, error)
+}
+
+// categoryService 实现了 ICategoryService 接口
+type categoryService struct {
+	categoryRepo repository.ICategoryRepository
+
+	mu   sync.RWMutex
+	tree []*model.CategoryNode // 分类树缓存，为 nil 表示需要重新构建
+}
+
+// NewCategoryService 创建一个新的 categoryService 实例
+func NewCategoryService(categoryRepo repository.ICategoryRepository) ICategoryService {
+	return &categoryService{categoryRepo: categoryRepo}
+}
+
+// CreateCategory 创建一个新分类
+func (s *categoryService) CreateCategory(req *model.CreateCategoryRequest) (*model.ScmCategory, error) {
+	// 1. 校验父级分类：父级必须存在，且不能已经挂有商品（否则这些商品将不再位于叶子分类）
+	if req.ParentID != 0 {
+		if err := s.checkParentAcceptsChildren(req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. 同一父级下不允许重名
+	if err := s.checkSiblingName(req.ParentID, req.Name, 0); err != nil {
+		return nil, err
+	}
+
+	category := &model.ScmCategory{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+		Icon:     req.Icon,
+		Sort:     req.Sort,
+	}
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, fmt.Errorf("创建分类失败: %w", err)
+	}
+
+	s.invalidate()
+	return category, nil
+}
+
+// UpdateCategory 修改分类名称和图标
+func (s *categoryService) UpdateCategory(id uint, req *model.UpdateCategoryRequest) error {
+	category, err := s.getCategory(id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkSiblingName(category.ParentID, req.Name, category.ID); err != nil {
+		return err
+	}
+
+	category.Name = req.Name
+	category.Icon = req.Icon
+	if err := s.categoryRepo.Update(category); err != nil {
+		return fmt.Errorf("更新分类失败: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// MoveCategory 将分类移动到新的父级下
+func (s *categoryService) MoveCategory(id uint, req *model.MoveCategoryRequest) error {
+	category, err := s.getCategory(id)
+	if err != nil {
+		return err
+	}
+
+	if req.ParentID != 0 {
+		// 1. 不能移动到自身或自身的子孙分类下，否则会形成环
+		all, err := s.categoryRepo.ListAll()
+		if err != nil {
+			return err
+		}
+		parentOf := make(map[uint]uint, len(all))
+		for _, c := range all {
+			parentOf[c.ID] = c.ParentID
+		}
+		for cur := req.ParentID; cur != 0; cur = parentOf[cur] {
+			if cur == id {
+				return errors.New("不能将分类移动到自身或其子分类下")
+			}
+		}
+
+		// 2. 新父级必须存在且未挂商品
+		if err := s.checkParentAcceptsChildren(req.ParentID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.checkSiblingName(req.ParentID, category.Name, category.ID); err != nil {
+		return err
+	}
+
+	category.ParentID = req.ParentID
+	if req.Sort != nil {
+		category.Sort = *req.Sort
+	}
+	if err := s.categoryRepo.Update(category); err != nil {
+		return fmt.Errorf("移动分类失败: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// SortCategories 批量调整分类排序（事务性）
+func (s *categoryService) SortCategories(req *model.SortCategoriesRequest) error {
+	err := s.categoryRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		for _, item := range req.Items {
+			result := tx.Model(&model.ScmCategory{}).Where("id = ?", item.ID).Update("sort", item.Sort)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				// 排序值未变化时 RowsAffected 也为 0，需要确认分类确实存在
+				if _, err := repository.NewCategoryRepository(tx).GetByID(item.ID); err != nil {
+					return fmt.Errorf("分类 [%d] 不存在", item.ID)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// DeleteCategory 删除分类，只有没有子分类且未被商品使用的分类才能删除
+func (s *categoryService) DeleteCategory(id uint) error {
+	if _, err := s.getCategory(id); err != nil {
+		return err
+	}
+
+	children, err := s.categoryRepo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("该分类下还有子分类，无法删除")
+	}
+
+	products, err := s.categoryRepo.CountProducts(id)
+	if err != nil {
+		return err
+	}
+	if products > 0 {
+		return fmt.Errorf("该分类下还有 %d 个商品，无法删除", products)
+	}
+
+	if err := s.categoryRepo.Delete(id); err != nil {
+		return fmt.Errorf("删除分类失败: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// GetTree 返回完整的分类树
+func (s *categoryService) GetTree() ([]*model.CategoryNode, error) {
+	s.mu.RLock()
+	tree := s.tree
+	s.mu.RUnlock()
+	if tree != nil {
+		return tree, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tree != nil {
+		return s.tree, nil
+	}
+
+	categories, err := s.categoryRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	s.tree = buildCategoryTree(categories)
+	return s.tree, nil
+}
+
+// EnsureLeafCategory 校验分类存在且为叶子分类
+func (s *categoryService) EnsureLeafCategory(id uint) error {
+	if _, err := s.getCategory(id); err != nil {
+		return err
+	}
+	children, err := s.categoryRepo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("商品只能归属于最末级分类")
+	}
+	return nil
+}
+
+// SetReportPolicy 设置分类的批次检测报告要求
+func (s *categoryService) SetReportPolicy(id uint, req *model.UpdateReportPolicyRequest) error {
+	category, err := s.getCategory(id)
+	if err != nil {
+		return err
+	}
+	category.RequireReport = *req.RequireReport
+	category.ReportValidDays = req.ValidDays
+	if err := s.categoryRepo.Update(category); err != nil {
+		return fmt.Errorf("更新检测报告要求失败: %w", err)
+	}
+
+	s.invalidate()
+	return nil
+}
+
+// ReportPolicy 返回分类生效的检测报告要求。
+// 从分类自身向上查找，最近一个要求检测报告的分类决定有效天数；都不要求时使用默认有效天数。
+func (s *categoryService) ReportPolicy(id uint) (*model.ReportPolicy, error) {
+	policy := &model.ReportPolicy{ValidDays: model.DefaultReportValidDays}
+	seen := make(map[uint]bool)
+	for cur := id; cur != 0 && !seen[cur]; {
+		seen[cur] = true
+		category, err := s.getCategory(cur)
+		if err != nil {
+			return nil, err
+		}
+		if category.RequireReport {
+			policy.Required = true
+			if category.ReportValidDays > 0 {
+				policy.ValidDays = category.ReportValidDays
+			}
+			break
+		}
+		cur = category.ParentID
+	}
+	return policy, nil
+}
+
+// invalidate 使分类树缓存失效
+func (s *categoryService) invalidate() {
+	s.mu.Lock()
+	s.tree = nil
+	s.mu.Unlock()
+}
+
+// getCategory 获取分类，不存在时返回友好的错误信息
+func (s *categoryService) getCategory(id uint) (*model.ScmCategory, error) {
+	category, err := s.categoryRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("分类不存在")
+		}
+		return nil, err
+	}
+	return category, nil
+}
+
+// checkParentAcceptsChildren 校验父级分类存在且没有直接挂载商品
+func (s *categoryService) checkParentAcceptsChildren(parentID uint) error {
+	if _, err := s.categoryRepo.GetByID(parentID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("父级分类不存在")
+		}
+		return err
+	}
+	products, err := s.categoryRepo.CountProducts(parentID)
+	if err != nil {
+		return err
+	}
+	if products > 0 {
+		return errors.New("父级分类下已有商品，不能再添加子分类")
+	}
+	return nil
+}
+
+// checkSiblingName 校验同一父级下分类名称唯一
+func (s *categoryService) checkSiblingName(parentID uint, name string, excludeID uint) error {
+	exists, err := s.categoryRepo.ExistsSiblingName(parentID, name, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("同级下已存在名为 [%s] 的分类", name)
+	}
+	return nil
+}
+
+// buildCategoryTree 将扁平的分类列表组装成树，输入已按排序值排好序
+func buildCategoryTree(categories []model.ScmCategory) []*model.CategoryNode {
+	nodes := make(map[uint]*model.CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &model.CategoryNode{
+			ID:       c.ID,
+			Name:     c.Name,
+			ParentID: c.ParentID,
+			Icon:     c.Icon,
+			Sort:     c.Sort,
+			Children: []*model.CategoryNode{},
+
+			RequireReport:   c.RequireReport,
+			ReportValidDays: c.ReportValidDays,
+		}
+	}
+
+	roots := []*model.CategoryNode{}
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if parent, ok := nodes[c.ParentID]; ok && c.ParentID != 0 {
+			parent.Children = append(parent.Children, node)
+		} else {
+			// 父级不存在的分类按顶级分类处理，避免数据异常时整棵子树丢失
+			roots = append(roots, node)
+		}
+	}
+	for _, node := range nodes {
+		node.IsLeaf = len(node.Children) == 0
+	}
+	return roots
+}
@@ -0,0 +1,353 @@
+// server/internal/service/comparison_service.go
+package service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// comparisonWindow 供应商评分和价格走势的统计周期
+const comparisonWindow = 90 * 24 * time.Hour
+
+// IComparisonService 定义多供应商比价服务接口
+type IComparisonService interface {
+	// CompareProduct 返回单个商品所有启用报价的横向对比
+	CompareProduct(productID uint, claims *jwt.CustomClaims) (*model.ProductComparison, error)
+	// CompareCategory 分页返回某分类下商品的比价结果
+	CompareCategory(categoryID uint, claims *jwt.CustomClaims, page, pageSize int) ([]model.ProductComparison, int64, error)
+	// PickBasket 为采购清单中的每个商品挑选价格最低的可用报价
+	PickBasket(req *model.BasketRequest, claims *jwt.CustomClaims) (*model.BasketResult, error)
+}
+
+// comparisonService 实现了 IComparisonService 接口
+type comparisonService struct {
+	productRepo repository.IProductRepository
+	quoteRepo   repository.IQuoteRepository
+	orderRepo   repository.IOrderRepository
+	orgRepo     repository.IOrganizationRepository
+
+	categoryService ICategoryService
+}
+
+// NewComparisonService 创建一个新的 comparisonService 实例
+func NewComparisonService(productRepo repository.IProductRepository, quoteRepo repository.IQuoteRepository, orderRepo repository.IOrderRepository, orgRepo repository.IOrganizationRepository, categoryService ICategoryService) IComparisonService {
+	return &comparisonService{
+		productRepo:     productRepo,
+		quoteRepo:       quoteRepo,
+		orderRepo:       orderRepo,
+		orgRepo:         orgRepo,
+		categoryService: categoryService,
+	}
+}
+
+// CompareProduct 返回单个商品的比价结果
+func (s *comparisonService) CompareProduct(productID uint, claims *jwt.CustomClaims) (*model.ProductComparison, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("商品不存在")
+		}
+		return nil, err
+	}
+	if err := s.checkComparable(product, claims); err != nil {
+		return nil, err
+	}
+
+	comparisons, err := s.compare([]model.ScmProduct{*product})
+	if err != nil {
+		return nil, err
+	}
+	return &comparisons[0], nil
+}
+
+// CompareCategory 分页返回某分类下商品的比价结果，食堂和商户只能看到已上架的商品
+func (s *comparisonService) CompareCategory(categoryID uint, claims *jwt.CustomClaims, page, pageSize int) ([]model.ProductComparison, int64, error) {
+	filter, err := s.scopeFilter(claims)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter.CategoryID = categoryID
+
+	products, total, err := s.productRepo.List(filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	comparisons, err := s.compare(products)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comparisons, total, nil
+}
+
+// PickBasket 为清单中的每个商品挑选价格最低的可下单报价，同一商品出现多次时数量合并
+func (s *comparisonService) PickBasket(req *model.BasketRequest, claims *jwt.CustomClaims) (*model.BasketResult, error) {
+	// 1. 合并重复商品，保持清单原有顺序
+	quantities := make(map[uint]int, len(req.Items))
+	var productIDs []uint
+	for _, item := range req.Items {
+		if _, ok := quantities[item.ProductID]; !ok {
+			productIDs = append(productIDs, item.ProductID)
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	// 2. 查询所有商品的启用报价，结果已按价格升序排列
+	rows, err := s.quoteRepo.ListEnabledByProducts(productIDs)
+	if err != nil {
+		return nil, err
+	}
+	rowsByProduct := make(map[uint][]model.QuoteCompareRow, len(productIDs))
+	for _, row := range rows {
+		rowsByProduct[row.ProductID] = append(rowsByProduct[row.ProductID], row)
+	}
+	policies := make(map[uint]*model.ReportPolicy)
+	now := time.Now()
+
+	result := &model.BasketResult{
+		Lines:       []model.BasketLine{},
+		Unavailable: []model.BasketUnavailable{},
+	}
+	suppliers := make(map[uint]struct{})
+	for _, productID := range productIDs {
+		product, err := s.productRepo.GetByID(productID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				result.Unavailable = append(result.Unavailable, model.BasketUnavailable{ProductID: productID, Reason: "商品不存在"})
+				continue
+			}
+			return nil, err
+		}
+		if err := s.checkComparable(product, claims); err != nil {
+			result.Unavailable = append(result.Unavailable, model.BasketUnavailable{ProductID: productID, Reason: err.Error()})
+			continue
+		}
+		candidates := rowsByProduct[productID]
+		if len(candidates) == 0 {
+			result.Unavailable = append(result.Unavailable, model.BasketUnavailable{ProductID: productID, Reason: "暂无可用报价"})
+			continue
+		}
+
+		// 3. 跳过不满足检测报告要求的报价，第一条满足的即最低价
+		policy, err := s.reportPolicy(policies, product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		var row model.QuoteCompareRow
+		var reportErr error
+		found := false
+		for _, candidate := range candidates {
+			if err := checkBatchReports(policy, candidate.BatchReports, now); err != nil {
+				if reportErr == nil {
+					reportErr = err
+				}
+				continue
+			}
+			row, found = candidate, true
+			break
+		}
+		if !found {
+			result.Unavailable = append(result.Unavailable, model.BasketUnavailable{ProductID: productID, Reason: reportErr.Error()})
+			continue
+		}
+
+		quantity := quantities[productID]
+		amount := roundMoney(row.Price * float64(quantity))
+		result.Lines = append(result.Lines, model.BasketLine{
+			ProductID:    product.ID,
+			ProductName:  product.Name,
+			Specs:        product.Specs,
+			Unit:         product.Unit,
+			Quantity:     quantity,
+			QuoteID:      row.ID,
+			SupplierID:   row.SupplierID,
+			SupplierName: row.SupplierName,
+			Price:        row.Price,
+			Amount:       amount,
+		})
+		result.TotalAmount += amount
+		suppliers[row.SupplierID] = struct{}{}
+	}
+	result.TotalAmount = roundMoney(result.TotalAmount)
+	result.SupplierCount = len(suppliers)
+	return result, nil
+}
+
+// compare 为一批商品组装比价结果
+func (s *comparisonService) compare(products []model.ScmProduct) ([]model.ProductComparison, error) {
+	comparisons := make([]model.ProductComparison, len(products))
+	if len(products) == 0 {
+		return comparisons, nil
+	}
+
+	productIDs := make([]uint, len(products))
+	for i := range products {
+		productIDs[i] = products[i].ID
+	}
+	rows, err := s.quoteRepo.ListEnabledByProducts(productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 批量查询供应商履约情况和统计周期开始时的价格
+	since := time.Now().Add(-comparisonWindow)
+	quoteIDs := make([]uint, 0, len(rows))
+	supplierIDs := make([]uint, 0, len(rows))
+	seenSupplier := make(map[uint]bool)
+	for _, row := range rows {
+		quoteIDs = append(quoteIDs, row.ID)
+		if !seenSupplier[row.SupplierID] {
+			seenSupplier[row.SupplierID] = true
+			supplierIDs = append(supplierIDs, row.SupplierID)
+		}
+	}
+	performance := map[uint]*model.SupplierPerformance{}
+	basePrices := map[uint]float64{}
+	if len(rows) > 0 {
+		if performance, err = s.orderRepo.SupplierPerformance(supplierIDs, since); err != nil {
+			return nil, err
+		}
+		if basePrices, err = s.quoteRepo.PricesAt(quoteIDs, since); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. 按商品分组并标记是否满足检测报告要求，报价已按价格升序排列，第一条可下单的即最优价
+	categoryOf := make(map[uint]uint, len(products))
+	for i := range products {
+		categoryOf[products[i].ID] = products[i].CategoryID
+	}
+	policies := make(map[uint]*model.ReportPolicy)
+	now := time.Now()
+	quotesByProduct := make(map[uint][]model.QuoteComparison, len(products))
+	for _, row := range rows {
+		quote := buildQuoteComparison(row, performance[row.SupplierID], basePrices)
+		policy, err := s.reportPolicy(policies, categoryOf[row.ProductID])
+		if err != nil {
+			return nil, err
+		}
+		quote.Orderable = true
+		if err := checkBatchReports(policy, row.BatchReports, now); err != nil {
+			quote.Orderable = false
+			quote.ReportIssue = err.Error()
+		}
+		quotesByProduct[row.ProductID] = append(quotesByProduct[row.ProductID], quote)
+	}
+	for i := range products {
+		quotes := quotesByProduct[products[i].ID]
+		comparison := model.ProductComparison{Product: &products[i], Quotes: []model.QuoteComparison{}}
+		if len(quotes) > 0 {
+			comparison.Quotes = quotes
+		}
+		for j := range quotes {
+			if quotes[j].Orderable {
+				quotes[j].IsBest = true
+				comparison.BestQuoteID = quotes[j].QuoteID
+				comparison.BestPrice = quotes[j].Price
+				break
+			}
+		}
+		comparisons[i] = comparison
+	}
+	return comparisons, nil
+}
+
+// reportPolicy 查询分类的检测报告要求，同一次比价中按分类缓存
+func (s *comparisonService) reportPolicy(cache map[uint]*model.ReportPolicy, categoryID uint) (*model.ReportPolicy, error) {
+	if policy, ok := cache[categoryID]; ok {
+		return policy, nil
+	}
+	policy, err := s.categoryService.ReportPolicy(categoryID)
+	if err != nil {
+		return nil, err
+	}
+	cache[categoryID] = policy
+	return policy, nil
+}
+
+// checkComparable 校验当前用户能否对该商品比价。
+// 供应商不能查看竞争对手的报价；学校可以对本校已审核的商品比价；食堂和商户只能对本校已上架的商品比价。
+func (s *comparisonService) checkComparable(product *model.ScmProduct, claims *jwt.CustomClaims) error {
+	filter, err := s.scopeFilter(claims)
+	if err != nil {
+		return err
+	}
+	if filter.SchoolID != 0 && product.SchoolID != filter.SchoolID {
+		return errors.New("无权查看此商品")
+	}
+	if product.AuditStatus != model.ProductAuditApproved {
+		return errors.New("商品尚未审核通过")
+	}
+	if filter.IsListed != nil && !product.IsListed {
+		return errors.New("商品未上架")
+	}
+	return nil
+}
+
+// scopeFilter 根据当前用户的角色生成比价的商品范围
+func (s *comparisonService) scopeFilter(claims *jwt.CustomClaims) (model.ProductListFilter, error) {
+	approved := model.ProductAuditApproved
+	filter := model.ProductListFilter{AuditStatus: &approved}
+	switch {
+	case isPlatformRole(claims.Role):
+		// 平台不做额外限制
+	case isSupplierRole(claims.Role):
+		return filter, errors.New("供应商无权查看比价信息")
+	case isSchoolRole(claims.Role):
+		filter.SchoolID = claims.OrgID
+	default:
+		schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+		if err != nil {
+			return filter, err
+		}
+		listed := true
+		filter.SchoolID = schoolID
+		filter.IsListed = &listed
+	}
+	return filter, nil
+}
+
+// buildQuoteComparison 组装单条报价的比价信息
+func buildQuoteComparison(row model.QuoteCompareRow, perf *model.SupplierPerformance, basePrices map[uint]float64) model.QuoteComparison {
+	quote := model.QuoteComparison{
+		QuoteID:      row.ID,
+		SupplierID:   row.SupplierID,
+		SupplierName: row.SupplierName,
+		Price:        row.Price,
+		Trend:        model.PriceTrendFlat,
+	}
+
+	if perf != nil && perf.OrderCount > 0 {
+		quote.OrderCount = perf.OrderCount
+		quote.AfterSaleCount = perf.AfterSaleCount
+		quote.LastDeliveryAt = perf.LastDeliveryAt
+		quote.AvgDeliveryHours = perf.AvgDeliveryHours
+		quote.CompletedRate = float64(perf.CompletedCount) / float64(perf.OrderCount)
+		// 评分 = 5 × 完成率 × (1 - 售后率)，售后率超过 100% 时按 100% 计
+		afterSaleRate := math.Min(1, float64(perf.AfterSaleCount)/float64(perf.OrderCount))
+		rating := math.Round(5*quote.CompletedRate*(1-afterSaleRate)*10) / 10
+		quote.Rating = &rating
+	}
+
+	if base, ok := basePrices[row.ID]; ok {
+		quote.PriceChange = roundMoney(row.Price - base)
+		switch {
+		case quote.PriceChange > 0:
+			quote.Trend = model.PriceTrendUp
+		case quote.PriceChange < 0:
+			quote.Trend = model.PriceTrendDown
+		}
+	}
+	return quote
+}
+
+// roundMoney 将金额四舍五入到分
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,451 @@
+// server/internal/service/delivery_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// IDeliveryService 定义供应商配送调度服务接口
+type IDeliveryService interface {
+	// CreateRun 将一批分拣中的订单编为一个配送趟次，并指派司机和车辆
+	CreateRun(req *model.CreateDeliveryRunRequest, claims *jwt.CustomClaims) (*model.DeliveryRunDetail, error)
+	ListRuns(filter model.DeliveryRunFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdDeliveryRun, int64, error)
+	GetRun(id uint, claims *jwt.CustomClaims) (*model.DeliveryRunDetail, error)
+	// AssignDriver 在出发前更换趟次的司机和车辆
+	AssignDriver(id uint, req *model.AssignDriverRequest, claims *jwt.CustomClaims) (*model.DeliveryRunDetail, error)
+	// CancelRun 取消尚未出发的趟次，趟次内的订单可重新排车
+	CancelRun(id uint, claims *jwt.CustomClaims) error
+	// Depart 登记趟次出发，趟次内的订单进入配送中状态并记录配送时间
+	Depart(id uint, claims *jwt.CustomClaims) (*model.DeliveryRunDetail, error)
+	// ArriveOrder 登记趟次内某订单送达，记录送达时间；全部订单送达后趟次完成
+	ArriveOrder(id, orderID uint, claims *jwt.CustomClaims) (*model.OrdOrder, error)
+	// DriverTasks 列出司机某日的配送任务，附带各趟次的订单
+	DriverTasks(driverID uint, date time.Time, claims *jwt.CustomClaims) ([]model.DeliveryRunDetail, error)
+}
+
+// deliveryService 实现了 IDeliveryService 接口
+type deliveryService struct {
+	runRepo      repository.IDeliveryRunRepository
+	orderRepo    repository.IOrderRepository
+	orgRepo      repository.IOrganizationRepository
+	staffRepo    repository.ISupplierStaffRepository
+	staffService ISupplierStaffService
+}
+
+// NewDeliveryService 创建一个新的 deliveryService 实例
+func NewDeliveryService(runRepo repository.IDeliveryRunRepository, orderRepo repository.IOrderRepository, orgRepo repository.IOrganizationRepository, staffRepo repository.ISupplierStaffRepository, staffService ISupplierStaffService) IDeliveryService {
+	return &deliveryService{
+		runRepo:      runRepo,
+		orderRepo:    orderRepo,
+		orgRepo:      orgRepo,
+		staffRepo:    staffRepo,
+		staffService: staffService,
+	}
+}
+
+// CreateRun 排车
+func (s *deliveryService) CreateRun(req *model.CreateDeliveryRunRequest, claims *jwt.CustomClaims) (*model.DeliveryRunDetail, error) {
+	if !isSupplierRole(claims.Role) {
+		return nil, errors.New("仅供应商可以安排配送")
+	}
+	date, err := time.ParseInLocation("2006-01-02", req.DeliveryDate, time.Local)
+	if err != nil {
+		return nil, errors.New("无效的送达日期")
+	}
+	if _, err := s.staffService.EnsureAssignable(claims.OrgID, req.DriverID, model.StaffRoleDriver); err != nil {
+		return nil, err
+	}
+
+	// 1. 订单须属于本供应商、处于分拣中状态、尚未排车且送达日期一致
+	orderIDs := uniqueIDs(req.OrderIDs)
+	orders, err := s.orderRepo.ListByIDs(orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(orders) != len(orderIDs) {
+		return nil, errors.New("部分订单不存在")
+	}
+	for _, order := range orders {
+		if order.SupplierID != claims.OrgID {
+			return nil, errors.New("无权安排其他供应商的订单")
+		}
+		if order.Status != model.OrderStatusPicking {
+			return nil, fmt.Errorf("订单 [%s] 处于 [%s] 状态，只有分拣中的订单可以排车", order.OrderNo, orderStatusLabel(order.Status))
+		}
+		if order.DeliveryRunID != 0 {
+			return nil, fmt.Errorf("订单 [%s] 已安排在趟次 #%d 中", order.OrderNo, order.DeliveryRunID)
+		}
+		if order.DeliveryDate != nil && !sameDay(*order.DeliveryDate, date) {
+			return nil, fmt.Errorf("订单 [%s] 的送达日期与趟次不一致", order.OrderNo)
+		}
+	}
+
+	run := &model.OrdDeliveryRun{
+		SupplierID:   claims.OrgID,
+		DeliveryDate: date,
+		DriverID:     req.DriverID,
+		VehicleNo:    req.VehicleNo,
+		Status:       model.DeliveryRunPending,
+		OrderCount:   len(orders),
+		OperatorID:   claims.UserID,
+	}
+	err = s.runRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := checkPickingDone(repository.NewPickListRepository(tx), orders); err != nil {
+			return err
+		}
+		if err := repository.NewDeliveryRunRepository(tx).Create(run); err != nil {
+			return fmt.Errorf("创建配送趟次失败: %w", err)
+		}
+		// 以订单尚未排车为条件，防止同一订单被并发编入两个趟次
+		affected, err := repository.NewOrderRepository(tx).SetDeliveryRun(orderIDs, 0, run.ID)
+		if err != nil {
+			return err
+		}
+		if int(affected) != len(orderIDs) {
+			return errors.New("部分订单已被安排到其他趟次，请刷新后重试")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildDetail(run)
+}
+
+// ListRuns 分页列出本供应商的配送趟次
+func (s *deliveryService) ListRuns(filter model.DeliveryRunFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdDeliveryRun, int64, error) {
+	filter.SupplierID = claims.OrgID
+	return s.runRepo.List(filter, page, pageSize)
+}
+
+// GetRun 获取配送趟次详情
+func (s *deliveryService) GetRun(id uint, claims *jwt.CustomClaims) (*model.DeliveryRunDetail, error) {
+	run, err := s.getOwnRun(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildDetail(run)
+}
+
+// AssignDriver 更换司机和车辆
+func (s *deliveryService) AssignDriver(id uint, req *model.AssignDriverRequest, claims *jwt.CustomClaims) (*model.DeliveryRunDetail, error) {
+	run, err := s.getOwnRun(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.staffService.EnsureAssignable(claims.OrgID, req.DriverID, model.StaffRoleDriver); err != nil {
+		return nil, err
+	}
+	err = s.runRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		runRepo := repository.NewDeliveryRunRepository(tx)
+		locked, err := lockPendingRun(runRepo, run.ID, "趟次已出发，不能更换司机")
+		if err != nil {
+			return err
+		}
+		run = locked
+		run.DriverID = req.DriverID
+		run.VehicleNo = req.VehicleNo
+		if err := runRepo.Update(run); err != nil {
+			return fmt.Errorf("更换司机失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildDetail(run)
+}
+
+// CancelRun 取消尚未出发的趟次
+func (s *deliveryService) CancelRun(id uint, claims *jwt.CustomClaims) error {
+	run, err := s.getOwnRun(id, claims)
+	if err != nil {
+		return err
+	}
+	return s.runRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if _, err := lockPendingRun(repository.NewDeliveryRunRepository(tx), run.ID, "趟次已出发，不能取消"); err != nil {
+			return err
+		}
+		orderRepo := repository.NewOrderRepository(tx)
+		orders, err := orderRepo.ListByDeliveryRun(run.ID)
+		if err != nil {
+			return err
+		}
+		ids := make([]uint, 0, len(orders))
+		for _, order := range orders {
+			ids = append(ids, order.ID)
+		}
+		if _, err := orderRepo.SetDeliveryRun(ids, run.ID, 0); err != nil {
+			return err
+		}
+		return repository.NewDeliveryRunRepository(tx).Delete(run.ID)
+	})
+}
+
+// Depart 登记趟次出发。出发前会再次校验司机的健康证；排车后被取消的订单会移出趟次。
+func (s *deliveryService) Depart(id uint, claims *jwt.CustomClaims) (*model.DeliveryRunDetail, error) {
+	run, err := s.getOwnRun(id, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.runRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		// 锁定趟次后再校验状态和司机，避免与取消、更换司机或重复出发并发
+		locked, err := lockPendingRun(repository.NewDeliveryRunRepository(tx), run.ID, "趟次已出发")
+		if err != nil {
+			return err
+		}
+		run = locked
+		driver, err := s.staffService.EnsureAssignable(claims.OrgID, run.DriverID, model.StaffRoleDriver)
+		if err != nil {
+			return err
+		}
+
+		orderRepo := repository.NewOrderRepository(tx)
+		orders, err := orderRepo.ListByDeliveryRun(run.ID)
+		if err != nil {
+			return err
+		}
+
+		// 1. 订单进入配送中状态，记录配送时间
+		remark := fmt.Sprintf("司机 %s 出发配送，车牌 %s", driver.Name, run.VehicleNo)
+		var departed []model.OrdOrder
+		var removed []uint
+		for i := range orders {
+			if orders[i].Status == model.OrderStatusCancelled {
+				removed = append(removed, orders[i].ID)
+				continue
+			}
+			if err := transitionOrder(orderRepo, &orders[i], model.OrderStatusDelivering, model.OrderActorSupplier, claims, remark); err != nil {
+				return fmt.Errorf("订单 [%s]: %w", orders[i].OrderNo, err)
+			}
+			departed = append(departed, orders[i])
+		}
+		if len(departed) == 0 {
+			return errors.New("趟次内没有可配送的订单")
+		}
+		if err := checkPickingDone(repository.NewPickListRepository(tx), departed); err != nil {
+			return err
+		}
+		if _, err := orderRepo.SetDeliveryRun(removed, run.ID, 0); err != nil {
+			return err
+		}
+
+		// 2. 在订单明细的溯源记录上登记司机
+		if err := stampTraceDriver(orderRepo, departed, driver.ID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		run.Status = model.DeliveryRunDelivering
+		run.DepartedAt = &now
+		run.OrderCount = len(departed)
+		return repository.NewDeliveryRunRepository(tx).Update(run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildDetail(run)
+}
+
+// ArriveOrder 登记订单送达
+func (s *deliveryService) ArriveOrder(id, orderID uint, claims *jwt.CustomClaims) (*model.OrdOrder, error) {
+	run, err := s.getOwnRun(id, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	var order *model.OrdOrder
+	err = s.runRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		// 锁定趟次，同一趟次的订单依次登记送达，最后一单送达时能看到其余订单均已送达
+		locked, err := repository.NewDeliveryRunRepository(tx).Lock(run.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status != model.DeliveryRunDelivering {
+			return errors.New("趟次不在配送中")
+		}
+		run = locked
+
+		orderRepo := repository.NewOrderRepository(tx)
+		order, err = orderRepo.GetByID(orderID)
+		if err != nil || order.DeliveryRunID != run.ID {
+			return errors.New("该订单不在此趟次中")
+		}
+		if err := transitionOrder(orderRepo, order, model.OrderStatusDelivered, model.OrderActorSupplier, claims, ""); err != nil {
+			return err
+		}
+
+		// 趟次内已没有配送中的订单时，趟次完成
+		orders, err := orderRepo.ListByDeliveryRun(run.ID)
+		if err != nil {
+			return err
+		}
+		for _, o := range orders {
+			if o.Status == model.OrderStatusDelivering {
+				return nil
+			}
+		}
+		now := time.Now()
+		run.Status = model.DeliveryRunFinished
+		run.FinishedAt = &now
+		return repository.NewDeliveryRunRepository(tx).Update(run)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// DriverTasks 列出司机某日的配送任务
+func (s *deliveryService) DriverTasks(driverID uint, date time.Time, claims *jwt.CustomClaims) ([]model.DeliveryRunDetail, error) {
+	driver, err := s.staffRepo.GetByID(driverID)
+	if err != nil || driver.SupplierID != claims.OrgID {
+		return nil, errors.New("司机不存在")
+	}
+
+	// 司机一天的趟次数量有限，一次取出即可
+	filter := model.DeliveryRunFilter{SupplierID: claims.OrgID, DriverID: driverID, DeliveryDate: &date}
+	runs, _, err := s.runRepo.List(filter, 1, 100)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]model.DeliveryRunDetail, 0, len(runs))
+	for i := len(runs) - 1; i >= 0; i-- {
+		detail, err := s.buildDetail(&runs[i])
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, *detail)
+	}
+	return tasks, nil
+}
+
+// getOwnRun 获取当前供应商自己的配送趟次
+func (s *deliveryService) getOwnRun(id uint, claims *jwt.CustomClaims) (*model.OrdDeliveryRun, error) {
+	run, err := s.runRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("配送趟次不存在")
+		}
+		return nil, err
+	}
+	if run.SupplierID != claims.OrgID {
+		return nil, errors.New("无权操作其他供应商的配送趟次")
+	}
+	return run, nil
+}
+
+// lockPendingRun 以 FOR UPDATE 方式读取趟次并确认其尚未出发，调用方负责开启事务
+func lockPendingRun(runRepo repository.IDeliveryRunRepository, id uint, msg string) (*model.OrdDeliveryRun, error) {
+	run, err := runRepo.Lock(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("配送趟次不存在")
+		}
+		return nil, err
+	}
+	if run.Status != model.DeliveryRunPending {
+		return nil, errors.New(msg)
+	}
+	return run, nil
+}
+
+// buildDetail 组装配送趟次详情
+func (s *deliveryService) buildDetail(run *model.OrdDeliveryRun) (*model.DeliveryRunDetail, error) {
+	orders, err := s.orderRepo.ListByDeliveryRun(run.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillOrderItems(s.orderRepo, orders); err != nil {
+		return nil, err
+	}
+	if err := fillOrderMerchantNames(s.orgRepo, orders); err != nil {
+		return nil, err
+	}
+
+	detail := &model.DeliveryRunDetail{Run: run, Orders: orders}
+	if driver, err := s.staffRepo.GetByID(run.DriverID); err == nil {
+		detail.DriverName = driver.Name
+		detail.DriverMobile = driver.Mobile
+	}
+	return detail, nil
+}
+
+// stampTraceDriver 在订单明细的溯源记录上登记司机，尚无溯源记录的明细会新建一条
+func stampTraceDriver(orderRepo repository.IOrderRepository, orders []model.OrdOrder, driverID uint) error {
+	if err := fillOrderItems(orderRepo, orders); err != nil {
+		return err
+	}
+	var itemIDs []uint
+	for _, order := range orders {
+		for _, item := range order.Items {
+			itemIDs = append(itemIDs, item.ID)
+		}
+	}
+	traces, err := orderRepo.ListTracesByItems(itemIDs)
+	if err != nil {
+		return err
+	}
+	traced := make(map[uint]bool, len(traces))
+	for _, trace := range traces {
+		traced[trace.OrderItemID] = true
+	}
+
+	for _, order := range orders {
+		for _, item := range order.Items {
+			if traced[item.ID] {
+				continue
+			}
+			trace := &model.OrdItemTrace{
+				OrderItemID:  item.ID,
+				TraceCode:    fmt.Sprintf("%s-%d", order.OrderNo, item.ID),
+				MerchantInfo: "{}",
+				SupplierInfo: "{}",
+				CertSnapshot: "[]",
+				TimeLine:     "[]",
+				DriverID:     driverID,
+			}
+			if err := orderRepo.CreateTrace(trace); err != nil {
+				return fmt.Errorf("创建溯源记录失败: %w", err)
+			}
+		}
+	}
+	return orderRepo.UpdateTraceDriver(itemIDs, driverID)
+}
+
+// checkPickingDone 校验订单的明细所在的分拣单均已完成，分拣未完成时实拣数量尚未回写到订单
+func checkPickingDone(pickRepo repository.IPickListRepository, orders []model.OrdOrder) error {
+	ids := make([]uint, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.ID)
+	}
+	unfinished, err := pickRepo.ListUnfinishedOrders(ids)
+	if err != nil {
+		return err
+	}
+	if len(unfinished) == 0 {
+		return nil
+	}
+	for _, order := range orders {
+		if order.ID == unfinished[0] {
+			return fmt.Errorf("订单 [%s] 的分拣单尚未完成", order.OrderNo)
+		}
+	}
+	return errors.New("部分订单的分拣单尚未完成")
+}
+
+// sameDay 判断两个时间是否在同一天
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.In(time.Local).Date()
+	by, bm, bd := b.In(time.Local).Date()
+	return ay == by && am == bm && ad == bd
+}
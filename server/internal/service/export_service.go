@@ -0,0 +1,613 @@
+// server/internal/service/export_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"server/internal/config"
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+	"server/pkg/sheet"
+)
+
+// exportPageSize 导出时每次从数据库读取的行数
+const exportPageSize = 500
+
+// 导出数据源名称
+const (
+	ExportSourceSchools   = "schools"
+	ExportSourceSuppliers = "suppliers"
+	ExportSourceAccounts  = "accounts"
+	ExportSourceOrders    = "orders"
+	ExportSourceLogs      = "logs"
+)
+
+// exportColumn 描述一个可导出的列
+type exportColumn struct {
+	Key      string
+	HeaderZh string
+	HeaderEn string
+	DictCode string // 非空时将存储值翻译为数据字典中的展示名
+}
+
+// header 返回指定语言的表头
+func (col exportColumn) header(lang string) string {
+	if lang == "en" {
+		return col.HeaderEn
+	}
+	return col.HeaderZh
+}
+
+// exportFetchFunc 按与列表接口一致的筛选条件分页取数，每行以列 Key 为键
+type exportFetchFunc func(claims *jwt.CustomClaims, filters url.Values, page, pageSize int) ([]map[string]string, int64, error)
+
+// exportSource 描述一个可导出的列表
+type exportSource struct {
+	SheetName string
+	Columns   []exportColumn
+	Allowed   func(claims *jwt.CustomClaims) bool
+	Fetch     exportFetchFunc
+}
+
+// exportPlan 是校验后的一次导出请求
+type exportPlan struct {
+	source  *exportSource
+	format  sheet.Format
+	lang    string
+	columns []exportColumn
+	filters url.Values
+}
+
+// IExportService 定义导出服务接口
+type IExportService interface {
+	// Columns 列出某个数据源的全部可导出列
+	Columns(source, lang string, claims *jwt.CustomClaims) ([]model.ExportColumnInfo, error)
+	// ShouldRunAsync 校验导出参数，并判断数据量是否需要转为后台任务
+	ShouldRunAsync(source string, query url.Values, claims *jwt.CustomClaims) (bool, error)
+	// Export 将导出结果直接写入 w，返回导出的行数
+	Export(source string, query url.Values, claims *jwt.CustomClaims, w io.Writer) (int, error)
+	// CreateJob 创建后台导出任务并异步执行
+	CreateJob(source string, query url.Values, claims *jwt.CustomClaims) (*model.SysExportJob, error)
+	// GetJob 获取当前用户的导出任务
+	GetJob(id uint, claims *jwt.CustomClaims) (*model.SysExportJob, error)
+	// ListJobs 分页列出当前用户的导出任务
+	ListJobs(claims *jwt.CustomClaims, page, pageSize int) ([]model.SysExportJob, int64, error)
+}
+
+// exportService 实现了 IExportService 接口
+type exportService struct {
+	sources  map[string]*exportSource
+	dictRepo repository.IDictionaryRepository
+	jobRepo  repository.IExportJobRepository
+}
+
+// NewExportService 创建一个新的 exportService 实例，并注册所有可导出的列表
+func NewExportService(
+	schoolService ISchoolService,
+	supplierService ISupplierService,
+	accountService IAccountService,
+	logService ILogService,
+	orgRepo repository.IOrganizationRepository,
+	orderRepo repository.IOrderRepository,
+	roleRepo repository.IRoleRepository,
+	dictRepo repository.IDictionaryRepository,
+	jobRepo repository.IExportJobRepository,
+) IExportService {
+	s := &exportService{
+		sources:  make(map[string]*exportSource),
+		dictRepo: dictRepo,
+		jobRepo:  jobRepo,
+	}
+
+	s.sources[ExportSourceSchools] = &exportSource{
+		SheetName: "学校列表",
+		Columns: []exportColumn{
+			{Key: "id", HeaderZh: "ID", HeaderEn: "ID"},
+			{Key: "name", HeaderZh: "学校名称", HeaderEn: "School Name"},
+			{Key: "contactName", HeaderZh: "联系人", HeaderEn: "Contact"},
+			{Key: "contactPhone", HeaderZh: "联系电话", HeaderEn: "Phone"},
+			{Key: "address", HeaderZh: "地址", HeaderEn: "Address"},
+			{Key: "adminUsername", HeaderZh: "管理员账号", HeaderEn: "Admin Username"},
+			{Key: "isEnabled", HeaderZh: "状态", HeaderEn: "Status", DictCode: model.DictEnabledStatus},
+			{Key: "createdAt", HeaderZh: "创建时间", HeaderEn: "Created At"},
+		},
+		Allowed: func(claims *jwt.CustomClaims) bool { return isPlatformRole(claims.Role) },
+		Fetch: func(claims *jwt.CustomClaims, filters url.Values, page, pageSize int) ([]map[string]string, int64, error) {
+			schools, total, err := schoolService.ListSchools(page, pageSize)
+			if err != nil {
+				return nil, 0, err
+			}
+			rows := make([]map[string]string, 0, len(schools))
+			for _, item := range schools {
+				rows = append(rows, map[string]string{
+					"id":            formatUint(item.ID),
+					"name":          item.Name,
+					"contactName":   item.ContactName,
+					"contactPhone":  item.ContactPhone,
+					"address":       item.Address,
+					"adminUsername": item.AdminUsername,
+					"isEnabled":     strconv.FormatBool(item.IsEnabled),
+					"createdAt":     formatExportTime(&item.CreatedAt),
+				})
+			}
+			return rows, total, nil
+		},
+	}
+
+	s.sources[ExportSourceSuppliers] = &exportSource{
+		SheetName: "供应商列表",
+		Columns: []exportColumn{
+			{Key: "id", HeaderZh: "ID", HeaderEn: "ID"},
+			{Key: "name", HeaderZh: "供应商名称", HeaderEn: "Supplier Name"},
+			{Key: "contactName", HeaderZh: "联系人", HeaderEn: "Contact"},
+			{Key: "contactPhone", HeaderZh: "联系电话", HeaderEn: "Phone"},
+			{Key: "address", HeaderZh: "地址", HeaderEn: "Address"},
+			{Key: "isEnabled", HeaderZh: "状态", HeaderEn: "Status", DictCode: model.DictEnabledStatus},
+			{Key: "createdAt", HeaderZh: "创建时间", HeaderEn: "Created At"},
+		},
+		Allowed: func(claims *jwt.CustomClaims) bool {
+			return isPlatformRole(claims.Role) || isSchoolRole(claims.Role)
+		},
+		Fetch: func(claims *jwt.CustomClaims, filters url.Values, page, pageSize int) ([]map[string]string, int64, error) {
+			// 学校只能导出自己的供应商，平台需通过 schoolId 指定学校
+			schoolID := claims.OrgID
+			if isPlatformRole(claims.Role) {
+				id, _ := strconv.ParseUint(filters.Get("schoolId"), 10, 32)
+				schoolID = uint(id)
+			}
+			suppliers, total, err := supplierService.ListSuppliers(page, pageSize, schoolID)
+			if err != nil {
+				return nil, 0, err
+			}
+			rows := make([]map[string]string, 0, len(suppliers))
+			for _, org := range suppliers {
+				rows = append(rows, map[string]string{
+					"id":           formatUint(org.ID),
+					"name":         org.Name,
+					"contactName":  org.ContactName,
+					"contactPhone": org.ContactPhone,
+					"address":      org.Address,
+					"isEnabled":    strconv.FormatBool(org.IsEnabled),
+					"createdAt":    formatExportTime(&org.CreatedAt),
+				})
+			}
+			return rows, total, nil
+		},
+	}
+
+	s.sources[ExportSourceAccounts] = &exportSource{
+		SheetName: "账号列表",
+		Columns: []exportColumn{
+			{Key: "id", HeaderZh: "ID", HeaderEn: "ID"},
+			{Key: "username", HeaderZh: "账号", HeaderEn: "Username"},
+			{Key: "realName", HeaderZh: "姓名", HeaderEn: "Real Name"},
+			{Key: "mobile", HeaderZh: "手机号", HeaderEn: "Mobile"},
+			{Key: "status", HeaderZh: "状态", HeaderEn: "Status", DictCode: model.DictUserStatus},
+			{Key: "createdAt", HeaderZh: "创建时间", HeaderEn: "Created At"},
+		},
+		// 与 /accounts 路由一致：仅具备创建子账号权限的角色可导出
+		Allowed: func(claims *jwt.CustomClaims) bool {
+			role, err := roleRepo.FindRoleByRoleKey(claims.Role)
+			return err == nil && role.CanCreateUsers
+		},
+		Fetch: func(claims *jwt.CustomClaims, filters url.Values, page, pageSize int) ([]map[string]string, int64, error) {
+			users, total, err := accountService.ListAccounts(claims, page, pageSize)
+			if err != nil {
+				return nil, 0, err
+			}
+			rows := make([]map[string]string, 0, len(users))
+			for _, user := range users {
+				rows = append(rows, map[string]string{
+					"id":        formatUint(user.ID),
+					"username":  user.Username,
+					"realName":  user.RealName,
+					"mobile":    user.Mobile,
+					"status":    strconv.Itoa(int(user.Status)),
+					"createdAt": formatExportTime(&user.CreatedAt),
+				})
+			}
+			return rows, total, nil
+		},
+	}
+
+	s.sources[ExportSourceOrders] = &exportSource{
+		SheetName: "订单列表",
+		Columns: []exportColumn{
+			{Key: "id", HeaderZh: "ID", HeaderEn: "ID"},
+			{Key: "orderNo", HeaderZh: "订单号", HeaderEn: "Order No"},
+			{Key: "merchantId", HeaderZh: "买家ID", HeaderEn: "Buyer ID"},
+			{Key: "supplierId", HeaderZh: "供应商ID", HeaderEn: "Supplier ID"},
+			{Key: "status", HeaderZh: "状态", HeaderEn: "Status", DictCode: model.DictOrderStatus},
+			{Key: "totalAmount", HeaderZh: "订单金额", HeaderEn: "Total Amount"},
+			{Key: "createdAt", HeaderZh: "下单时间", HeaderEn: "Ordered At"},
+			{Key: "deliveryTime", HeaderZh: "配送时间", HeaderEn: "Delivery Time"},
+			{Key: "arrivalTime", HeaderZh: "送达时间", HeaderEn: "Arrival Time"},
+		},
+		Allowed: func(claims *jwt.CustomClaims) bool { return true },
+		Fetch: func(claims *jwt.CustomClaims, filters url.Values, page, pageSize int) ([]map[string]string, int64, error) {
+			filter, err := orderExportFilter(orgRepo, claims, filters)
+			if err != nil {
+				return nil, 0, err
+			}
+			orders, total, err := orderRepo.List(*filter, page, pageSize)
+			if err != nil {
+				return nil, 0, err
+			}
+			rows := make([]map[string]string, 0, len(orders))
+			for _, order := range orders {
+				rows = append(rows, map[string]string{
+					"id":           formatUint(order.ID),
+					"orderNo":      order.OrderNo,
+					"merchantId":   formatUint(order.MerchantID),
+					"supplierId":   formatUint(order.SupplierID),
+					"status":       strconv.Itoa(int(order.Status)),
+					"totalAmount":  strconv.FormatFloat(order.TotalAmount, 'f', 2, 64),
+					"createdAt":    formatExportTime(&order.CreatedAt),
+					"deliveryTime": formatExportTime(order.DeliveryTime),
+					"arrivalTime":  formatExportTime(order.ArrivalTime),
+				})
+			}
+			return rows, total, nil
+		},
+	}
+
+	s.sources[ExportSourceLogs] = &exportSource{
+		SheetName: "操作日志",
+		Columns: []exportColumn{
+			{Key: "id", HeaderZh: "ID", HeaderEn: "ID"},
+			{Key: "username", HeaderZh: "操作人", HeaderEn: "Operator"},
+			{Key: "module", HeaderZh: "模块", HeaderEn: "Module"},
+			{Key: "action", HeaderZh: "动作", HeaderEn: "Action"},
+			{Key: "params", HeaderZh: "参数", HeaderEn: "Params"},
+			{Key: "createdAt", HeaderZh: "操作时间", HeaderEn: "Time"},
+		},
+		// 与 /logs 路由一致：仅平台管理员或员工可导出
+		Allowed: func(claims *jwt.CustomClaims) bool { return isPlatformRole(claims.Role) },
+		Fetch: func(claims *jwt.CustomClaims, filters url.Values, page, pageSize int) ([]map[string]string, int64, error) {
+			// 可通过 orgId 筛选指定组织的日志
+			id, _ := strconv.ParseUint(filters.Get("orgId"), 10, 32)
+			orgID := uint(id)
+			logs, total, err := logService.ListLogs(page, pageSize, orgID)
+			if err != nil {
+				return nil, 0, err
+			}
+			rows := make([]map[string]string, 0, len(logs))
+			for _, log := range logs {
+				rows = append(rows, map[string]string{
+					"id":        formatUint(log.ID),
+					"username":  log.Username,
+					"module":    log.Module,
+					"action":    log.Action,
+					"params":    log.Params,
+					"createdAt": formatExportTime(&log.CreatedAt),
+				})
+			}
+			return rows, total, nil
+		},
+	}
+
+	return s
+}
+
+// Columns 列出某个数据源的全部可导出列
+func (s *exportService) Columns(source, lang string, claims *jwt.CustomClaims) ([]model.ExportColumnInfo, error) {
+	src, err := s.getSource(source, claims)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]model.ExportColumnInfo, 0, len(src.Columns))
+	for _, col := range src.Columns {
+		columns = append(columns, model.ExportColumnInfo{Key: col.Key, Header: col.header(lang)})
+	}
+	return columns, nil
+}
+
+// ShouldRunAsync 校验导出参数，async=true 或数据量超过阈值时转为后台任务
+func (s *exportService) ShouldRunAsync(source string, query url.Values, claims *jwt.CustomClaims) (bool, error) {
+	plan, err := s.plan(source, query, claims)
+	if err != nil {
+		return false, err
+	}
+	if query.Get("async") == "true" {
+		return true, nil
+	}
+
+	_, total, err := plan.source.Fetch(claims, plan.filters, 1, 1)
+	if err != nil {
+		return false, err
+	}
+	return total > config.Cfg.Export.AsyncThreshold, nil
+}
+
+// Export 将导出结果直接写入 w
+func (s *exportService) Export(source string, query url.Values, claims *jwt.CustomClaims, w io.Writer) (int, error) {
+	plan, err := s.plan(source, query, claims)
+	if err != nil {
+		return 0, err
+	}
+	return s.write(plan, claims, w)
+}
+
+// CreateJob 创建后台导出任务，导出在独立的 goroutine 中完成
+func (s *exportService) CreateJob(source string, query url.Values, claims *jwt.CustomClaims) (*model.SysExportJob, error) {
+	plan, err := s.plan(source, query, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &model.SysExportJob{
+		UserID:   claims.UserID,
+		OrgID:    claims.OrgID,
+		Source:   source,
+		Format:   string(plan.format),
+		Params:   query.Encode(),
+		Status:   model.ExportJobPending,
+		FileName: ExportFileName(source, plan.format),
+	}
+	if err := s.jobRepo.Create(job); err != nil {
+		return nil, fmt.Errorf("创建导出任务失败: %w", err)
+	}
+
+	// 复制一份 claims，避免请求结束后被复用
+	jobClaims := *claims
+	go s.runJob(*job, plan, &jobClaims)
+
+	return job, nil
+}
+
+// GetJob 获取当前用户的导出任务
+func (s *exportService) GetJob(id uint, claims *jwt.CustomClaims) (*model.SysExportJob, error) {
+	job, err := s.jobRepo.GetByID(id)
+	if err != nil {
+		return nil, errors.New("导出任务不存在")
+	}
+	if job.UserID != claims.UserID {
+		return nil, errors.New("无权访问此导出任务")
+	}
+	return job, nil
+}
+
+// ListJobs 分页列出当前用户的导出任务
+func (s *exportService) ListJobs(claims *jwt.CustomClaims, page, pageSize int) ([]model.SysExportJob, int64, error) {
+	return s.jobRepo.ListByUser(claims.UserID, page, pageSize)
+}
+
+// runJob 执行后台导出，将文件写入配置的导出目录
+func (s *exportService) runJob(job model.SysExportJob, plan *exportPlan, claims *jwt.CustomClaims) {
+	// 后台协程中的 panic 不能拖垮整个进程，捕获后将任务标记为失败
+	defer func() {
+		if r := recover(); r != nil {
+			now := time.Now()
+			job.FinishedAt = &now
+			job.Status = model.ExportJobFailed
+			job.ErrorMsg = truncate(fmt.Sprintf("导出任务异常: %v", r), 255)
+			_ = s.jobRepo.Update(&job)
+		}
+	}()
+
+	job.Status = model.ExportJobProcessing
+	_ = s.jobRepo.Update(&job)
+
+	rowCount, err := s.writeFile(&job, plan, claims)
+	now := time.Now()
+	job.FinishedAt = &now
+	job.RowCount = rowCount
+	if err != nil {
+		job.Status = model.ExportJobFailed
+		job.ErrorMsg = truncate(err.Error(), 255)
+	} else {
+		job.Status = model.ExportJobDone
+	}
+	_ = s.jobRepo.Update(&job)
+}
+
+// writeFile 将导出结果写入磁盘文件，并回填文件路径
+func (s *exportService) writeFile(job *model.SysExportJob, plan *exportPlan, claims *jwt.CustomClaims) (int, error) {
+	dir := config.Cfg.Export.Dir
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("创建导出目录失败: %w", err)
+	}
+
+	job.FilePath = filepath.Join(dir, fmt.Sprintf("export-%d.%s", job.ID, plan.format))
+	file, err := os.Create(job.FilePath)
+	if err != nil {
+		return 0, fmt.Errorf("创建导出文件失败: %w", err)
+	}
+	defer file.Close()
+
+	return s.write(plan, claims, file)
+}
+
+// write 分页取数并逐行写出
+func (s *exportService) write(plan *exportPlan, claims *jwt.CustomClaims, w io.Writer) (int, error) {
+	dicts, err := s.loadDicts(plan.columns)
+	if err != nil {
+		return 0, err
+	}
+
+	sw, err := sheet.NewWriter(w, plan.format, plan.source.SheetName)
+	if err != nil {
+		return 0, err
+	}
+
+	headers := make([]string, len(plan.columns))
+	for i, col := range plan.columns {
+		headers[i] = col.header(plan.lang)
+	}
+	if err := sw.WriteRow(headers); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for page := 1; ; page++ {
+		rows, total, err := plan.source.Fetch(claims, plan.filters, page, exportPageSize)
+		if err != nil {
+			return written, err
+		}
+		for _, row := range rows {
+			values := make([]string, len(plan.columns))
+			for i, col := range plan.columns {
+				values[i] = row[col.Key]
+				if label, ok := dicts[col.DictCode][values[i]]; ok {
+					values[i] = label
+				}
+			}
+			if err := sw.WriteRow(values); err != nil {
+				return written, err
+			}
+			written++
+		}
+		if len(rows) < exportPageSize || int64(page*exportPageSize) >= total {
+			break
+		}
+	}
+
+	return written, sw.Close()
+}
+
+// plan 校验数据源、权限、格式与列选择
+func (s *exportService) plan(source string, query url.Values, claims *jwt.CustomClaims) (*exportPlan, error) {
+	src, err := s.getSource(source, claims)
+	if err != nil {
+		return nil, err
+	}
+	format, err := sheet.ParseFormat(query.Get("format"))
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &exportPlan{source: src, format: format, lang: query.Get("lang"), filters: query}
+
+	// columns 为空时导出全部列，否则按传入的顺序导出
+	selected := strings.TrimSpace(query.Get("columns"))
+	if selected == "" {
+		plan.columns = src.Columns
+		return plan, nil
+	}
+	byKey := make(map[string]exportColumn, len(src.Columns))
+	for _, col := range src.Columns {
+		byKey[col.Key] = col
+	}
+	for _, key := range strings.Split(selected, ",") {
+		col, ok := byKey[strings.TrimSpace(key)]
+		if !ok {
+			return nil, fmt.Errorf("不支持导出的列: %s", key)
+		}
+		plan.columns = append(plan.columns, col)
+	}
+	return plan, nil
+}
+
+// getSource 查找数据源并校验当前用户是否有权导出
+func (s *exportService) getSource(source string, claims *jwt.CustomClaims) (*exportSource, error) {
+	src, ok := s.sources[source]
+	if !ok {
+		return nil, fmt.Errorf("不支持导出的数据: %s", source)
+	}
+	if !src.Allowed(claims) {
+		return nil, errors.New("您的角色无权导出该数据")
+	}
+	return src, nil
+}
+
+// loadDicts 加载导出列用到的数据字典，返回 dictCode -> 存储值 -> 展示名
+func (s *exportService) loadDicts(columns []exportColumn) (map[string]map[string]string, error) {
+	var codes []string
+	for _, col := range columns {
+		if col.DictCode != "" {
+			codes = append(codes, col.DictCode)
+		}
+	}
+	items, err := s.dictRepo.ListByCodes(codes)
+	if err != nil {
+		return nil, fmt.Errorf("加载数据字典失败: %w", err)
+	}
+
+	dicts := make(map[string]map[string]string)
+	for _, item := range items {
+		if dicts[item.DictCode] == nil {
+			dicts[item.DictCode] = make(map[string]string)
+		}
+		dicts[item.DictCode][item.ItemValue] = item.ItemLabel
+	}
+	return dicts, nil
+}
+
+// orderExportFilter 根据当前用户的组织限定订单范围，并解析 status/startDate/endDate 筛选条件
+func orderExportFilter(orgRepo repository.IOrganizationRepository, claims *jwt.CustomClaims, filters url.Values) (*model.OrderListFilter, error) {
+	filter := &model.OrderListFilter{}
+	switch {
+	case isPlatformRole(claims.Role):
+		// 平台可查看全部订单
+	case isSchoolRole(claims.Role):
+		buyerIDs, err := buyerOrgIDsOfSchool(orgRepo, claims.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		filter.MerchantIDs = buyerIDs
+	case isSupplierRole(claims.Role):
+		filter.SupplierID = claims.OrgID
+	default:
+		filter.MerchantIDs = []uint{claims.OrgID}
+	}
+
+	if v := filters.Get("status"); v != "" {
+		status, err := strconv.ParseInt(v, 10, 8)
+		if err != nil {
+			return nil, errors.New("无效的订单状态")
+		}
+		s := int8(status)
+		filter.Status = &s
+	}
+	if v := filters.Get("startDate"); v != "" {
+		start, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, errors.New("无效的开始日期")
+		}
+		filter.StartDate = &start
+	}
+	if v := filters.Get("endDate"); v != "" {
+		end, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, errors.New("无效的结束日期")
+		}
+		end = end.AddDate(0, 0, 1) // 结束日期当天也包含在内
+		filter.EndDate = &end
+	}
+	return filter, nil
+}
+
+// ExportFileName 生成导出文件的下载文件名
+func ExportFileName(source string, format sheet.Format) string {
+	return fmt.Sprintf("%s-%s.%s", source, time.Now().Format("20060102150405"), format)
+}
+
+// formatExportTime 将时间格式化为导出文件中的文本，空值返回空字符串
+func formatExportTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func formatUint(v uint) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+// truncate 按字符截断字符串，用于写入有长度限制的字段
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
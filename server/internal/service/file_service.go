@@ -0,0 +1,546 @@
+// server/internal/service/file_service.go
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/imaging"
+	"server/pkg/jwt"
+	"server/pkg/storage"
+
+	"gorm.io/gorm"
+)
+
+// orphanScanBatch 清理孤儿文件时每批检查的文件数
+const orphanScanBatch = 200
+
+// mimeExtensions 允许上传的文件类型对应的扩展名
+var mimeExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+// FileOptions 定义了文件服务的运行参数
+type FileOptions struct {
+	MaxSize      int64         // 单个文件的字节数上限
+	AllowedTypes []string      // 允许的 MIME 类型，按文件内容判断而不是扩展名
+	SignExpire   time.Duration // 下载地址有效期
+	OrphanGrace  time.Duration // 上传后超过该时长仍未被引用的文件视为孤儿文件
+}
+
+// IFileService 定义文件上传与下载服务接口
+type IFileService interface {
+	// Upload 保存上传的文件，处理后内容相同的文件只保存一份并返回已有的记录，同时为当前组织登记上传记录。
+	// 图片会被旋正、去除 EXIF 并生成缩略图；purpose 为收货凭证时保留拍摄时间。
+	Upload(r io.Reader, filename, purpose string, claims *jwt.CustomClaims) (*model.FileResponse, error)
+	// SignedURL 返回文件的签名下载地址，不是本系统文件 Key 的值（如外部地址）原样返回
+	SignedURL(key string) (string, error)
+	// SignedURLs 批量返回文件的签名下载地址
+	SignedURLs(keys []string) (map[string]string, error)
+	// SignedURLsFor 校验用户能否查看引用文件的业务数据后，批量返回文件的签名下载地址
+	SignedURLsFor(keys []string, claims *jwt.CustomClaims) (map[string]string, error)
+	// ImageURLs 批量返回图片各尺寸的签名下载地址
+	ImageURLs(keys []string) (map[string]*model.ImageURLs, error)
+	// FillProductImages 为商品填充标准图各尺寸的下载地址，失败时只记录日志
+	FillProductImages(products []model.ScmProduct)
+	// FillOrderVouchers 为订单填充收货凭证和验收签名各尺寸的下载地址，失败时只记录日志
+	FillOrderVouchers(orders []model.OrdOrder)
+	// LocalPath 校验本地存储的签名下载参数并返回文件在磁盘上的路径
+	LocalPath(key, expires, signature string) (string, error)
+	// CleanupOrphans 删除上传后长时间未被任何业务数据引用的文件
+	CleanupOrphans() (*model.OrphanCleanupResult, error)
+	// StartCleanup 启动后台定时任务，按固定间隔清理孤儿文件
+	StartCleanup(interval time.Duration)
+}
+
+// fileService 实现了 IFileService 接口
+type fileService struct {
+	fileRepo    repository.IFileRepository
+	orderRepo   repository.IOrderRepository
+	orgRepo     repository.IOrganizationRepository
+	quoteRepo   repository.IQuoteRepository
+	productRepo repository.IProductRepository
+	store       storage.Storage
+	opts        FileOptions
+	allowed     map[string]bool
+}
+
+// NewFileService 创建一个新的 fileService 实例
+func NewFileService(
+	fileRepo repository.IFileRepository,
+	orderRepo repository.IOrderRepository,
+	orgRepo repository.IOrganizationRepository,
+	quoteRepo repository.IQuoteRepository,
+	productRepo repository.IProductRepository,
+	store storage.Storage,
+	opts FileOptions,
+) IFileService {
+	allowed := make(map[string]bool, len(opts.AllowedTypes))
+	for _, t := range opts.AllowedTypes {
+		allowed[t] = true
+	}
+	return &fileService{
+		fileRepo:    fileRepo,
+		orderRepo:   orderRepo,
+		orgRepo:     orgRepo,
+		quoteRepo:   quoteRepo,
+		productRepo: productRepo,
+		store:       store,
+		opts:        opts,
+		allowed:     allowed,
+	}
+}
+
+// Upload 保存上传的文件。
+// 文件先写入临时文件，校验大小和类型并处理图片后，按实际保存内容的 SHA-256 去重再写入存储后端。
+func (s *fileService) Upload(r io.Reader, filename, purpose string, claims *jwt.CustomClaims) (*model.FileResponse, error) {
+	// 1. 写入临时文件，多读一个字节用于判断是否超出大小上限
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), io.LimitReader(r, s.opts.MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("读取上传文件失败: %w", err)
+	}
+	if size == 0 {
+		return nil, errors.New("上传的文件为空")
+	}
+	if size > s.opts.MaxSize {
+		return nil, fmt.Errorf("文件不能超过 %dMB", s.opts.MaxSize>>20)
+	}
+
+	// 2. 按文件内容判断类型，不信任扩展名和客户端声明的类型
+	head := make([]byte, 512)
+	n, err := tmp.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	mimeType := strings.TrimSpace(strings.Split(http.DetectContentType(head[:n]), ";")[0])
+	if !s.allowed[mimeType] {
+		return nil, fmt.Errorf("不支持的文件类型: %s", mimeType)
+	}
+
+	// 3. 图片在去重前处理，去重按处理后实际保存的内容计算；相同内容作为收货凭证再次上传时需要补记拍摄时间
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var processed *imaging.Result
+	var content io.Reader = tmp
+	if imaging.Processable(mimeType) {
+		data, err := io.ReadAll(tmp)
+		if err != nil {
+			return nil, err
+		}
+		if processed, err = imaging.Process(data, mimeType, imaging.Thumb, imaging.Medium); err != nil {
+			return nil, err
+		}
+		if processed.Original != nil {
+			data, size = processed.Original, int64(len(processed.Original))
+		}
+		content = bytes.NewReader(data)
+		hasher.Reset()
+		hasher.Write(data)
+	}
+	var capturedAt *time.Time
+	if processed != nil && purpose == model.FilePurposeReceipt {
+		capturedAt = processed.CapturedAt
+	}
+
+	// 4. 相同内容的文件已存在时登记本组织的上传记录后直接返回
+	hash := hex.EncodeToString(hasher.Sum(nil))
+	if existing, err := s.fileRepo.GetByHash(hash); err == nil {
+		if existing.CapturedAt == nil && capturedAt != nil {
+			existing.CapturedAt = capturedAt
+			if err := s.fileRepo.Update(existing); err != nil {
+				return nil, err
+			}
+		}
+		return s.existingResponse(existing, claims)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// 5. 先写入缩略图再写入原图，原图写入成功才登记，避免记录指向不完整的文件
+	file := &model.SysFile{
+		Hash:         hash,
+		Key:          fileKey(hash, mimeType),
+		Size:         size,
+		MimeType:     mimeType,
+		OriginalName: filepath.Base(filename),
+		UploaderID:   claims.UserID,
+		OrgID:        claims.OrgID,
+		CapturedAt:   capturedAt,
+	}
+	ctx := context.Background()
+	if processed != nil {
+		file.Width, file.Height, file.VariantExt = processed.Width, processed.Height, processed.VariantExt
+		for name, data := range processed.Variants {
+			key := variantKey(file.Key, name, file.VariantExt)
+			if err := s.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), processed.VariantMIME); err != nil {
+				return nil, fmt.Errorf("保存缩略图失败: %w", err)
+			}
+		}
+	}
+	if err := s.store.Put(ctx, file.Key, content, size, mimeType); err != nil {
+		return nil, fmt.Errorf("保存文件失败: %w", err)
+	}
+	if err := s.fileRepo.Create(file); err != nil {
+		// 并发上传相同内容时另一请求已登记，对象内容相同，直接使用已有记录
+		if existing, getErr := s.fileRepo.GetByHash(hash); getErr == nil {
+			return s.existingResponse(existing, claims)
+		}
+		return nil, fmt.Errorf("保存文件记录失败: %w", err)
+	}
+	if err := s.recordUpload(file, claims); err != nil {
+		return nil, err
+	}
+	return s.response(file, false)
+}
+
+// existingResponse 为已存在的文件登记当前组织的上传记录并返回
+func (s *fileService) existingResponse(file *model.SysFile, claims *jwt.CustomClaims) (*model.FileResponse, error) {
+	if err := s.recordUpload(file, claims); err != nil {
+		return nil, err
+	}
+	return s.response(file, true)
+}
+
+// recordUpload 登记当前组织上传了文件，业务数据只能引用本组织上传过的文件
+func (s *fileService) recordUpload(file *model.SysFile, claims *jwt.CustomClaims) error {
+	upload := &model.SysFileUpload{FileID: file.ID, OrgID: claims.OrgID, UploaderID: claims.UserID}
+	if err := s.fileRepo.CreateUpload(upload); err != nil {
+		return fmt.Errorf("保存上传记录失败: %w", err)
+	}
+	return nil
+}
+
+// SignedURL 返回文件的签名下载地址
+func (s *fileService) SignedURL(key string) (string, error) {
+	if key == "" || !isFileKey(key) {
+		return key, nil
+	}
+	return s.store.SignedURL(context.Background(), key, s.opts.SignExpire)
+}
+
+// SignedURLs 批量返回文件的签名下载地址
+func (s *fileService) SignedURLs(keys []string) (map[string]string, error) {
+	urls := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if _, ok := urls[key]; ok {
+			continue
+		}
+		u, err := s.SignedURL(key)
+		if err != nil {
+			return nil, err
+		}
+		urls[key] = u
+	}
+	return urls, nil
+}
+
+// SignedURLsFor 校验下载权限后批量返回文件的签名下载地址
+func (s *fileService) SignedURLsFor(keys []string, claims *jwt.CustomClaims) (map[string]string, error) {
+	for _, key := range uniqueKeys(keys) {
+		if err := s.checkAccess(key, claims); err != nil {
+			return nil, err
+		}
+	}
+	return s.SignedURLs(keys)
+}
+
+// checkAccess 判断用户能否获取文件的下载地址：平台用户和上传过该文件的组织可直接访问，
+// 其他用户需要能查看至少一条引用该文件的业务数据
+func (s *fileService) checkAccess(key string, claims *jwt.CustomClaims) error {
+	if key == "" || !isFileKey(key) || isPlatformRole(claims.Role) {
+		return nil
+	}
+	file, err := s.fileRepo.GetByKey(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("文件不存在")
+		}
+		return err
+	}
+	uploaded, err := s.fileRepo.HasUpload(file.ID, claims.OrgID)
+	if err != nil {
+		return err
+	}
+	if uploaded {
+		return nil
+	}
+
+	owners, err := s.fileRepo.ListOwners(key)
+	if err != nil {
+		return err
+	}
+	for _, owner := range owners {
+		visible, err := s.ownerVisible(owner, claims)
+		if err != nil {
+			return err
+		}
+		if visible {
+			return nil
+		}
+	}
+	return errors.New("无权访问此文件")
+}
+
+// ownerVisible 判断用户能否查看引用文件的业务数据
+func (s *fileService) ownerVisible(owner model.FileOwner, claims *jwt.CustomClaims) (bool, error) {
+	switch owner.Kind {
+	case model.FileOwnerPublic:
+		return true, nil
+	case model.FileOwnerOrder:
+		_, err := getVisibleOrder(s.orderRepo, s.orgRepo, owner.ID, claims)
+		return err == nil, nil
+	case model.FileOwnerQuote:
+		// 报价的检测报告对报价供应商和本校的学校、食堂、商户可见
+		quote, err := s.quoteRepo.GetByID(owner.ID)
+		if err != nil {
+			return false, nil
+		}
+		if quote.SupplierID == claims.OrgID {
+			return true, nil
+		}
+		if isSupplierRole(claims.Role) {
+			return false, nil
+		}
+		product, err := s.productRepo.GetByID(quote.ProductID)
+		if err != nil {
+			return false, nil
+		}
+		schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+		return err == nil && product.SchoolID == schoolID, nil
+	case model.FileOwnerSupplier:
+		return owner.ID == claims.OrgID, nil
+	}
+	return false, nil
+}
+
+// ImageURLs 批量返回图片各尺寸的签名下载地址
+func (s *fileService) ImageURLs(keys []string) (map[string]*model.ImageURLs, error) {
+	fileKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if isFileKey(key) {
+			fileKeys = append(fileKeys, key)
+		}
+	}
+	files, err := s.fileRepo.ListByKeys(fileKeys)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*model.SysFile, len(files))
+	for i := range files {
+		byKey[files[i].Key] = &files[i]
+	}
+
+	result := make(map[string]*model.ImageURLs, len(keys))
+	for _, key := range keys {
+		if key == "" || result[key] != nil {
+			continue
+		}
+		u, err := s.SignedURL(key)
+		if err != nil {
+			return nil, err
+		}
+		urls := &model.ImageURLs{Key: key, URL: u, Medium: u, Thumb: u}
+		if f, ok := byKey[key]; ok && f.VariantExt != "" {
+			if urls.Medium, err = s.SignedURL(variantKey(key, imaging.Medium.Name, f.VariantExt)); err != nil {
+				return nil, err
+			}
+			if urls.Thumb, err = s.SignedURL(variantKey(key, imaging.Thumb.Name, f.VariantExt)); err != nil {
+				return nil, err
+			}
+		}
+		result[key] = urls
+	}
+	return result, nil
+}
+
+// FillProductImages 为商品填充标准图各尺寸的下载地址
+func (s *fileService) FillProductImages(products []model.ScmProduct) {
+	keys := make([]string, 0, len(products))
+	for i := range products {
+		keys = append(keys, products[i].Image)
+	}
+	urls, err := s.ImageURLs(keys)
+	if err != nil {
+		log.Printf("生成商品图片地址失败: %v", err)
+		return
+	}
+	for i := range products {
+		products[i].ImageURLs = urls[products[i].Image]
+	}
+}
+
+// FillOrderVouchers 为订单填充收货凭证各尺寸的下载地址，收货凭证为文件 Key 的 JSON 数组
+func (s *fileService) FillOrderVouchers(orders []model.OrdOrder) {
+	vouchers := make([][]string, len(orders))
+	var keys []string
+	for i := range orders {
+		if orders[i].ReceiptVouchers == "" {
+			continue
+		}
+		if err := json.Unmarshal([]byte(orders[i].ReceiptVouchers), &vouchers[i]); err != nil {
+			log.Printf("解析订单 [%s] 收货凭证失败: %v", orders[i].OrderNo, err)
+			continue
+		}
+		keys = append(keys, vouchers[i]...)
+	}
+	for i := range orders {
+		if orders[i].ReceiptSignature != "" {
+			keys = append(keys, orders[i].ReceiptSignature)
+		}
+	}
+	urls, err := s.ImageURLs(keys)
+	if err != nil {
+		log.Printf("生成收货凭证地址失败: %v", err)
+		return
+	}
+	for i := range orders {
+		orders[i].VoucherImages = make([]model.ImageURLs, 0, len(vouchers[i]))
+		for _, key := range vouchers[i] {
+			if u, ok := urls[key]; ok {
+				orders[i].VoucherImages = append(orders[i].VoucherImages, *u)
+			}
+		}
+		if orders[i].ReceiptSignature != "" {
+			orders[i].SignatureImage = urls[orders[i].ReceiptSignature]
+		}
+	}
+}
+
+// LocalPath 校验签名并返回本地文件路径
+func (s *fileService) LocalPath(key, expires, signature string) (string, error) {
+	local, ok := s.store.(*storage.Local)
+	if !ok {
+		return "", errors.New("当前存储不支持直接下载")
+	}
+	if !local.Verify(key, expires, signature) {
+		return "", errors.New("下载地址无效或已过期")
+	}
+	return local.Path(key)
+}
+
+// CleanupOrphans 删除孤儿文件，先删除存储对象再删除记录，对象删除失败时保留记录等待下次清理
+func (s *fileService) CleanupOrphans() (*model.OrphanCleanupResult, error) {
+	result := &model.OrphanCleanupResult{}
+	before := time.Now().Add(-s.opts.OrphanGrace)
+	var afterID uint
+	for {
+		files, err := s.fileRepo.ListCreatedBefore(before, afterID, orphanScanBatch)
+		if err != nil {
+			return result, err
+		}
+		for _, f := range files {
+			result.Scanned++
+			referenced, err := s.fileRepo.IsReferenced(f.Key)
+			if err != nil {
+				return result, err
+			}
+			if referenced {
+				continue
+			}
+			if err := s.deleteObjects(&f); err != nil {
+				log.Printf("删除孤儿文件 [%s] 失败: %v", f.Key, err)
+				continue
+			}
+			if err := s.fileRepo.Delete(f.ID); err != nil {
+				return result, err
+			}
+			result.Deleted++
+		}
+		if len(files) < orphanScanBatch {
+			break
+		}
+		afterID = files[len(files)-1].ID
+	}
+	return result, nil
+}
+
+// StartCleanup 启动后台定时任务
+func (s *fileService) StartCleanup(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := s.CleanupOrphans(); err != nil {
+				log.Printf("清理孤儿文件失败: %v", err)
+			}
+		}
+	}()
+}
+
+// deleteObjects 删除文件及其缩略图的存储对象
+func (s *fileService) deleteObjects(file *model.SysFile) error {
+	ctx := context.Background()
+	if file.VariantExt != "" {
+		for _, v := range []imaging.Variant{imaging.Thumb, imaging.Medium} {
+			if err := s.store.Delete(ctx, variantKey(file.Key, v.Name, file.VariantExt)); err != nil {
+				return err
+			}
+		}
+	}
+	return s.store.Delete(ctx, file.Key)
+}
+
+// response 组装文件的返回结构
+func (s *fileService) response(file *model.SysFile, duplicate bool) (*model.FileResponse, error) {
+	urls, err := s.ImageURLs([]string{file.Key})
+	if err != nil {
+		return nil, err
+	}
+	resp := &model.FileResponse{
+		ID:         file.ID,
+		Key:        file.Key,
+		Size:       file.Size,
+		MimeType:   file.MimeType,
+		URL:        urls[file.Key].URL,
+		Duplicate:  duplicate,
+		Width:      file.Width,
+		Height:     file.Height,
+		CapturedAt: file.CapturedAt,
+	}
+	if file.VariantExt != "" {
+		resp.Medium, resp.Thumb = urls[file.Key].Medium, urls[file.Key].Thumb
+	}
+	return resp, nil
+}
+
+// fileKey 根据内容哈希生成存储路径，按哈希前两位分目录
+func fileKey(hash, mimeType string) string {
+	return "files/" + hash[:2] + "/" + hash + mimeExtensions[mimeType]
+}
+
+// variantKey 返回缩略图的存储路径，如 files/ab/abcd.jpg 的 thumb 为 files/ab/abcd_thumb.jpg
+func variantKey(key, name, ext string) string {
+	return strings.TrimSuffix(key, filepath.Ext(key)) + "_" + name + ext
+}
+
+// isFileKey 判断值是否为本系统生成的文件 Key
+func isFileKey(key string) bool {
+	return strings.HasPrefix(key, "files/") && !strings.Contains(key, "..")
+}
@@ -0,0 +1,218 @@
+// server/internal/service/guide_price_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// ErrQuoteAboveCeiling 表示报价超出了指导价上限，且学校设置为直接拒绝
+var ErrQuoteAboveCeiling = errors.New("报价超出学校指导价上限")
+
+// IGuidePriceService 定义学校指导价服务接口
+type IGuidePriceService interface {
+	// SetGuidePrice 学校为本校商品设置指导价和允许的偏离比例
+	SetGuidePrice(productID uint, req *model.SetGuidePriceRequest, claims *jwt.CustomClaims) (*model.ScmGuidePrice, error)
+	DeleteGuidePrice(productID uint, claims *jwt.CustomClaims) error
+	ListGuidePrices(keyword string, claims *jwt.CustomClaims, page, pageSize int) ([]model.GuidePriceItem, int64, error)
+	// CheckQuotePrice 校验报价是否超出指导价上限，返回报价应处的复核状态。
+	// 超出上限且学校设置为直接拒绝时返回 ErrQuoteAboveCeiling。
+	CheckQuotePrice(productID uint, price float64) (int8, error)
+	// ListReviewQueue 列出本校待复核的超价报价
+	ListReviewQueue(claims *jwt.CustomClaims, page, pageSize int) ([]model.QuoteBandItem, int64, error)
+	ApproveQuote(quoteID uint, claims *jwt.CustomClaims) error
+	// RejectQuote 驳回超价报价，报价会被停用
+	RejectQuote(quoteID uint, req *model.RejectQuoteRequest, claims *jwt.CustomClaims) error
+	// OutOfBandReport 列出本校当前价格超出指导价区间的启用报价
+	OutOfBandReport(claims *jwt.CustomClaims, page, pageSize int) ([]model.QuoteBandItem, int64, error)
+}
+
+// guidePriceService 实现了 IGuidePriceService 接口
+type guidePriceService struct {
+	guideRepo      repository.IGuidePriceRepository
+	productRepo    repository.IProductRepository
+	quoteRepo      repository.IQuoteRepository
+	productService IProductService
+}
+
+// NewGuidePriceService 创建一个新的 guidePriceService 实例
+func NewGuidePriceService(guideRepo repository.IGuidePriceRepository, productRepo repository.IProductRepository, quoteRepo repository.IQuoteRepository, productService IProductService) IGuidePriceService {
+	return &guidePriceService{
+		guideRepo:      guideRepo,
+		productRepo:    productRepo,
+		quoteRepo:      quoteRepo,
+		productService: productService,
+	}
+}
+
+// SetGuidePrice 设置商品指导价，已存在时覆盖
+func (s *guidePriceService) SetGuidePrice(productID uint, req *model.SetGuidePriceRequest, claims *jwt.CustomClaims) (*model.ScmGuidePrice, error) {
+	product, err := s.productRepo.GetByID(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("商品不存在")
+		}
+		return nil, err
+	}
+	if product.SchoolID != claims.OrgID {
+		return nil, errors.New("无权为其他学校的商品设置指导价")
+	}
+
+	guide, err := s.guideRepo.GetByProduct(productID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	guide.SchoolID = product.SchoolID
+	guide.ProductID = product.ID
+	guide.GuidePrice = req.GuidePrice
+	guide.Deviation = req.Deviation
+	guide.OverMode = req.OverMode
+	guide.OperatorID = claims.UserID
+	if err := s.guideRepo.Save(guide); err != nil {
+		return nil, fmt.Errorf("保存指导价失败: %w", err)
+	}
+	return guide, nil
+}
+
+// DeleteGuidePrice 删除商品指导价，之后该商品的报价不再受限
+func (s *guidePriceService) DeleteGuidePrice(productID uint, claims *jwt.CustomClaims) error {
+	guide, err := s.guideRepo.GetByProduct(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("该商品未设置指导价")
+		}
+		return err
+	}
+	if guide.SchoolID != claims.OrgID {
+		return errors.New("无权删除其他学校的指导价")
+	}
+	return s.guideRepo.DeleteByProduct(productID)
+}
+
+// ListGuidePrices 列出本校设置的指导价
+func (s *guidePriceService) ListGuidePrices(keyword string, claims *jwt.CustomClaims, page, pageSize int) ([]model.GuidePriceItem, int64, error) {
+	return s.guideRepo.ListBySchool(claims.OrgID, keyword, page, pageSize)
+}
+
+// CheckQuotePrice 校验报价是否超出指导价上限
+func (s *guidePriceService) CheckQuotePrice(productID uint, price float64) (int8, error) {
+	guide, err := s.guideRepo.GetByProduct(productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.QuoteReviewNone, nil
+		}
+		return 0, err
+	}
+
+	ceiling := roundMoney(guide.GuidePrice * (1 + guide.Deviation/100))
+	if price <= ceiling {
+		return model.QuoteReviewNone, nil
+	}
+	if guide.OverMode == model.GuidePriceOverReject {
+		return 0, fmt.Errorf("%w（指导价 %.2f 元，上限 %.2f 元）", ErrQuoteAboveCeiling, guide.GuidePrice, ceiling)
+	}
+	return model.QuoteReviewPending, nil
+}
+
+// ListReviewQueue 列出本校待复核的超价报价
+func (s *guidePriceService) ListReviewQueue(claims *jwt.CustomClaims, page, pageSize int) ([]model.QuoteBandItem, int64, error) {
+	pending := model.QuoteReviewPending
+	return s.listQuoteBand(model.QuoteBandFilter{SchoolID: claims.OrgID, ReviewStatus: &pending}, page, pageSize)
+}
+
+// ApproveQuote 复核通过超价报价
+func (s *guidePriceService) ApproveQuote(quoteID uint, claims *jwt.CustomClaims) error {
+	if _, err := s.getPendingQuote(quoteID, claims); err != nil {
+		return err
+	}
+	return s.review(quoteID, map[string]interface{}{
+		"review_status": model.QuoteReviewAccepted,
+		"review_reason": "",
+	})
+}
+
+// RejectQuote 驳回超价报价并停用，商品已无可用报价时自动下架
+func (s *guidePriceService) RejectQuote(quoteID uint, req *model.RejectQuoteRequest, claims *jwt.CustomClaims) error {
+	quote, err := s.getPendingQuote(quoteID, claims)
+	if err != nil {
+		return err
+	}
+	err = s.review(quoteID, map[string]interface{}{
+		"review_status": model.QuoteReviewRejected,
+		"review_reason": req.Reason,
+		"is_enabled":    false,
+	})
+	if err != nil {
+		return err
+	}
+	return s.productService.SyncListing(quote.ProductID)
+}
+
+// review 锁定报价并确认仍待复核后写入复核结果，只更新 fields 中的列
+func (s *guidePriceService) review(quoteID uint, fields map[string]interface{}) error {
+	return s.quoteRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewQuoteRepository(tx)
+		quote, err := txRepo.LockByID(quoteID)
+		if err != nil {
+			return err
+		}
+		if quote.ReviewStatus != model.QuoteReviewPending {
+			return errors.New("报价已被调整或复核，请刷新后重试")
+		}
+		return txRepo.UpdateFields(quoteID, fields)
+	})
+}
+
+// OutOfBandReport 列出本校当前价格超出指导价区间的启用报价
+func (s *guidePriceService) OutOfBandReport(claims *jwt.CustomClaims, page, pageSize int) ([]model.QuoteBandItem, int64, error) {
+	return s.listQuoteBand(model.QuoteBandFilter{SchoolID: claims.OrgID, OutOfBand: true}, page, pageSize)
+}
+
+// listQuoteBand 查询报价并计算其指导价区间和偏离比例
+func (s *guidePriceService) listQuoteBand(filter model.QuoteBandFilter, page, pageSize int) ([]model.QuoteBandItem, int64, error) {
+	items, total, err := s.guideRepo.ListQuoteBand(filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range items {
+		item := &items[i]
+		if item.GuidePrice == nil || item.Deviation == nil {
+			continue
+		}
+		guide, deviation := *item.GuidePrice, *item.Deviation
+		floor := roundMoney(guide * (1 - deviation/100))
+		ceiling := roundMoney(guide * (1 + deviation/100))
+		rate := math.Round((item.Price/guide-1)*10000) / 100
+		item.Floor, item.Ceiling, item.DeviationRate = &floor, &ceiling, &rate
+	}
+	return items, total, nil
+}
+
+// getPendingQuote 获取本校待复核的报价
+func (s *guidePriceService) getPendingQuote(quoteID uint, claims *jwt.CustomClaims) (*model.ScmProductQuote, error) {
+	quote, err := s.quoteRepo.GetByID(quoteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("报价不存在")
+		}
+		return nil, err
+	}
+	product, err := s.productRepo.GetByID(quote.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if product.SchoolID != claims.OrgID {
+		return nil, errors.New("无权复核其他学校的报价")
+	}
+	if quote.ReviewStatus != model.QuoteReviewPending {
+		return nil, errors.New("只能复核待复核状态的报价")
+	}
+	return quote, nil
+}
@@ -0,0 +1,301 @@
+// server/internal/service/import_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+	"server/pkg/sheet"
+
+	"gorm.io/gorm"
+)
+
+// 支持的导入类型
+const (
+	ImportTypeSchools   = "schools"
+	ImportTypeSuppliers = "suppliers"
+	ImportTypeAccounts  = "accounts"
+)
+
+// phonePattern 校验手机号（11位）或带区号的固定电话
+var phonePattern = regexp.MustCompile(`^(1[3-9]\d{9}|0\d{2,3}-?\d{7,8})$`)
+
+// importColumn 描述导入模板中的一列
+type importColumn struct {
+	Key      string
+	Header   string
+	Required bool
+	Phone    bool // 是否按电话号码格式校验
+	Password bool // 是否按密码规则校验
+	Username bool // 是否为登录账号，需要做唯一性校验
+}
+
+// importTemplates 定义了每种导入类型的模板列，表头文字即模板中的列名
+var importTemplates = map[string][]importColumn{
+	ImportTypeSchools: {
+		{Key: "name", Header: "学校名称", Required: true},
+		{Key: "contactName", Header: "联系人"},
+		{Key: "contactPhone", Header: "联系电话", Phone: true},
+		{Key: "address", Header: "地址"},
+		{Key: "adminUsername", Header: "管理员账号", Required: true, Username: true},
+		{Key: "adminPassword", Header: "管理员密码", Required: true, Password: true},
+		{Key: "adminRealName", Header: "管理员姓名", Required: true},
+	},
+	ImportTypeSuppliers: {
+		{Key: "name", Header: "供应商名称", Required: true},
+		{Key: "contactName", Header: "联系人", Required: true},
+		{Key: "contactPhone", Header: "联系电话", Required: true, Phone: true},
+		{Key: "address", Header: "地址"},
+		{Key: "username", Header: "管理员账号", Required: true, Username: true},
+		{Key: "password", Header: "管理员密码", Required: true, Password: true},
+		{Key: "realName", Header: "管理员姓名", Required: true},
+	},
+	ImportTypeAccounts: {
+		{Key: "username", Header: "账号", Required: true, Username: true},
+		{Key: "password", Header: "密码", Required: true, Password: true},
+		{Key: "realName", Header: "姓名", Required: true},
+		{Key: "mobile", Header: "手机号", Phone: true},
+	},
+}
+
+// importRow 是解析后的一行数据
+type importRow struct {
+	Line   int
+	Values map[string]string
+}
+
+// IImportService 定义批量导入服务接口
+type IImportService interface {
+	// Import 解析并校验导入文件，非试运行模式下在一个事务中写入所有校验通过的行
+	Import(importType string, r io.Reader, format sheet.Format, dryRun bool, claims *jwt.CustomClaims) (*model.ImportResult, error)
+	// WriteTemplate 输出指定导入类型的空白模板
+	WriteTemplate(importType string, w io.Writer, format sheet.Format) error
+}
+
+// importService 实现了 IImportService 接口
+type importService struct {
+	orgRepo  repository.IOrganizationRepository
+	userRepo repository.IUserRepository
+}
+
+// NewImportService 创建一个新的 importService 实例
+func NewImportService(orgRepo repository.IOrganizationRepository, userRepo repository.IUserRepository) IImportService {
+	return &importService{
+		orgRepo:  orgRepo,
+		userRepo: userRepo,
+	}
+}
+
+// WriteTemplate 输出只包含表头的导入模板，必填列以 * 结尾
+func (s *importService) WriteTemplate(importType string, w io.Writer, format sheet.Format) error {
+	columns, ok := importTemplates[importType]
+	if !ok {
+		return fmt.Errorf("不支持的导入类型: %s", importType)
+	}
+
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.Header
+		if col.Required {
+			headers[i] += "*"
+		}
+	}
+
+	sw, err := sheet.NewWriter(w, format, importType)
+	if err != nil {
+		return err
+	}
+	if err := sw.WriteRow(headers); err != nil {
+		return err
+	}
+	return sw.Close()
+}
+
+// Import 解析、校验并导入数据
+func (s *importService) Import(importType string, r io.Reader, format sheet.Format, dryRun bool, claims *jwt.CustomClaims) (*model.ImportResult, error) {
+	columns, ok := importTemplates[importType]
+	if !ok {
+		return nil, fmt.Errorf("不支持的导入类型: %s", importType)
+	}
+
+	// 1. 读取并解析文件
+	records, err := sheet.Read(r, format)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := parseImportRows(records, columns)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("导入文件中没有数据")
+	}
+
+	// 2. 逐行校验，生成错误报告
+	result := &model.ImportResult{Type: importType, DryRun: dryRun, Total: len(rows), Errors: []model.ImportRowError{}}
+	validRows := make([]importRow, 0, len(rows))
+	seenUsernames := make(map[string]int)
+	for _, row := range rows {
+		rowErrors, err := s.validateRow(row, columns, seenUsernames)
+		if err != nil {
+			return nil, err
+		}
+		if len(rowErrors) > 0 {
+			result.Errors = append(result.Errors, model.ImportRowError{Row: row.Line, Errors: rowErrors})
+			continue
+		}
+		validRows = append(validRows, row)
+	}
+	result.Valid = len(validRows)
+	result.Invalid = result.Total - result.Valid
+
+	if dryRun || len(validRows) == 0 {
+		return result, nil
+	}
+
+	// 3. 在一个事务中写入所有合法行，任意一行失败则整体回滚
+	err = s.orgRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txOrgRepo := repository.NewOrganizationRepository(tx)
+		txUserRepo := repository.NewUserRepository(tx)
+		txRoleRepo := repository.NewRoleRepository(tx)
+
+		for _, row := range validRows {
+			if err := commitImportRow(importType, row, claims, txOrgRepo, txUserRepo, txRoleRepo); err != nil {
+				return fmt.Errorf("第 %d 行导入失败: %w", row.Line, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result.Imported = len(validRows)
+	return result, nil
+}
+
+// validateRow 校验单行数据，返回该行的全部错误信息
+func (s *importService) validateRow(row importRow, columns []importColumn, seenUsernames map[string]int) ([]string, error) {
+	var rowErrors []string
+	for _, col := range columns {
+		value := row.Values[col.Key]
+		if value == "" {
+			if col.Required {
+				rowErrors = append(rowErrors, fmt.Sprintf("%s不能为空", col.Header))
+			}
+			continue
+		}
+
+		if col.Phone && !phonePattern.MatchString(value) {
+			rowErrors = append(rowErrors, fmt.Sprintf("%s格式不正确", col.Header))
+		}
+		if col.Password && len(value) < 6 {
+			rowErrors = append(rowErrors, fmt.Sprintf("%s长度不能少于6位", col.Header))
+		}
+		if col.Username {
+			if line, ok := seenUsernames[value]; ok {
+				rowErrors = append(rowErrors, fmt.Sprintf("%s [%s] 与第 %d 行重复", col.Header, value, line))
+				continue
+			}
+			seenUsernames[value] = row.Line
+
+			_, err := s.userRepo.GetUserByUsername(value)
+			if err == nil {
+				rowErrors = append(rowErrors, fmt.Sprintf("%s [%s] 已存在", col.Header, value))
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("检查用户名失败: %w", err)
+			}
+		}
+	}
+	return rowErrors, nil
+}
+
+// commitImportRow 复用已有的业务服务写入一行数据，所有仓库均绑定在同一事务上
+func commitImportRow(importType string, row importRow, claims *jwt.CustomClaims,
+	orgRepo repository.IOrganizationRepository, userRepo repository.IUserRepository, roleRepo repository.IRoleRepository) error {
+	v := row.Values
+	switch importType {
+	case ImportTypeSchools:
+		return NewSchoolService(orgRepo, userRepo, roleRepo).CreateSchool(&model.CreateSchoolRequest{
+			Name:          v["name"],
+			ContactName:   v["contactName"],
+			ContactPhone:  v["contactPhone"],
+			Address:       v["address"],
+			AdminUsername: v["adminUsername"],
+			AdminPassword: v["adminPassword"],
+			AdminRealName: v["adminRealName"],
+		})
+	case ImportTypeSuppliers:
+		_, _, err := NewSupplierService(orgRepo, userRepo, roleRepo).CreateSupplierWithAdmin(&CreateSupplierRequest{
+			Name:         v["name"],
+			ContactName:  v["contactName"],
+			ContactPhone: v["contactPhone"],
+			Address:      v["address"],
+			Username:     v["username"],
+			Password:     v["password"],
+			RealName:     v["realName"],
+		}, claims.UserID, claims.OrgID)
+		return err
+	case ImportTypeAccounts:
+		return NewAccountService(userRepo, roleRepo).CreateAccount(&model.CreateAccountRequest{
+			Username: v["username"],
+			Password: v["password"],
+			RealName: v["realName"],
+			Mobile:   v["mobile"],
+		}, claims)
+	default:
+		return fmt.Errorf("不支持的导入类型: %s", importType)
+	}
+}
+
+// parseImportRows 按表头将原始记录映射为键值行，跳过空行
+func parseImportRows(records [][]string, columns []importColumn) ([]importRow, error) {
+	if len(records) == 0 {
+		return nil, errors.New("导入文件为空")
+	}
+
+	// 1. 根据表头定位每一列，表头允许带 * 号
+	headerIndex := make(map[string]int)
+	for i, h := range records[0] {
+		headerIndex[strings.TrimSuffix(strings.TrimSpace(h), "*")] = i
+	}
+	colIndex := make(map[string]int, len(columns))
+	var missing []string
+	for _, col := range columns {
+		idx, ok := headerIndex[col.Header]
+		if !ok {
+			missing = append(missing, col.Header)
+			continue
+		}
+		colIndex[col.Key] = idx
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("模板缺少列: %s", strings.Join(missing, "、"))
+	}
+
+	// 2. 逐行读取
+	var rows []importRow
+	for i, record := range records[1:] {
+		values := make(map[string]string, len(columns))
+		empty := true
+		for key, idx := range colIndex {
+			if idx < len(record) {
+				values[key] = strings.TrimSpace(record[idx])
+				if values[key] != "" {
+					empty = false
+				}
+			}
+		}
+		if empty {
+			continue
+		}
+		rows = append(rows, importRow{Line: i + 2, Values: values})
+	}
+	return rows, nil
+}
@@ -0,0 +1,252 @@
+// server/internal/service/master_product_service.go
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// IMasterProductService 定义平台标准商品库服务接口
+type IMasterProductService interface {
+	CreateMaster(req *model.MasterProductRequest) (*model.ScmMasterProduct, error)
+	// UpdateMaster 修改标准商品，并将修改同步到所有学校副本（规格已锁定的副本不修改规格和单位）
+	UpdateMaster(id uint, req *model.MasterProductRequest) (*model.MasterSyncResult, error)
+	// SetMasterEnabled 启用或停用标准商品，停用后不能再下发，已生成的副本不受影响
+	SetMasterEnabled(id uint, enabled bool) error
+	// ListMasters 列出标准商品，非平台用户只能看到可下发的商品
+	ListMasters(filter model.MasterProductListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.ScmMasterProduct, int64, error)
+	// ListCopies 列出标准商品在各学校的副本
+	ListCopies(id uint) ([]model.ScmProduct, error)
+	// Push 平台将标准商品下发到指定学校，学校已有的标准商品会被跳过
+	Push(req *model.PushMasterProductsRequest, claims *jwt.CustomClaims) (*model.PushResult, error)
+	// Subscribe 学校订阅标准商品到本校商品库
+	Subscribe(req *model.SubscribeMasterProductsRequest, claims *jwt.CustomClaims) (*model.PushResult, error)
+}
+
+// masterProductService 实现了 IMasterProductService 接口
+type masterProductService struct {
+	masterRepo      repository.IMasterProductRepository
+	productRepo     repository.IProductRepository
+	orgRepo         repository.IOrganizationRepository
+	categoryService ICategoryService
+	searchService   IProductSearchService
+}
+
+// NewMasterProductService 创建一个新的 masterProductService 实例
+func NewMasterProductService(masterRepo repository.IMasterProductRepository, productRepo repository.IProductRepository, orgRepo repository.IOrganizationRepository, categoryService ICategoryService, searchService IProductSearchService) IMasterProductService {
+	return &masterProductService{
+		masterRepo:      masterRepo,
+		productRepo:     productRepo,
+		orgRepo:         orgRepo,
+		categoryService: categoryService,
+		searchService:   searchService,
+	}
+}
+
+// CreateMaster 新建标准商品
+func (s *masterProductService) CreateMaster(req *model.MasterProductRequest) (*model.ScmMasterProduct, error) {
+	master := &model.ScmMasterProduct{IsEnabled: true}
+	if err := s.fill(master, req); err != nil {
+		return nil, err
+	}
+	if err := s.masterRepo.Create(master); err != nil {
+		return nil, fmt.Errorf("创建标准商品失败: %w", err)
+	}
+	return master, nil
+}
+
+// UpdateMaster 修改标准商品并同步到学校副本
+func (s *masterProductService) UpdateMaster(id uint, req *model.MasterProductRequest) (*model.MasterSyncResult, error) {
+	master, err := s.getMaster(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.fill(master, req); err != nil {
+		return nil, err
+	}
+
+	// 标准商品和所有副本在同一事务中更新
+	err = s.masterRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := repository.NewMasterProductRepository(tx).Update(master); err != nil {
+			return fmt.Errorf("更新标准商品失败: %w", err)
+		}
+		return repository.NewProductRepository(tx).SyncMasterCopies(master)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	copies, err := s.productRepo.ListMasterCopies(master.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(copies))
+	for i := range copies {
+		ids[i] = copies[i].ID
+	}
+	if err := s.searchService.Refresh(ids...); err != nil {
+		log.Printf("同步商品搜索索引失败: %v", err)
+	}
+	return &model.MasterSyncResult{Master: master, Synced: int64(len(copies))}, nil
+}
+
+// SetMasterEnabled 启用或停用标准商品
+func (s *masterProductService) SetMasterEnabled(id uint, enabled bool) error {
+	master, err := s.getMaster(id)
+	if err != nil {
+		return err
+	}
+	master.IsEnabled = enabled
+	return s.masterRepo.Update(master)
+}
+
+// ListMasters 列出标准商品
+func (s *masterProductService) ListMasters(filter model.MasterProductListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.ScmMasterProduct, int64, error) {
+	if !isPlatformRole(claims.Role) {
+		enabled := true
+		filter.IsEnabled = &enabled
+	}
+	return s.masterRepo.List(filter, page, pageSize)
+}
+
+// ListCopies 列出标准商品在各学校的副本
+func (s *masterProductService) ListCopies(id uint) ([]model.ScmProduct, error) {
+	if _, err := s.getMaster(id); err != nil {
+		return nil, err
+	}
+	return s.productRepo.ListMasterCopies(id)
+}
+
+// Push 平台将标准商品下发到指定学校
+func (s *masterProductService) Push(req *model.PushMasterProductsRequest, claims *jwt.CustomClaims) (*model.PushResult, error) {
+	for _, schoolID := range req.SchoolIDs {
+		org, err := s.orgRepo.GetByID(schoolID)
+		if err != nil || org.OrgType != int8(model.OrgTypeSchool) {
+			return nil, fmt.Errorf("学校 [%d] 不存在", schoolID)
+		}
+	}
+	return s.push(req.MasterIDs, req.SchoolIDs, claims)
+}
+
+// Subscribe 学校订阅标准商品
+func (s *masterProductService) Subscribe(req *model.SubscribeMasterProductsRequest, claims *jwt.CustomClaims) (*model.PushResult, error) {
+	return s.push(req.MasterIDs, []uint{claims.OrgID}, claims)
+}
+
+// push 为每个学校生成尚未拥有的标准商品副本。
+// 副本直接视为审核通过；规格在第一个供应商报价后锁定，此前标准商品的规格修正仍会同步到副本。
+func (s *masterProductService) push(masterIDs, schoolIDs []uint, claims *jwt.CustomClaims) (*model.PushResult, error) {
+	masters, err := s.masterRepo.ListByIDs(masterIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(masters) != len(uniqueIDs(masterIDs)) {
+		return nil, errors.New("部分标准商品不存在")
+	}
+	for _, m := range masters {
+		if !m.IsEnabled {
+			return nil, fmt.Errorf("标准商品 [%s] 已停用，不能下发", m.Name)
+		}
+	}
+
+	result := &model.PushResult{}
+	var createdIDs []uint
+	err = s.productRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewProductRepository(tx)
+		for _, schoolID := range uniqueIDs(schoolIDs) {
+			existing, err := txRepo.ExistingMasterCopies(schoolID, masterIDs)
+			if err != nil {
+				return err
+			}
+			for _, m := range masters {
+				if existing[m.ID] {
+					result.Skipped++
+					continue
+				}
+				product := &model.ScmProduct{
+					SchoolID:    schoolID,
+					CategoryID:  m.CategoryID,
+					Name:        m.Name,
+					Image:       m.Image,
+					Specs:       m.Specs,
+					Unit:        m.Unit,
+					SourceType:  model.ProductSourcePlatform,
+					CreatorID:   claims.OrgID,
+					StaticCerts: m.StaticCerts,
+					AuditStatus: model.ProductAuditApproved,
+					MasterID:    m.ID,
+				}
+				if err := txRepo.Create(product); err != nil {
+					return fmt.Errorf("下发标准商品失败: %w", err)
+				}
+				createdIDs = append(createdIDs, product.ID)
+				result.Created++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.searchService.Refresh(createdIDs...); err != nil {
+		log.Printf("同步商品搜索索引失败: %v", err)
+	}
+	return result, nil
+}
+
+// fill 校验请求并填充标准商品字段
+func (s *masterProductService) fill(master *model.ScmMasterProduct, req *model.MasterProductRequest) error {
+	if err := s.categoryService.EnsureLeafCategory(req.CategoryID); err != nil {
+		return err
+	}
+	certs := req.Certs
+	if certs == nil {
+		certs = []string{}
+	}
+	certsJSON, err := json.Marshal(certs)
+	if err != nil {
+		return err
+	}
+
+	master.CategoryID = req.CategoryID
+	master.Name = req.Name
+	master.Image = req.Image
+	master.Specs = req.Specs
+	master.Unit = req.Unit
+	master.StaticCerts = string(certsJSON)
+	return nil
+}
+
+// getMaster 获取标准商品，不存在时返回友好的错误信息
+func (s *masterProductService) getMaster(id uint) (*model.ScmMasterProduct, error) {
+	master, err := s.masterRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("标准商品不存在")
+		}
+		return nil, err
+	}
+	return master, nil
+}
+
+// uniqueIDs 去除重复的ID并保持原有顺序
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
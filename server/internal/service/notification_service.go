@@ -0,0 +1,67 @@
+// server/internal/service/notification_service.go
+package service
+
+import (
+	"errors"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+)
+
+// INotificationService 定义站内通知服务接口，通知发给组织，组织内的用户共享已读状态
+type INotificationService interface {
+	// Notify 向组织发送一条通知
+	Notify(orgID uint, category, title, content string, refID uint) error
+	// List 分页列出当前组织的通知，并返回未读数
+	List(claims *jwt.CustomClaims, unreadOnly bool, page, pageSize int) ([]model.SysNotification, int64, int64, error)
+	MarkRead(id uint, claims *jwt.CustomClaims) error
+	MarkAllRead(claims *jwt.CustomClaims) error
+}
+
+// notificationService 实现了 INotificationService 接口
+type notificationService struct {
+	notificationRepo repository.INotificationRepository
+}
+
+// NewNotificationService 创建一个新的 notificationService 实例
+func NewNotificationService(notificationRepo repository.INotificationRepository) INotificationService {
+	return &notificationService{notificationRepo: notificationRepo}
+}
+
+// Notify 发送通知
+func (s *notificationService) Notify(orgID uint, category, title, content string, refID uint) error {
+	return s.notificationRepo.Create(&model.SysNotification{
+		OrgID:    orgID,
+		Category: category,
+		Title:    title,
+		Content:  content,
+		RefID:    refID,
+	})
+}
+
+// List 列出通知
+func (s *notificationService) List(claims *jwt.CustomClaims, unreadOnly bool, page, pageSize int) ([]model.SysNotification, int64, int64, error) {
+	notifications, total, err := s.notificationRepo.List(claims.OrgID, unreadOnly, page, pageSize)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	unread, err := s.notificationRepo.CountUnread(claims.OrgID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return notifications, total, unread, nil
+}
+
+// MarkRead 将一条通知标记为已读，已读或不属于本组织的通知不做处理
+func (s *notificationService) MarkRead(id uint, claims *jwt.CustomClaims) error {
+	return s.notificationRepo.MarkRead(claims.OrgID, id)
+}
+
+// MarkAllRead 将全部通知标记为已读
+func (s *notificationService) MarkAllRead(claims *jwt.CustomClaims) error {
+	if claims.OrgID == 0 {
+		return errors.New("当前用户不属于任何组织")
+	}
+	return s.notificationRepo.MarkAllRead(claims.OrgID)
+}
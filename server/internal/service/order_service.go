@@ -0,0 +1,593 @@
+// server/internal/service/order_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// IOrderService 定义订单服务接口
+type IOrderService interface {
+	// Checkout 结算购物车：按供应商拆分为多个订单并写入交易快照，同一结算令牌重复提交时返回首次结算的结果
+	Checkout(req *model.CheckoutRequest, claims *jwt.CustomClaims) (*model.CheckoutResult, error)
+	// CheckoutRows 不经过购物车，直接结算给定的条目并以系统身份记录下单，供定期订单等后台任务使用。
+	// 校验规则与结算购物车相同，同一结算令牌重复提交时返回首次结算的结果。
+	CheckoutRows(merchantID, operatorID uint, token string, rows []model.CartLineRow, deliveryDate time.Time, remark string) (*model.CheckoutResult, error)
+	// GetOrder 获取订单详情，附带明细和状态变更记录
+	GetOrder(id uint, claims *jwt.CustomClaims) (*model.OrderDetail, error)
+	// ChangeStatus 按订单状态机接单、拒单或取消订单，其余状态须通过分拣、配送和验收流程变更
+	ChangeStatus(id uint, req *model.UpdateOrderStatusRequest, claims *jwt.CustomClaims) (*model.OrdOrder, error)
+	// Inbox 分页列出供应商收到的订单，附带明细、买家名称以及各状态的订单数
+	Inbox(filter model.OrderListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdOrder, int64, []model.OrderStatusCount, error)
+	// Accept 供应商接单
+	Accept(id uint, req *model.AcceptOrderRequest, claims *jwt.CustomClaims) (*model.OrdOrder, error)
+	// Reject 供应商拒单，须填写原因
+	Reject(id uint, req *model.RejectOrderRequest, claims *jwt.CustomClaims) (*model.OrdOrder, error)
+	// AdjustItems 供应商将订单明细标记为缺货或少发，并重新计算订单总金额
+	AdjustItems(id uint, req *model.AdjustOrderItemsRequest, claims *jwt.CustomClaims) (*model.OrdOrder, error)
+}
+
+// orderService 实现了 IOrderService 接口
+type orderService struct {
+	orderRepo       repository.IOrderRepository
+	orgRepo         repository.IOrganizationRepository
+	categoryService ICategoryService
+	fileService     IFileService
+}
+
+// NewOrderService 创建一个新的 orderService 实例
+func NewOrderService(orderRepo repository.IOrderRepository, orgRepo repository.IOrganizationRepository, categoryService ICategoryService, fileService IFileService) IOrderService {
+	return &orderService{
+		orderRepo:       orderRepo,
+		orgRepo:         orgRepo,
+		categoryService: categoryService,
+		fileService:     fileService,
+	}
+}
+
+// Checkout 结算购物车
+func (s *orderService) Checkout(req *model.CheckoutRequest, claims *jwt.CustomClaims) (*model.CheckoutResult, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, errors.New("仅食堂和商户可以下单")
+	}
+	schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	deliveryDate, err := checkoutDeliveryDate(req.DeliveryDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 已用该令牌结算过，直接返回首次结算的结果
+	if result, err := s.existingCheckout(claims.OrgID, req.Token); result != nil || err != nil {
+		return result, err
+	}
+
+	var checkout *model.OrdCheckout
+	err = s.orderRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		orderRepo := repository.NewOrderRepository(tx)
+		cartRepo := repository.NewCartRepository(tx)
+
+		// 2. 先占用结算令牌，并发的重复提交会因唯一索引冲突而失败
+		checkout = &model.OrdCheckout{MerchantID: claims.OrgID, Token: req.Token, OperatorID: claims.UserID}
+		if err := orderRepo.CreateCheckout(checkout); err != nil {
+			return err
+		}
+
+		// 3. 锁定购物车后读取实时报价，校验待结算的条目
+		if _, err := cartRepo.LockByMerchant(claims.OrgID); err != nil {
+			return err
+		}
+		rows, err := cartRepo.ListLinesByMerchant(claims.OrgID)
+		if err != nil {
+			return err
+		}
+		rows, err = selectCheckoutRows(rows, req.ItemIDs)
+		if err != nil {
+			return err
+		}
+
+		// 4. 按供应商拆分订单并写入明细快照
+		if err := s.submitCheckout(orderRepo, checkout, rows, schoolID, deliveryDate, model.OrdOrderLog{
+			Actor:         model.OrderActorBuyer,
+			OperatorID:    claims.UserID,
+			OperatorOrgID: claims.OrgID,
+			Remark:        "提交订单",
+		}); err != nil {
+			return err
+		}
+
+		// 5. 从购物车中移除已结算的条目
+		ids := make([]uint, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+		return cartRepo.DeleteByIDs(claims.OrgID, ids)
+	})
+	if err != nil {
+		// 并发的重复提交：另一请求已用该令牌完成结算
+		if result, lookupErr := s.existingCheckout(claims.OrgID, req.Token); result != nil && lookupErr == nil {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	return s.checkoutResult(checkout, false)
+}
+
+// CheckoutRows 直接结算给定的条目，由系统代买家下单
+func (s *orderService) CheckoutRows(merchantID, operatorID uint, token string, rows []model.CartLineRow, deliveryDate time.Time, remark string) (*model.CheckoutResult, error) {
+	schoolID, err := schoolIDOf(s.orgRepo, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	if result, err := s.existingCheckout(merchantID, token); result != nil || err != nil {
+		return result, err
+	}
+
+	var checkout *model.OrdCheckout
+	err = s.orderRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		orderRepo := repository.NewOrderRepository(tx)
+		checkout = &model.OrdCheckout{MerchantID: merchantID, Token: token, OperatorID: operatorID}
+		if err := orderRepo.CreateCheckout(checkout); err != nil {
+			return err
+		}
+		return s.submitCheckout(orderRepo, checkout, rows, schoolID, deliveryDate, model.OrdOrderLog{
+			Actor:         model.OrderActorSystem,
+			OperatorID:    operatorID,
+			OperatorOrgID: merchantID,
+			Remark:        remark,
+		})
+	})
+	if err != nil {
+		if result, lookupErr := s.existingCheckout(merchantID, token); result != nil && lookupErr == nil {
+			return result, nil
+		}
+		return nil, err
+	}
+	return s.checkoutResult(checkout, false)
+}
+
+// submitCheckout 校验待结算的条目，按供应商拆分订单并以 logTemplate 为模板写入下单记录，最后更新结算批次的汇总。
+// 调用方负责开启事务并预先占用结算令牌。
+func (s *orderService) submitCheckout(orderRepo repository.IOrderRepository, checkout *model.OrdCheckout, rows []model.CartLineRow, schoolID uint, deliveryDate time.Time, logTemplate model.OrdOrderLog) error {
+	if len(rows) == 0 {
+		return errors.New("没有可结算的商品")
+	}
+	if err := s.checkCheckoutRows(rows, schoolID); err != nil {
+		return err
+	}
+	orders, err := createSupplierOrders(orderRepo, checkout, rows, deliveryDate)
+	if err != nil {
+		return err
+	}
+	for _, order := range orders {
+		checkout.TotalAmount = roundMoney(checkout.TotalAmount + order.TotalAmount)
+		log := logTemplate
+		log.OrderID = order.ID
+		log.ToStatus = order.Status
+		if err := orderRepo.CreateLog(&log); err != nil {
+			return err
+		}
+	}
+	checkout.OrderCount = len(orders)
+	return orderRepo.UpdateCheckout(checkout)
+}
+
+// GetOrder 获取订单详情
+func (s *orderService) GetOrder(id uint, claims *jwt.CustomClaims) (*model.OrderDetail, error) {
+	order, err := getVisibleOrder(s.orderRepo, s.orgRepo, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	orders := []model.OrdOrder{*order}
+	if err := fillOrderItems(s.orderRepo, orders); err != nil {
+		return nil, err
+	}
+	logs, err := s.orderRepo.ListLogs(id)
+	if err != nil {
+		return nil, err
+	}
+	s.fileService.FillOrderVouchers(orders)
+	return &model.OrderDetail{Order: &orders[0], Logs: logs}, nil
+}
+
+// ChangeStatus 变更订单状态
+func (s *orderService) ChangeStatus(id uint, req *model.UpdateOrderStatusRequest, claims *jwt.CustomClaims) (*model.OrdOrder, error) {
+	if !manualOrderStatuses[req.Status] {
+		return nil, fmt.Errorf("订单不能直接变更为 [%s]，请通过分拣、配送或验收流程操作", orderStatusLabel(req.Status))
+	}
+	actor, err := orderActorOf(claims.Role)
+	if err != nil {
+		return nil, err
+	}
+	order, err := getVisibleOrder(s.orderRepo, s.orgRepo, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	err = s.orderRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		return transitionOrder(repository.NewOrderRepository(tx), order, req.Status, actor, claims, req.Remark)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Inbox 分页列出供应商收到的订单
+func (s *orderService) Inbox(filter model.OrderListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdOrder, int64, []model.OrderStatusCount, error) {
+	if !isSupplierRole(claims.Role) {
+		return nil, 0, nil, errors.New("仅供应商可以查看订单收件箱")
+	}
+	filter.SupplierID = claims.OrgID
+	filter.MerchantIDs = nil
+
+	orders, total, err := s.orderRepo.List(filter, page, pageSize)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	counts, err := s.orderRepo.CountByStatus(filter)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	if err := fillOrderItems(s.orderRepo, orders); err != nil {
+		return nil, 0, nil, err
+	}
+	if err := fillOrderMerchantNames(s.orgRepo, orders); err != nil {
+		return nil, 0, nil, err
+	}
+	s.fileService.FillOrderVouchers(orders)
+	return orders, total, counts, nil
+}
+
+// Accept 供应商接单
+func (s *orderService) Accept(id uint, req *model.AcceptOrderRequest, claims *jwt.CustomClaims) (*model.OrdOrder, error) {
+	return s.ChangeStatus(id, &model.UpdateOrderStatusRequest{Status: model.OrderStatusAccepted, Remark: req.Remark}, claims)
+}
+
+// Reject 供应商拒单
+func (s *orderService) Reject(id uint, req *model.RejectOrderRequest, claims *jwt.CustomClaims) (*model.OrdOrder, error) {
+	return s.ChangeStatus(id, &model.UpdateOrderStatusRequest{Status: model.OrderStatusRejected, Remark: req.Reason}, claims)
+}
+
+// AdjustItems 标记订单明细缺货或少发。只有尚未发货的订单（待接单、已接单、分拣中）可以调整。
+// 订单行在事务中加锁后重新读取明细，避免与生成分拣单并发时调整已进入分拣的明细。
+func (s *orderService) AdjustItems(id uint, req *model.AdjustOrderItemsRequest, claims *jwt.CustomClaims) (*model.OrdOrder, error) {
+	if !isSupplierRole(claims.Role) {
+		return nil, errors.New("仅供应商可以调整订单明细")
+	}
+	if _, err := getVisibleOrder(s.orderRepo, s.orgRepo, id, claims); err != nil {
+		return nil, err
+	}
+
+	var order *model.OrdOrder
+	err := s.orderRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		orderRepo := repository.NewOrderRepository(tx)
+		locked, err := orderRepo.Lock(id)
+		if err != nil {
+			return err
+		}
+		switch locked.Status {
+		case model.OrderStatusPending, model.OrderStatusAccepted, model.OrderStatusPicking:
+		default:
+			return fmt.Errorf("订单处于 [%s] 状态，不能调整明细", orderStatusLabel(locked.Status))
+		}
+
+		orders := []model.OrdOrder{*locked}
+		if err := fillOrderItems(orderRepo, orders); err != nil {
+			return err
+		}
+		order = &orders[0]
+		byID := make(map[uint]*model.OrdOrderItem, len(order.Items))
+		for i := range order.Items {
+			byID[order.Items[i].ID] = &order.Items[i]
+		}
+
+		// 1. 按标记调整明细的数量和金额
+		changed := make(map[uint]bool, len(req.Items))
+		for _, adj := range req.Items {
+			item, ok := byID[adj.ItemID]
+			if !ok {
+				return fmt.Errorf("订单明细 #%d 不存在", adj.ItemID)
+			}
+			if item.PickLineID != 0 {
+				return fmt.Errorf("商品 [%s] 已生成分拣单，不能再调整", item.SnapName)
+			}
+			if err := adjustOrderItem(item, adj); err != nil {
+				return err
+			}
+			changed[item.ID] = true
+		}
+
+		// 2. 重新计算订单总金额，全部缺货的订单应直接拒单
+		total := 0.0
+		for _, item := range order.Items {
+			total = roundMoney(total + item.Amount)
+		}
+		if total == 0 {
+			return errors.New("订单全部商品缺货，请直接拒单")
+		}
+
+		for i := range order.Items {
+			if !changed[order.Items[i].ID] {
+				continue
+			}
+			if err := orderRepo.UpdateItem(&order.Items[i]); err != nil {
+				return fmt.Errorf("更新订单明细失败: %w", err)
+			}
+		}
+		if _, err := orderRepo.UpdateTotal(order.ID, order.Status, total); err != nil {
+			return fmt.Errorf("更新订单金额失败: %w", err)
+		}
+		order.TotalAmount = total
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// fillOrderMerchantNames 为订单填充买家名称
+func fillOrderMerchantNames(orgRepo repository.IOrganizationRepository, orders []model.OrdOrder) error {
+	ids := make([]uint, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.MerchantID)
+	}
+	orgs, err := orgRepo.ListByIDs(ids)
+	if err != nil {
+		return err
+	}
+	names := make(map[uint]string, len(orgs))
+	for _, org := range orgs {
+		names[org.ID] = org.Name
+	}
+	for i := range orders {
+		orders[i].MerchantName = names[orders[i].MerchantID]
+	}
+	return nil
+}
+
+// getVisibleOrder 获取当前用户有权查看的订单：买家和供应商只能查看自己的订单，学校可以查看本校买家的订单，平台不做限制
+func getVisibleOrder(orderRepo repository.IOrderRepository, orgRepo repository.IOrganizationRepository, id uint, claims *jwt.CustomClaims) (*model.OrdOrder, error) {
+	order, err := orderRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("订单不存在")
+		}
+		return nil, err
+	}
+
+	switch {
+	case isPlatformRole(claims.Role):
+		return order, nil
+	case isBuyerRole(claims.Role):
+		if order.MerchantID == claims.OrgID {
+			return order, nil
+		}
+	case isSupplierRole(claims.Role):
+		if order.SupplierID == claims.OrgID {
+			return order, nil
+		}
+	case isSchoolRole(claims.Role):
+		buyerIDs, err := buyerOrgIDsOfSchool(orgRepo, claims.OrgID)
+		if err != nil {
+			return nil, err
+		}
+		for _, buyerID := range buyerIDs {
+			if buyerID == order.MerchantID {
+				return order, nil
+			}
+		}
+	}
+	return nil, errors.New("无权查看此订单")
+}
+
+// existingCheckout 查找买家已用该令牌完成的结算，未结算过时返回 nil
+func (s *orderService) existingCheckout(merchantID uint, token string) (*model.CheckoutResult, error) {
+	checkout, err := s.orderRepo.GetCheckoutByToken(merchantID, token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.checkoutResult(checkout, true)
+}
+
+// checkoutResult 组装结算结果，附带各订单的明细
+func (s *orderService) checkoutResult(checkout *model.OrdCheckout, duplicate bool) (*model.CheckoutResult, error) {
+	orders, err := s.orderRepo.ListByCheckout(checkout.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillOrderItems(s.orderRepo, orders); err != nil {
+		return nil, err
+	}
+	return &model.CheckoutResult{
+		CheckoutID:  checkout.ID,
+		Token:       checkout.Token,
+		TotalAmount: checkout.TotalAmount,
+		Orders:      orders,
+		Duplicate:   duplicate,
+	}, nil
+}
+
+// fillOrderItems 为订单填充明细
+func fillOrderItems(orderRepo repository.IOrderRepository, orders []model.OrdOrder) error {
+	orderIDs := make([]uint, 0, len(orders))
+	for _, order := range orders {
+		orderIDs = append(orderIDs, order.ID)
+	}
+	items, err := orderRepo.ListItemsByOrders(orderIDs)
+	if err != nil {
+		return err
+	}
+	byOrder := make(map[uint][]model.OrdOrderItem, len(orders))
+	for _, item := range items {
+		byOrder[item.OrderID] = append(byOrder[item.OrderID], item)
+	}
+	for i := range orders {
+		orders[i].Items = byOrder[orders[i].ID]
+		if orders[i].Items == nil {
+			orders[i].Items = []model.OrdOrderItem{}
+		}
+	}
+	return nil
+}
+
+// checkCheckoutRows 校验待结算的条目都可以下单，且价格变化已由买家确认
+func (s *orderService) checkCheckoutRows(rows []model.CartLineRow, schoolID uint) error {
+	policies := make(map[uint]*model.ReportPolicy)
+	now := time.Now()
+	for _, row := range rows {
+		policy, err := lineReportPolicy(s.categoryService, policies, row)
+		if err != nil {
+			return err
+		}
+		line := buildCartLine(row, schoolID, policy, now)
+		name := row.ProductName
+		if name == "" {
+			name = fmt.Sprintf("#%d", row.ID)
+		}
+		if !line.Orderable {
+			return fmt.Errorf("商品 [%s] 无法下单: %s", name, line.Reason)
+		}
+		if line.PriceChanged {
+			return fmt.Errorf("商品 [%s] 价格已由 %.2f 变为 %.2f，请确认后再提交", name, row.AddedPrice, row.Price)
+		}
+	}
+	return nil
+}
+
+// checkoutDeliveryDate 解析期望送达日期，为空时默认次日，不能早于今天
+func checkoutDeliveryDate(value string) (time.Time, error) {
+	today := startOfDay(time.Now())
+	if value == "" {
+		return today.AddDate(0, 0, 1), nil
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("无效的送达日期")
+	}
+	if date.Before(today) {
+		return time.Time{}, errors.New("送达日期不能早于今天")
+	}
+	return date, nil
+}
+
+// adjustOrderItem 按缺货/少发标记调整明细的数量和金额
+func adjustOrderItem(item *model.OrdOrderItem, adj model.AdjustOrderItem) error {
+	ordered := item.OrderedQty
+	if ordered == 0 {
+		ordered = item.Quantity
+	}
+	quantity := ordered
+	switch *adj.FulfilStatus {
+	case model.OrderItemFulfilOutOfStock:
+		quantity = 0
+	case model.OrderItemFulfilShort:
+		if adj.Quantity <= 0 || adj.Quantity >= ordered {
+			return fmt.Errorf("商品 [%s] 少发数量须大于 0 且小于下单数量 %d", item.SnapName, ordered)
+		}
+		quantity = adj.Quantity
+	}
+
+	item.OrderedQty = ordered
+	item.Quantity = quantity
+	item.FulfilStatus = *adj.FulfilStatus
+	item.FulfilRemark = adj.Remark
+	item.Amount = roundMoney(item.SnapPrice * float64(quantity))
+	return nil
+}
+
+// selectCheckoutRows 按条目ID挑选待结算的购物车条目，itemIDs 为空时结算整个购物车
+func selectCheckoutRows(rows []model.CartLineRow, itemIDs []uint) ([]model.CartLineRow, error) {
+	if len(itemIDs) == 0 {
+		if len(rows) == 0 {
+			return nil, errors.New("购物车为空")
+		}
+		return rows, nil
+	}
+
+	byID := make(map[uint]model.CartLineRow, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = row
+	}
+	selected := make([]model.CartLineRow, 0, len(itemIDs))
+	seen := make(map[uint]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		row, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("购物车条目 #%d 不存在", id)
+		}
+		selected = append(selected, row)
+	}
+	return selected, nil
+}
+
+// createSupplierOrders 按供应商拆分条目，为每个供应商创建一个订单及其明细快照。
+// 订单号由下单日期、结算批次ID和批次内序号组成，保证全局唯一。
+func createSupplierOrders(orderRepo repository.IOrderRepository, checkout *model.OrdCheckout, rows []model.CartLineRow, deliveryDate time.Time) ([]*model.OrdOrder, error) {
+	var supplierIDs []uint
+	bySupplier := make(map[uint][]model.CartLineRow)
+	for _, row := range rows {
+		if _, ok := bySupplier[row.SupplierID]; !ok {
+			supplierIDs = append(supplierIDs, row.SupplierID)
+		}
+		bySupplier[row.SupplierID] = append(bySupplier[row.SupplierID], row)
+	}
+
+	date := time.Now().Format("20060102")
+	orders := make([]*model.OrdOrder, 0, len(supplierIDs))
+	for i, supplierID := range supplierIDs {
+		order := &model.OrdOrder{
+			OrderNo:         fmt.Sprintf("%s%010d%03d", date, checkout.ID, i+1),
+			CheckoutID:      checkout.ID,
+			MerchantID:      checkout.MerchantID,
+			SupplierID:      supplierID,
+			Status:          model.OrderStatusPending,
+			ReceiptVouchers: "[]",
+			DeliveryDate:    &deliveryDate,
+		}
+		items := make([]model.OrdOrderItem, 0, len(bySupplier[supplierID]))
+		for _, row := range bySupplier[supplierID] {
+			amount := roundMoney(row.Price * float64(row.Quantity))
+			items = append(items, model.OrdOrderItem{
+				ProductID:  row.ProductID,
+				QuoteID:    row.QuoteID,
+				SnapName:   row.ProductName,
+				SnapSpecs:  row.Specs,
+				SnapPrice:  row.Price,
+				Quantity:   row.Quantity,
+				Amount:     amount,
+				OrderedQty: row.Quantity,
+			})
+			order.TotalAmount = roundMoney(order.TotalAmount + amount)
+		}
+
+		if err := orderRepo.CreateOrder(order); err != nil {
+			return nil, fmt.Errorf("创建订单失败: %w", err)
+		}
+		for j := range items {
+			items[j].OrderID = order.ID
+		}
+		if err := orderRepo.CreateItems(items); err != nil {
+			return nil, fmt.Errorf("创建订单明细失败: %w", err)
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
@@ -0,0 +1,85 @@
+// server/internal/service/order_service_test.go
+package service
+
+import (
+	"testing"
+
+	"server/internal/model"
+)
+
+func TestAdjustOrderItem(t *testing.T) {
+	status := func(s int8) *int8 { return &s }
+	tests := []struct {
+		name       string
+		item       model.OrdOrderItem
+		adj        model.AdjustOrderItem
+		wantErr    bool
+		wantQty    int
+		wantOrder  int
+		wantAmount float64
+	}{
+		{
+			name:       "缺货",
+			item:       model.OrdOrderItem{Quantity: 10, SnapPrice: 2.5},
+			adj:        model.AdjustOrderItem{FulfilStatus: status(model.OrderItemFulfilOutOfStock)},
+			wantQty:    0,
+			wantOrder:  10,
+			wantAmount: 0,
+		},
+		{
+			name:       "少发",
+			item:       model.OrdOrderItem{Quantity: 10, SnapPrice: 2.5},
+			adj:        model.AdjustOrderItem{FulfilStatus: status(model.OrderItemFulfilShort), Quantity: 4},
+			wantQty:    4,
+			wantOrder:  10,
+			wantAmount: 10,
+		},
+		{
+			name:       "再次调整按下单数量计算",
+			item:       model.OrdOrderItem{Quantity: 4, OrderedQty: 10, SnapPrice: 1.15},
+			adj:        model.AdjustOrderItem{FulfilStatus: status(model.OrderItemFulfilShort), Quantity: 7},
+			wantQty:    7,
+			wantOrder:  10,
+			wantAmount: 8.05,
+		},
+		{
+			name:       "恢复正常",
+			item:       model.OrdOrderItem{Quantity: 0, OrderedQty: 10, SnapPrice: 2.5, FulfilStatus: model.OrderItemFulfilOutOfStock},
+			adj:        model.AdjustOrderItem{FulfilStatus: status(model.OrderItemFulfilNormal)},
+			wantQty:    10,
+			wantOrder:  10,
+			wantAmount: 25,
+		},
+		{
+			name:    "少发数量为 0",
+			item:    model.OrdOrderItem{Quantity: 10, SnapPrice: 2.5},
+			adj:     model.AdjustOrderItem{FulfilStatus: status(model.OrderItemFulfilShort), Quantity: 0},
+			wantErr: true,
+		},
+		{
+			name:    "少发数量不小于下单数量",
+			item:    model.OrdOrderItem{Quantity: 10, SnapPrice: 2.5},
+			adj:     model.AdjustOrderItem{FulfilStatus: status(model.OrderItemFulfilShort), Quantity: 10},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := tt.item
+			err := adjustOrderItem(&item, tt.adj)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("adjustOrderItem error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if item.Quantity != tt.wantQty || item.OrderedQty != tt.wantOrder || item.Amount != tt.wantAmount {
+				t.Errorf("got quantity %d ordered %d amount %v, want %d %d %v",
+					item.Quantity, item.OrderedQty, item.Amount, tt.wantQty, tt.wantOrder, tt.wantAmount)
+			}
+			if item.FulfilStatus != *tt.adj.FulfilStatus {
+				t.Errorf("FulfilStatus = %d, want %d", item.FulfilStatus, *tt.adj.FulfilStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// server/internal/service/order_state.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+)
+
+// orderTransitions 定义了订单生命周期中允许的状态变更，以及每种变更允许的操作方
+var orderTransitions = map[int8]map[int8][]string{
+	model.OrderStatusPending: {
+		model.OrderStatusAccepted:  {model.OrderActorSupplier},
+		model.OrderStatusRejected:  {model.OrderActorSupplier},
+		model.OrderStatusCancelled: {model.OrderActorBuyer, model.OrderActorPlatform},
+	},
+	model.OrderStatusAccepted: {
+		model.OrderStatusPicking:   {model.OrderActorSupplier},
+		model.OrderStatusCancelled: {model.OrderActorSupplier, model.OrderActorPlatform},
+	},
+	model.OrderStatusPicking: {
+		model.OrderStatusDelivering: {model.OrderActorSupplier},
+		model.OrderStatusCancelled:  {model.OrderActorPlatform},
+	},
+	model.OrderStatusDelivering: {
+		model.OrderStatusDelivered: {model.OrderActorSupplier},
+	},
+	model.OrderStatusDelivered: {
+		model.OrderStatusReceived: {model.OrderActorBuyer},
+	},
+	model.OrderStatusReceived: {
+		model.OrderStatusCompleted: {model.OrderActorBuyer, model.OrderActorPlatform, model.OrderActorSystem},
+	},
+}
+
+// manualOrderStatuses 可以通过通用状态接口直接变更到的状态。
+// 分拣、配送、送达和验收须经过各自的业务流程，以便同时记录分拣单、配送单和验收结果
+var manualOrderStatuses = map[int8]bool{
+	model.OrderStatusAccepted:  true,
+	model.OrderStatusRejected:  true,
+	model.OrderStatusCancelled: true,
+}
+
+// orderActorLabels 订单操作方的中文名称
+var orderActorLabels = map[string]string{
+	model.OrderActorBuyer:    "买家",
+	model.OrderActorSupplier: "供应商",
+	model.OrderActorPlatform: "平台",
+	model.OrderActorSystem:   "系统",
+}
+
+// orderActorOf 根据角色确定订单操作方，学校只能查看订单，不能变更订单状态
+func orderActorOf(role string) (string, error) {
+	switch {
+	case isBuyerRole(role):
+		return model.OrderActorBuyer, nil
+	case isSupplierRole(role):
+		return model.OrderActorSupplier, nil
+	case isPlatformRole(role):
+		return model.OrderActorPlatform, nil
+	}
+	return "", errors.New("当前角色无权变更订单状态")
+}
+
+// orderStatusLabel 返回订单状态的中文名称
+func orderStatusLabel(status int8) string {
+	if label, ok := model.OrderStatusLabels[status]; ok {
+		return label
+	}
+	return fmt.Sprintf("未知状态(%d)", status)
+}
+
+// checkOrderTransition 校验操作方能否将订单从 from 状态变更为 to 状态
+func checkOrderTransition(from, to int8, actor string) error {
+	if _, ok := model.OrderStatusLabels[to]; !ok {
+		return fmt.Errorf("未知的订单状态 %d", to)
+	}
+	if from == to {
+		return fmt.Errorf("订单已处于 [%s] 状态", orderStatusLabel(from))
+	}
+	actors, ok := orderTransitions[from][to]
+	if !ok {
+		return fmt.Errorf("订单不能从 [%s] 变更为 [%s]", orderStatusLabel(from), orderStatusLabel(to))
+	}
+	for _, a := range actors {
+		if a == actor {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s无权将订单从 [%s] 变更为 [%s]", orderActorLabels[actor], orderStatusLabel(from), orderStatusLabel(to))
+}
+
+// transitionOrder 按状态机变更订单状态并记录变更日志，调用方负责开启事务。
+// 变更以订单当前状态为条件，并发变更同一订单时只有一个会成功。
+func transitionOrder(orderRepo repository.IOrderRepository, order *model.OrdOrder, to int8, actor string, claims *jwt.CustomClaims, remark string) error {
+	from := order.Status
+	if err := checkOrderTransition(from, to, actor); err != nil {
+		return err
+	}
+	if (to == model.OrderStatusRejected || to == model.OrderStatusCancelled) && remark == "" {
+		return fmt.Errorf("%s需填写原因", orderStatusLabel(to))
+	}
+
+	now := time.Now()
+	fields := map[string]interface{}{}
+	switch to {
+	case model.OrderStatusDelivering:
+		fields["delivery_time"] = now
+		order.DeliveryTime = &now
+	case model.OrderStatusDelivered:
+		fields["arrival_time"] = now
+		order.ArrivalTime = &now
+	}
+	affected, err := orderRepo.UpdateStatus(order.ID, from, to, fields)
+	if err != nil {
+		return fmt.Errorf("更新订单状态失败: %w", err)
+	}
+	if affected == 0 {
+		return errors.New("订单状态已变化，请刷新后重试")
+	}
+	order.Status = to
+
+	log := &model.OrdOrderLog{
+		OrderID:    order.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		Remark:     remark,
+	}
+	if claims != nil {
+		log.OperatorID = claims.UserID
+		log.OperatorOrgID = claims.OrgID
+	}
+	return orderRepo.CreateLog(log)
+}
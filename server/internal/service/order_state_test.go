@@ -0,0 +1,81 @@
+// server/internal/service/order_state_test.go
+package service
+
+import (
+	"testing"
+
+	"server/internal/model"
+)
+
+func TestCheckOrderTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    int8
+		to      int8
+		actor   string
+		wantErr bool
+	}{
+		{"供应商接单", model.OrderStatusPending, model.OrderStatusAccepted, model.OrderActorSupplier, false},
+		{"供应商拒单", model.OrderStatusPending, model.OrderStatusRejected, model.OrderActorSupplier, false},
+		{"买家取消待接单", model.OrderStatusPending, model.OrderStatusCancelled, model.OrderActorBuyer, false},
+		{"平台取消待接单", model.OrderStatusPending, model.OrderStatusCancelled, model.OrderActorPlatform, false},
+		{"买家不能接单", model.OrderStatusPending, model.OrderStatusAccepted, model.OrderActorBuyer, true},
+		{"供应商不能取消待接单", model.OrderStatusPending, model.OrderStatusCancelled, model.OrderActorSupplier, true},
+		{"供应商开始分拣", model.OrderStatusAccepted, model.OrderStatusPicking, model.OrderActorSupplier, false},
+		{"供应商取消已接单", model.OrderStatusAccepted, model.OrderStatusCancelled, model.OrderActorSupplier, false},
+		{"买家不能取消已接单", model.OrderStatusAccepted, model.OrderStatusCancelled, model.OrderActorBuyer, true},
+		{"供应商发车", model.OrderStatusPicking, model.OrderStatusDelivering, model.OrderActorSupplier, false},
+		{"只有平台能取消分拣中", model.OrderStatusPicking, model.OrderStatusCancelled, model.OrderActorSupplier, true},
+		{"平台取消分拣中", model.OrderStatusPicking, model.OrderStatusCancelled, model.OrderActorPlatform, false},
+		{"配送中不能取消", model.OrderStatusDelivering, model.OrderStatusCancelled, model.OrderActorPlatform, true},
+		{"供应商送达", model.OrderStatusDelivering, model.OrderStatusDelivered, model.OrderActorSupplier, false},
+		{"买家验收", model.OrderStatusDelivered, model.OrderStatusReceived, model.OrderActorBuyer, false},
+		{"供应商不能验收", model.OrderStatusDelivered, model.OrderStatusReceived, model.OrderActorSupplier, true},
+		{"系统自动完成", model.OrderStatusReceived, model.OrderStatusCompleted, model.OrderActorSystem, false},
+		{"供应商不能完成", model.OrderStatusReceived, model.OrderStatusCompleted, model.OrderActorSupplier, true},
+		{"不能跳过分拣", model.OrderStatusAccepted, model.OrderStatusDelivering, model.OrderActorSupplier, true},
+		{"已完成为终态", model.OrderStatusCompleted, model.OrderStatusCancelled, model.OrderActorPlatform, true},
+		{"已取消为终态", model.OrderStatusCancelled, model.OrderStatusPending, model.OrderActorPlatform, true},
+		{"状态未变化", model.OrderStatusAccepted, model.OrderStatusAccepted, model.OrderActorSupplier, true},
+		{"未知目标状态", model.OrderStatusPending, 99, model.OrderActorSupplier, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOrderTransition(tt.from, tt.to, tt.actor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkOrderTransition(%d, %d, %s) error = %v, wantErr %v", tt.from, tt.to, tt.actor, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOrderActorOf(t *testing.T) {
+	tests := []struct {
+		role    string
+		want    string
+		wantErr bool
+	}{
+		{model.RoleCanteenAdmin, model.OrderActorBuyer, false},
+		{model.RoleCanteenStaff, model.OrderActorBuyer, false},
+		{model.RoleMerchantAdmin, model.OrderActorBuyer, false},
+		{model.RoleMerchantStaff, model.OrderActorBuyer, false},
+		{model.RoleSupplierAdmin, model.OrderActorSupplier, false},
+		{model.RoleSupplierStaff, model.OrderActorSupplier, false},
+		{model.RolePlatformAdmin, model.OrderActorPlatform, false},
+		{model.RolePlatformStaff, model.OrderActorPlatform, false},
+		{model.RoleSchoolAdmin, "", true},
+		{model.RoleSchoolStaff, "", true},
+		{"unknown", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			got, err := orderActorOf(tt.role)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("orderActorOf(%s) error = %v, wantErr %v", tt.role, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("orderActorOf(%s) = %q, want %q", tt.role, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// server/internal/service/org_scope.go
+package service
+
+import (
+	"errors"
+
+	"server/internal/model"
+	"server/internal/repository"
+)
+
+// isPlatformRole 判断角色是否属于平台
+func isPlatformRole(role string) bool {
+	return role == model.RolePlatformAdmin || role == model.RolePlatformStaff
+}
+
+// isSchoolRole 判断角色是否属于学校
+func isSchoolRole(role string) bool {
+	return role == model.RoleSchoolAdmin || role == model.RoleSchoolStaff
+}
+
+// isSupplierRole 判断角色是否属于供应商
+func isSupplierRole(role string) bool {
+	return role == model.RoleSupplierAdmin || role == model.RoleSupplierStaff
+}
+
+// isBuyerRole 判断角色是否属于买家（食堂或商户）
+func isBuyerRole(role string) bool {
+	switch role {
+	case model.RoleCanteenAdmin, model.RoleCanteenStaff, model.RoleMerchantAdmin, model.RoleMerchantStaff:
+		return true
+	}
+	return false
+}
+
+// buyerOrgIDsOfSchool 返回某学校下所有买家（食堂及其下属商户）的组织ID
+func buyerOrgIDsOfSchool(orgRepo repository.IOrganizationRepository, schoolID uint) ([]uint, error) {
+	canteenIDs, err := orgRepo.ListIDsByParents([]uint{schoolID}, []int8{int8(model.OrgTypeCanteen)})
+	if err != nil {
+		return nil, err
+	}
+	// 商户既可能直接挂在学校下，也可能挂在食堂下
+	parents := append([]uint{schoolID}, canteenIDs...)
+	merchantIDs, err := orgRepo.ListIDsByParents(parents, []int8{int8(model.OrgTypeMerchant)})
+	if err != nil {
+		return nil, err
+	}
+	return append(canteenIDs, merchantIDs...), nil
+}
+
+// schoolIDOf 沿组织树向上查找所属学校的ID（学校自身返回自己的ID）
+func schoolIDOf(orgRepo repository.IOrganizationRepository, orgID uint) (uint, error) {
+	// 组织树层级很浅（学校 -> 食堂 -> 商户），限制查找深度以防数据异常导致死循环
+	for depth := 0; depth < 5 && orgID != 0; depth++ {
+		org, err := orgRepo.GetByID(orgID)
+		if err != nil {
+			return 0, errors.New("所属组织不存在")
+		}
+		if org.OrgType == int8(model.OrgTypeSchool) {
+			return org.ID, nil
+		}
+		orgID = org.ParentID
+	}
+	return 0, errors.New("无法确定所属学校")
+}
+
+// deliveryPointOf 沿组织树向上查找买家的收货点：挂在食堂下的商户归入食堂，直属学校的商户归入学校
+func deliveryPointOf(orgRepo repository.IOrganizationRepository, orgID uint) (uint, error) {
+	for depth := 0; depth < 5 && orgID != 0; depth++ {
+		org, err := orgRepo.GetByID(orgID)
+		if err != nil {
+			return 0, errors.New("所属组织不存在")
+		}
+		if org.OrgType == int8(model.OrgTypeCanteen) || org.OrgType == int8(model.OrgTypeSchool) {
+			return org.ID, nil
+		}
+		orgID = org.ParentID
+	}
+	return 0, errors.New("无法确定收货食堂")
+}
@@ -0,0 +1,492 @@
+// server/internal/service/picking_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+	"server/pkg/sheet"
+
+	"gorm.io/gorm"
+)
+
+// IPickingService 定义供应商分拣服务接口
+type IPickingService interface {
+	// CreatePickList 汇总某送达日期下所有已接单订单的明细生成分拣单，订单随之进入分拣中状态
+	CreatePickList(req *model.CreatePickListRequest, claims *jwt.CustomClaims) (*model.PickListDetail, error)
+	ListPickLists(filter model.PickListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdPickList, int64, error)
+	// GetPickList 获取分拣单详情，按商品汇总后再按食堂（或学校）分行
+	GetPickList(id uint, claims *jwt.CustomClaims) (*model.PickListDetail, error)
+	// AssignPicker 为分拣单指派分拣员，分拣员须已启用且健康证在有效期内
+	AssignPicker(id uint, req *model.AssignPickerRequest, claims *jwt.CustomClaims) (*model.PickListDetail, error)
+	// PickLine 登记分拣行的实拣数量
+	PickLine(id, lineID uint, req *model.PickLineRequest, claims *jwt.CustomClaims) (*model.OrdPickLine, error)
+	// CompletePickList 完成分拣，所有分拣行都须已登记；实拣短少会回写到订单明细和订单金额
+	CompletePickList(id uint, claims *jwt.CustomClaims) (*model.OrdPickList, error)
+	// WriteSheet 将分拣单写出为可打印的表格
+	WriteSheet(id uint, claims *jwt.CustomClaims, w io.Writer, format sheet.Format) error
+}
+
+// pickingService 实现了 IPickingService 接口
+type pickingService struct {
+	pickRepo     repository.IPickListRepository
+	orderRepo    repository.IOrderRepository
+	orgRepo      repository.IOrganizationRepository
+	staffRepo    repository.ISupplierStaffRepository
+	staffService ISupplierStaffService
+}
+
+// NewPickingService 创建一个新的 pickingService 实例
+func NewPickingService(pickRepo repository.IPickListRepository, orderRepo repository.IOrderRepository, orgRepo repository.IOrganizationRepository, staffRepo repository.ISupplierStaffRepository, staffService ISupplierStaffService) IPickingService {
+	return &pickingService{
+		pickRepo:     pickRepo,
+		orderRepo:    orderRepo,
+		orgRepo:      orgRepo,
+		staffRepo:    staffRepo,
+		staffService: staffService,
+	}
+}
+
+// CreatePickList 生成分拣单
+func (s *pickingService) CreatePickList(req *model.CreatePickListRequest, claims *jwt.CustomClaims) (*model.PickListDetail, error) {
+	if !isSupplierRole(claims.Role) {
+		return nil, errors.New("仅供应商可以生成分拣单")
+	}
+	date, err := time.ParseInLocation("2006-01-02", req.DeliveryDate, time.Local)
+	if err != nil {
+		return nil, errors.New("无效的送达日期")
+	}
+	if req.PickerID != 0 {
+		if _, err := s.staffService.EnsureAssignable(claims.OrgID, req.PickerID, model.StaffRolePicker); err != nil {
+			return nil, err
+		}
+	}
+
+	list := &model.OrdPickList{
+		SupplierID:   claims.OrgID,
+		DeliveryDate: date,
+		PickerID:     req.PickerID,
+		Status:       model.PickListPending,
+		OperatorID:   claims.UserID,
+	}
+	err = s.pickRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		orderRepo := repository.NewOrderRepository(tx)
+		pickRepo := repository.NewPickListRepository(tx)
+
+		// 1. 找出该送达日期下所有已接单的订单
+		orders, err := orderRepo.ListForPicking(claims.OrgID, date, model.OrderStatusAccepted)
+		if err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			return errors.New("该送达日期没有待分拣的已接单订单")
+		}
+		orderIDs := make([]uint, 0, len(orders))
+		destOf := make(map[uint]uint, len(orders))
+		points := make(map[uint]uint)
+		for _, order := range orders {
+			orderIDs = append(orderIDs, order.ID)
+			point, ok := points[order.MerchantID]
+			if !ok {
+				if point, err = deliveryPointOf(s.orgRepo, order.MerchantID); err != nil {
+					return fmt.Errorf("订单 [%s]: %w", order.OrderNo, err)
+				}
+				points[order.MerchantID] = point
+			}
+			destOf[order.ID] = point
+		}
+		items, err := orderRepo.ListItemsByOrders(orderIDs)
+		if err != nil {
+			return err
+		}
+
+		// 2. 同一商品发往同一食堂（或学校）的明细合并为一个分拣行，缺货的明细不参与分拣
+		type lineKey struct{ productID, destID uint }
+		var keys []lineKey
+		lines := make(map[lineKey]*model.OrdPickLine)
+		itemIDs := make(map[lineKey][]uint)
+		for _, item := range items {
+			if item.Quantity <= 0 || item.PickLineID != 0 {
+				continue
+			}
+			key := lineKey{item.ProductID, destOf[item.OrderID]}
+			line, ok := lines[key]
+			if !ok {
+				line = &model.OrdPickLine{
+					ProductID: item.ProductID,
+					SnapName:  item.SnapName,
+					SnapSpecs: item.SnapSpecs,
+					DestID:    key.destID,
+				}
+				lines[key] = line
+				keys = append(keys, key)
+			}
+			line.Quantity += item.Quantity
+			itemIDs[key] = append(itemIDs[key], item.ID)
+		}
+		if len(keys) == 0 {
+			return errors.New("订单中没有需要分拣的商品")
+		}
+
+		list.OrderCount = len(orders)
+		if err := pickRepo.Create(list); err != nil {
+			return fmt.Errorf("生成分拣单失败: %w", err)
+		}
+		created := make([]model.OrdPickLine, 0, len(keys))
+		for _, key := range keys {
+			line := *lines[key]
+			line.PickListID = list.ID
+			created = append(created, line)
+		}
+		if err := pickRepo.CreateLines(created); err != nil {
+			return fmt.Errorf("生成分拣行失败: %w", err)
+		}
+		for i, key := range keys {
+			if err := orderRepo.SetItemsPickLine(itemIDs[key], created[i].ID); err != nil {
+				return err
+			}
+		}
+
+		// 3. 订单进入分拣中状态
+		remark := fmt.Sprintf("生成分拣单 #%d", list.ID)
+		for i := range orders {
+			if err := transitionOrder(orderRepo, &orders[i], model.OrderStatusPicking, model.OrderActorSupplier, claims, remark); err != nil {
+				return fmt.Errorf("订单 [%s]: %w", orders[i].OrderNo, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildDetail(list)
+}
+
+// ListPickLists 分页列出本供应商的分拣单
+func (s *pickingService) ListPickLists(filter model.PickListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdPickList, int64, error) {
+	filter.SupplierID = claims.OrgID
+	return s.pickRepo.List(filter, page, pageSize)
+}
+
+// GetPickList 获取分拣单详情
+func (s *pickingService) GetPickList(id uint, claims *jwt.CustomClaims) (*model.PickListDetail, error) {
+	list, err := s.getOwnList(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildDetail(list)
+}
+
+// AssignPicker 指派分拣员
+func (s *pickingService) AssignPicker(id uint, req *model.AssignPickerRequest, claims *jwt.CustomClaims) (*model.PickListDetail, error) {
+	list, err := s.getOwnList(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.staffService.EnsureAssignable(claims.OrgID, req.StaffID, model.StaffRolePicker); err != nil {
+		return nil, err
+	}
+	err = s.pickRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		pickRepo := repository.NewPickListRepository(tx)
+		locked, err := pickRepo.Lock(list.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status == model.PickListDone {
+			return errors.New("分拣单已完成，不能更换分拣员")
+		}
+		if err := pickRepo.UpdateFields(locked.ID, map[string]interface{}{"picker_id": req.StaffID}); err != nil {
+			return fmt.Errorf("指派分拣员失败: %w", err)
+		}
+		list = locked
+		list.PickerID = req.StaffID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildDetail(list)
+}
+
+// PickLine 登记分拣行的实拣数量，实拣数量不能超过应拣数量
+func (s *pickingService) PickLine(id, lineID uint, req *model.PickLineRequest, claims *jwt.CustomClaims) (*model.OrdPickLine, error) {
+	list, err := s.getOwnList(id, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	// 锁定分拣单后再校验状态，避免与完成分拣并发时修改已回写到订单的分拣行
+	var line *model.OrdPickLine
+	err = s.pickRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		pickRepo := repository.NewPickListRepository(tx)
+		locked, err := pickRepo.Lock(list.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status == model.PickListDone {
+			return errors.New("分拣单已完成，不能再修改")
+		}
+		if locked.PickerID == 0 {
+			return errors.New("请先为分拣单指派分拣员")
+		}
+		line, err = pickRepo.GetLine(lineID)
+		if err != nil || line.PickListID != locked.ID {
+			return errors.New("分拣行不存在")
+		}
+		if *req.PickedQty > line.Quantity {
+			return fmt.Errorf("实拣数量不能超过应拣数量 %d", line.Quantity)
+		}
+
+		now := time.Now()
+		line.PickedQty = *req.PickedQty
+		line.Picked = true
+		line.PickedAt = &now
+		if err := pickRepo.UpdateLine(line); err != nil {
+			return fmt.Errorf("登记分拣数量失败: %w", err)
+		}
+		if locked.Status == model.PickListPending {
+			return pickRepo.UpdateFields(locked.ID, map[string]interface{}{"status": model.PickListPicking})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+// CompletePickList 完成分拣
+func (s *pickingService) CompletePickList(id uint, claims *jwt.CustomClaims) (*model.OrdPickList, error) {
+	list, err := s.getOwnList(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	err = s.pickRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		pickRepo := repository.NewPickListRepository(tx)
+		locked, err := pickRepo.Lock(list.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status == model.PickListDone {
+			return errors.New("分拣单已完成")
+		}
+		lines, err := pickRepo.ListLines(locked.ID)
+		if err != nil {
+			return err
+		}
+		pending := 0
+		for _, line := range lines {
+			if !line.Picked {
+				pending++
+			}
+		}
+		if pending > 0 {
+			return fmt.Errorf("还有 %d 行尚未分拣", pending)
+		}
+
+		// 实拣少于应拣的部分回写到订单明细和订单金额
+		if err := reconcilePickedQty(repository.NewOrderRepository(tx), lines); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		list = locked
+		list.Status = model.PickListDone
+		list.CompletedAt = &now
+		err = pickRepo.UpdateFields(list.ID, map[string]interface{}{"status": list.Status, "completed_at": now})
+		if err != nil {
+			return fmt.Errorf("完成分拣失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// reconcilePickedQty 将分拣短少回写到订单明细：每行的实拣数量按明细ID顺序分配，
+// 分不足的明细记为少发，分不到的记为缺货，并重新计算受影响订单的总金额
+func reconcilePickedQty(orderRepo repository.IOrderRepository, lines []model.OrdPickLine) error {
+	remaining := make(map[uint]int)
+	lineIDs := make([]uint, 0, len(lines))
+	for _, line := range lines {
+		if line.PickedQty < line.Quantity {
+			remaining[line.ID] = line.PickedQty
+			lineIDs = append(lineIDs, line.ID)
+		}
+	}
+	if len(lineIDs) == 0 {
+		return nil
+	}
+
+	items, err := orderRepo.ListItemsByPickLines(lineIDs)
+	if err != nil {
+		return err
+	}
+	var orderIDs []uint
+	affected := make(map[uint]bool)
+	for i := range items {
+		item := &items[i]
+		alloc := min(item.Quantity, remaining[item.PickLineID])
+		remaining[item.PickLineID] -= alloc
+		if alloc == item.Quantity {
+			continue
+		}
+		status := model.OrderItemFulfilShort
+		if alloc == 0 {
+			status = model.OrderItemFulfilOutOfStock
+		}
+		adj := model.AdjustOrderItem{ItemID: item.ID, FulfilStatus: &status, Quantity: alloc, Remark: "分拣短少"}
+		if err := adjustOrderItem(item, adj); err != nil {
+			return err
+		}
+		if err := orderRepo.UpdateItem(item); err != nil {
+			return fmt.Errorf("更新订单明细失败: %w", err)
+		}
+		if !affected[item.OrderID] {
+			affected[item.OrderID] = true
+			orderIDs = append(orderIDs, item.OrderID)
+		}
+	}
+
+	orderItems, err := orderRepo.ListItemsByOrders(orderIDs)
+	if err != nil {
+		return err
+	}
+	totals := make(map[uint]float64, len(orderIDs))
+	for _, item := range orderItems {
+		totals[item.OrderID] = roundMoney(totals[item.OrderID] + item.Amount)
+	}
+	for _, id := range orderIDs {
+		affected, err := orderRepo.UpdateTotal(id, model.OrderStatusPicking, totals[id])
+		if err != nil {
+			return fmt.Errorf("更新订单金额失败: %w", err)
+		}
+		// 订单已不在分拣中（如已被平台取消），明细的调整不能再回写
+		if affected == 0 {
+			return fmt.Errorf("订单 #%d 已不在分拣中，请刷新后重试", id)
+		}
+	}
+	return nil
+}
+
+// WriteSheet 写出可打印的分拣单：表头为分拣单信息，每个商品先列合计行，再逐行列出各食堂的应拣数量
+func (s *pickingService) WriteSheet(id uint, claims *jwt.CustomClaims, w io.Writer, format sheet.Format) error {
+	list, err := s.getOwnList(id, claims)
+	if err != nil {
+		return err
+	}
+	detail, err := s.buildDetail(list)
+	if err != nil {
+		return err
+	}
+
+	sw, err := sheet.NewWriter(w, format, "分拣单")
+	if err != nil {
+		return err
+	}
+	rows := [][]string{
+		{"分拣单", fmt.Sprintf("#%d", list.ID)},
+		{"送达日期", list.DeliveryDate.Format("2006-01-02")},
+		{"分拣员", detail.PickerName},
+		{"订单数", strconv.Itoa(list.OrderCount)},
+		{},
+		{"商品", "规格", "收货食堂", "应拣数量", "实拣数量", "核对"},
+	}
+	for _, product := range detail.Products {
+		rows = append(rows, []string{product.Name, product.Specs, "合计", strconv.Itoa(product.Quantity), "", ""})
+		for _, dest := range product.Destinations {
+			picked := ""
+			if dest.Picked {
+				picked = strconv.Itoa(dest.PickedQty)
+			}
+			rows = append(rows, []string{"", "", dest.DestName, strconv.Itoa(dest.Quantity), picked, ""})
+		}
+	}
+	for _, row := range rows {
+		if err := sw.WriteRow(row); err != nil {
+			return err
+		}
+	}
+	return sw.Close()
+}
+
+// getOwnList 获取当前供应商自己的分拣单
+func (s *pickingService) getOwnList(id uint, claims *jwt.CustomClaims) (*model.OrdPickList, error) {
+	list, err := s.pickRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("分拣单不存在")
+		}
+		return nil, err
+	}
+	if list.SupplierID != claims.OrgID {
+		return nil, errors.New("无权操作其他供应商的分拣单")
+	}
+	return list, nil
+}
+
+// buildDetail 组装分拣单详情，分拣行按商品汇总，商品和食堂均按首次出现的顺序排列
+func (s *pickingService) buildDetail(list *model.OrdPickList) (*model.PickListDetail, error) {
+	lines, err := s.pickRepo.ListLines(list.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	destIDs := make([]uint, 0, len(lines))
+	for _, line := range lines {
+		destIDs = append(destIDs, line.DestID)
+	}
+	orgs, err := s.orgRepo.ListByIDs(destIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(orgs))
+	for _, org := range orgs {
+		names[org.ID] = org.Name
+	}
+
+	detail := &model.PickListDetail{PickList: list, Products: []model.PickProductGroup{}, LineCount: len(lines)}
+	if list.PickerID != 0 {
+		if staff, err := s.staffRepo.GetByID(list.PickerID); err == nil {
+			detail.PickerName = staff.Name
+		}
+	}
+	groupIndex := make(map[uint]int)
+	for _, line := range lines {
+		idx, ok := groupIndex[line.ProductID]
+		if !ok {
+			idx = len(detail.Products)
+			groupIndex[line.ProductID] = idx
+			detail.Products = append(detail.Products, model.PickProductGroup{
+				ProductID:    line.ProductID,
+				Name:         line.SnapName,
+				Specs:        line.SnapSpecs,
+				Destinations: []model.PickDestination{},
+			})
+		}
+		group := &detail.Products[idx]
+		group.Quantity += line.Quantity
+		group.PickedQty += line.PickedQty
+		group.Destinations = append(group.Destinations, model.PickDestination{
+			LineID:    line.ID,
+			DestID:    line.DestID,
+			DestName:  names[line.DestID],
+			Quantity:  line.Quantity,
+			PickedQty: line.PickedQty,
+			Picked:    line.Picked,
+			PickedAt:  line.PickedAt,
+		})
+		if line.Picked {
+			detail.PickedCount++
+		}
+	}
+	return detail, nil
+}
@@ -0,0 +1,153 @@
+// server/internal/service/picking_service_test.go
+package service
+
+import (
+	"testing"
+
+	"server/internal/model"
+	"server/internal/repository"
+)
+
+// fakeOrderRepo 在内存中保存订单明细，只实现分拣回写用到的方法
+type fakeOrderRepo struct {
+	repository.IOrderRepository
+	items  []model.OrdOrderItem
+	totals map[uint]float64
+	stale  map[uint]bool // 已不在分拣中的订单
+}
+
+func (r *fakeOrderRepo) ListItemsByPickLines(lineIDs []uint) ([]model.OrdOrderItem, error) {
+	wanted := make(map[uint]bool, len(lineIDs))
+	for _, id := range lineIDs {
+		wanted[id] = true
+	}
+	var items []model.OrdOrderItem
+	for _, item := range r.items {
+		if wanted[item.PickLineID] {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *fakeOrderRepo) ListItemsByOrders(orderIDs []uint) ([]model.OrdOrderItem, error) {
+	wanted := make(map[uint]bool, len(orderIDs))
+	for _, id := range orderIDs {
+		wanted[id] = true
+	}
+	var items []model.OrdOrderItem
+	for _, item := range r.items {
+		if wanted[item.OrderID] {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *fakeOrderRepo) UpdateItem(item *model.OrdOrderItem) error {
+	for i := range r.items {
+		if r.items[i].ID == item.ID {
+			r.items[i] = *item
+		}
+	}
+	return nil
+}
+
+func (r *fakeOrderRepo) UpdateTotal(id uint, status int8, total float64) (int64, error) {
+	if r.stale[id] {
+		return 0, nil
+	}
+	r.totals[id] = total
+	return 1, nil
+}
+
+func TestReconcilePickedQty(t *testing.T) {
+	type want struct {
+		quantity int
+		status   int8
+	}
+	tests := []struct {
+		name   string
+		lines  []model.OrdPickLine
+		items  []model.OrdOrderItem
+		want   map[uint]want
+		totals map[uint]float64
+	}{
+		{
+			name:   "全部拣足不回写",
+			lines:  []model.OrdPickLine{{ID: 1, Quantity: 5, PickedQty: 5}},
+			items:  []model.OrdOrderItem{{ID: 11, OrderID: 100, PickLineID: 1, Quantity: 5, SnapPrice: 2, Amount: 10}},
+			want:   map[uint]want{11: {5, model.OrderItemFulfilNormal}},
+			totals: map[uint]float64{},
+		},
+		{
+			name:  "短少按明细ID顺序分配",
+			lines: []model.OrdPickLine{{ID: 1, Quantity: 8, PickedQty: 6}},
+			items: []model.OrdOrderItem{
+				{ID: 11, OrderID: 100, PickLineID: 1, Quantity: 5, SnapPrice: 2, Amount: 10},
+				{ID: 12, OrderID: 200, PickLineID: 1, Quantity: 3, SnapPrice: 2, Amount: 6},
+				{ID: 13, OrderID: 200, PickLineID: 9, Quantity: 1, SnapPrice: 4, Amount: 4},
+			},
+			want: map[uint]want{
+				11: {5, model.OrderItemFulfilNormal},
+				12: {1, model.OrderItemFulfilShort},
+				13: {1, model.OrderItemFulfilNormal},
+			},
+			totals: map[uint]float64{200: 6},
+		},
+		{
+			name:  "分不到的明细记为缺货",
+			lines: []model.OrdPickLine{{ID: 1, Quantity: 8, PickedQty: 2}},
+			items: []model.OrdOrderItem{
+				{ID: 11, OrderID: 100, PickLineID: 1, Quantity: 5, SnapPrice: 1.5, Amount: 7.5},
+				{ID: 12, OrderID: 200, PickLineID: 1, Quantity: 3, SnapPrice: 2, Amount: 6},
+			},
+			want: map[uint]want{
+				11: {2, model.OrderItemFulfilShort},
+				12: {0, model.OrderItemFulfilOutOfStock},
+			},
+			totals: map[uint]float64{100: 3, 200: 0},
+		},
+		{
+			name:   "整行未拣到",
+			lines:  []model.OrdPickLine{{ID: 1, Quantity: 4, PickedQty: 0}},
+			items:  []model.OrdOrderItem{{ID: 11, OrderID: 100, PickLineID: 1, Quantity: 4, SnapPrice: 3, Amount: 12}},
+			want:   map[uint]want{11: {0, model.OrderItemFulfilOutOfStock}},
+			totals: map[uint]float64{100: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOrderRepo{items: append([]model.OrdOrderItem(nil), tt.items...), totals: map[uint]float64{}}
+			if err := reconcilePickedQty(repo, tt.lines); err != nil {
+				t.Fatalf("reconcilePickedQty error = %v", err)
+			}
+			for _, item := range repo.items {
+				w := tt.want[item.ID]
+				if item.Quantity != w.quantity || item.FulfilStatus != w.status {
+					t.Errorf("item %d: quantity %d status %d, want %d %d", item.ID, item.Quantity, item.FulfilStatus, w.quantity, w.status)
+				}
+			}
+			if len(repo.totals) != len(tt.totals) {
+				t.Errorf("totals = %v, want %v", repo.totals, tt.totals)
+			}
+			for id, total := range tt.totals {
+				if repo.totals[id] != total {
+					t.Errorf("order %d total = %v, want %v", id, repo.totals[id], total)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcilePickedQtyStaleOrder(t *testing.T) {
+	repo := &fakeOrderRepo{
+		items:  []model.OrdOrderItem{{ID: 11, OrderID: 100, PickLineID: 1, Quantity: 5, SnapPrice: 2, Amount: 10}},
+		totals: map[uint]float64{},
+		stale:  map[uint]bool{100: true},
+	}
+	lines := []model.OrdPickLine{{ID: 1, Quantity: 5, PickedQty: 3}}
+	if err := reconcilePickedQty(repo, lines); err == nil {
+		t.Error("reconcilePickedQty should fail when the order is no longer picking")
+	}
+}
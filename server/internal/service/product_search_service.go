@@ -0,0 +1,258 @@
+// server/internal/service/product_search_service.go
+package service
+
+import (
+	"errors"
+	"sync"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+	"server/pkg/search"
+)
+
+// searchRebuildBatch 重建索引时每批从数据库读取的商品数
+const searchRebuildBatch = 500
+
+// IProductSearchService 定义商品搜索服务接口
+type IProductSearchService interface {
+	// Search 按名称搜索商品，支持中文、拼音全拼和首字母，结果按相关度排序
+	Search(filter model.ProductListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.ScmProduct, int64, error)
+	// Refresh 从数据库重新加载指定商品并更新索引，商品已不存在时从索引中删除
+	Refresh(ids ...uint) error
+	// Rebuild 从数据库全量重建索引，返回索引的商品数
+	Rebuild() (int, error)
+}
+
+// productMeta 保存索引中商品的筛选字段，避免搜索时回表过滤
+type productMeta struct {
+	SchoolID    uint
+	CategoryID  uint
+	AuditStatus int8
+	IsListed    bool
+}
+
+// productSearchService 实现了 IProductSearchService 接口。
+// 索引在首次搜索时从数据库构建，之后由商品服务在商品变更时调用 Refresh 保持同步。
+type productSearchService struct {
+	productRepo repository.IProductRepository
+	orgRepo     repository.IOrganizationRepository
+	fileService IFileService
+
+	mu    sync.RWMutex
+	index *search.Index
+	meta  map[uint]productMeta
+	built bool
+
+	buildMu sync.Mutex // 串行化重建和增量更新，避免增量更新被重建覆盖
+}
+
+// NewProductSearchService 创建一个新的 productSearchService 实例
+func NewProductSearchService(productRepo repository.IProductRepository, orgRepo repository.IOrganizationRepository, fileService IFileService) IProductSearchService {
+	return &productSearchService{
+		productRepo: productRepo,
+		orgRepo:     orgRepo,
+		fileService: fileService,
+		index:       search.New(),
+		meta:        make(map[uint]productMeta),
+	}
+}
+
+// Search 按名称搜索商品。
+// 平台可搜索全部；学校只能搜索本校商品；供应商只能搜索本校已审核通过的商品；食堂和商户只能搜索本校已上架的商品。
+func (s *productSearchService) Search(filter model.ProductListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.ScmProduct, int64, error) {
+	if filter.Keyword == "" {
+		return nil, 0, errors.New("请输入搜索关键词")
+	}
+	if err := s.scope(&filter, claims); err != nil {
+		return nil, 0, err
+	}
+	if err := s.ensureBuilt(); err != nil {
+		return nil, 0, err
+	}
+
+	s.mu.RLock()
+	index, meta := s.index, s.meta
+	hits := index.Search(filter.Keyword, func(id uint) bool {
+		m, ok := meta[id]
+		return ok && matchProductFilter(m, filter)
+	})
+	s.mu.RUnlock()
+
+	// 分页后只回表查询当前页的商品，并按相关度顺序返回
+	total := int64(len(hits))
+	start := (page - 1) * pageSize
+	if start >= len(hits) {
+		return []model.ScmProduct{}, total, nil
+	}
+	end := start + pageSize
+	if end > len(hits) {
+		end = len(hits)
+	}
+	ids := make([]uint, 0, end-start)
+	for _, hit := range hits[start:end] {
+		ids = append(ids, hit.ID)
+	}
+	products, err := s.productRepo.ListByIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[uint]model.ScmProduct, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+	result := make([]model.ScmProduct, 0, len(ids))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			result = append(result, p)
+		}
+	}
+	s.fileService.FillProductImages(result)
+	return result, total, nil
+}
+
+// Refresh 从数据库重新加载指定商品并更新索引
+func (s *productSearchService) Refresh(ids ...uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+
+	s.mu.RLock()
+	built := s.built
+	s.mu.RUnlock()
+	if !built {
+		// 索引尚未构建，首次搜索时会全量加载
+		return nil
+	}
+
+	products, err := s.productRepo.ListByIDs(ids)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	found := make(map[uint]bool, len(products))
+	for i := range products {
+		s.add(s.index, s.meta, &products[i])
+		found[products[i].ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			s.index.Remove(id)
+			delete(s.meta, id)
+		}
+	}
+	return nil
+}
+
+// Rebuild 从数据库全量重建索引，构建完成后整体替换旧索引，重建期间搜索不受影响
+func (s *productSearchService) Rebuild() (int, error) {
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+	return s.rebuild()
+}
+
+// ensureBuilt 在索引尚未构建时执行一次全量构建
+func (s *productSearchService) ensureBuilt() error {
+	s.mu.RLock()
+	built := s.built
+	s.mu.RUnlock()
+	if built {
+		return nil
+	}
+
+	s.buildMu.Lock()
+	defer s.buildMu.Unlock()
+	s.mu.RLock()
+	built = s.built
+	s.mu.RUnlock()
+	if built {
+		return nil
+	}
+	_, err := s.rebuild()
+	return err
+}
+
+// rebuild 执行全量重建，调用方需持有 buildMu
+func (s *productSearchService) rebuild() (int, error) {
+	index := search.New()
+	meta := make(map[uint]productMeta)
+	var afterID uint
+	for {
+		products, err := s.productRepo.ListAfterID(afterID, searchRebuildBatch)
+		if err != nil {
+			return 0, err
+		}
+		for i := range products {
+			s.add(index, meta, &products[i])
+		}
+		if len(products) < searchRebuildBatch {
+			break
+		}
+		afterID = products[len(products)-1].ID
+	}
+
+	s.mu.Lock()
+	s.index, s.meta, s.built = index, meta, true
+	s.mu.Unlock()
+	return len(meta), nil
+}
+
+// add 将商品写入索引和筛选字段表，已被合并的商品从索引中删除
+func (s *productSearchService) add(index *search.Index, meta map[uint]productMeta, p *model.ScmProduct) {
+	if p.MergedInto != 0 {
+		index.Remove(p.ID)
+		delete(meta, p.ID)
+		return
+	}
+	index.Add(p.ID, p.Name)
+	meta[p.ID] = productMeta{
+		SchoolID:    p.SchoolID,
+		CategoryID:  p.CategoryID,
+		AuditStatus: p.AuditStatus,
+		IsListed:    p.IsListed,
+	}
+}
+
+// scope 根据当前用户的角色限定搜索范围
+func (s *productSearchService) scope(filter *model.ProductListFilter, claims *jwt.CustomClaims) error {
+	switch {
+	case isPlatformRole(claims.Role):
+		// 平台不做额外限制
+	case isSchoolRole(claims.Role):
+		filter.SchoolID = claims.OrgID
+	default:
+		schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+		if err != nil {
+			return err
+		}
+		approved := model.ProductAuditApproved
+		filter.SchoolID = schoolID
+		filter.AuditStatus = &approved
+		if !isSupplierRole(claims.Role) {
+			listed := true
+			filter.IsListed = &listed
+		}
+	}
+	return nil
+}
+
+// matchProductFilter 判断商品是否满足筛选条件
+func matchProductFilter(m productMeta, filter model.ProductListFilter) bool {
+	if filter.SchoolID != 0 && m.SchoolID != filter.SchoolID {
+		return false
+	}
+	if filter.CategoryID != 0 && m.CategoryID != filter.CategoryID {
+		return false
+	}
+	if filter.AuditStatus != nil && m.AuditStatus != *filter.AuditStatus {
+		return false
+	}
+	if filter.IsListed != nil && m.IsListed != *filter.IsListed {
+		return false
+	}
+	return true
+}
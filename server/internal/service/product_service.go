@@ -0,0 +1,614 @@
+// server/internal/service/product_service.go
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// IProductService 定义商品(SPU)服务接口
+type IProductService interface {
+	// SubmitProduct 供应商提交新商品，进入所属学校的审核队列
+	SubmitProduct(req *model.SubmitProductRequest, claims *jwt.CustomClaims) (*model.ScmProduct, error)
+	// UpdateProduct 供应商修改待审或被驳回的商品，被驳回的商品修改后自动重新提交
+	UpdateProduct(id uint, req *model.SubmitProductRequest, claims *jwt.CustomClaims) (*model.ScmProduct, error)
+	// UpdateCerts 供应商为已审核通过的商品上传三证资质
+	UpdateCerts(id uint, req *model.UpdateProductCertsRequest, claims *jwt.CustomClaims) error
+	GetProduct(id uint, claims *jwt.CustomClaims) (*model.ProductDetail, error)
+	// ListProducts 按当前用户的组织范围列出商品
+	ListProducts(filter model.ProductListFilter, mine bool, claims *jwt.CustomClaims, page, pageSize int) ([]model.ScmProduct, int64, error)
+	ApproveProduct(id uint, claims *jwt.CustomClaims) error
+	RejectProduct(id uint, req *model.RejectProductRequest, claims *jwt.CustomClaims) error
+	// ListProduct 学校上架商品，只有审核通过且至少有一个启用报价的商品才能上架
+	ListProduct(id uint, claims *jwt.CustomClaims) error
+	// UnlistProducts 学校批量下架商品，返回实际下架的数量
+	UnlistProducts(ids []uint, claims *jwt.CustomClaims) (int64, error)
+	// ListListedProducts 列出当前用户所属学校已上架的商品，供食堂选购
+	ListListedProducts(filter model.ProductListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.ScmProduct, int64, error)
+	// SyncListing 在报价变更后调用，商品已无启用报价时自动下架
+	SyncListing(productID uint) error
+	// FindDuplicates 在同一学校、同一分类下查找与该商品疑似重复的商品，按相似度从高到低排列
+	FindDuplicates(id uint, claims *jwt.CustomClaims) ([]model.ProductDuplicate, error)
+	// MergeProduct 学校将重复商品合并到保留的商品，报价和购物车条目随之转移
+	MergeProduct(id uint, req *model.MergeProductRequest, claims *jwt.CustomClaims) error
+}
+
+// productService 实现了 IProductService 接口
+type productService struct {
+	productRepo     repository.IProductRepository
+	orgRepo         repository.IOrganizationRepository
+	categoryService ICategoryService
+	searchService   IProductSearchService
+	fileService     IFileService
+}
+
+// NewProductService 创建一个新的 productService 实例
+func NewProductService(productRepo repository.IProductRepository, orgRepo repository.IOrganizationRepository, categoryService ICategoryService, searchService IProductSearchService, fileService IFileService) IProductService {
+	return &productService{
+		productRepo:     productRepo,
+		orgRepo:         orgRepo,
+		categoryService: categoryService,
+		searchService:   searchService,
+		fileService:     fileService,
+	}
+}
+
+// SubmitProduct 供应商提交新商品
+func (s *productService) SubmitProduct(req *model.SubmitProductRequest, claims *jwt.CustomClaims) (*model.ScmProduct, error) {
+	// 1. 商品归属于供应商所在的学校
+	schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 商品只能挂在叶子分类下
+	if err := s.categoryService.EnsureLeafCategory(req.CategoryID); err != nil {
+		return nil, err
+	}
+
+	product := &model.ScmProduct{
+		SchoolID:    schoolID,
+		CategoryID:  req.CategoryID,
+		Name:        req.Name,
+		Image:       req.Image,
+		Specs:       req.Specs,
+		Unit:        req.Unit,
+		SourceType:  model.ProductSourceSupplier,
+		CreatorID:   claims.OrgID, // 记录最早上传的供应商
+		StaticCerts: "[]",         // JSON 列不能写入空字符串
+		AuditStatus: model.ProductAuditPending,
+	}
+
+	// 3. 商品和提交记录在同一事务中写入
+	err = s.productRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewProductRepository(tx)
+		if err := txRepo.Create(product); err != nil {
+			return fmt.Errorf("创建商品失败: %w", err)
+		}
+		return txRepo.CreateAudit(newProductAudit(product.ID, model.ProductAuditActionSubmit,
+			model.ProductAuditPending, model.ProductAuditPending, "", claims))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.reindex(product.ID)
+	return product, nil
+}
+
+// UpdateProduct 供应商修改自己提交的商品
+func (s *productService) UpdateProduct(id uint, req *model.SubmitProductRequest, claims *jwt.CustomClaims) (*model.ScmProduct, error) {
+	product, err := s.getProduct(id)
+	if err != nil {
+		return nil, err
+	}
+	if product.CreatorID != claims.OrgID || product.SourceType != model.ProductSourceSupplier {
+		return nil, errors.New("无权修改此商品")
+	}
+	if product.MergedInto != 0 {
+		return nil, errors.New("商品已被合并，不能再修改")
+	}
+	// 规格在审核通过时锁定，因此已通过的商品整体不允许再修改
+	if product.AuditStatus == model.ProductAuditApproved {
+		return nil, errors.New("商品已审核通过，不能再修改")
+	}
+	if req.CategoryID != product.CategoryID {
+		if err := s.categoryService.EnsureLeafCategory(req.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	fromStatus := product.AuditStatus
+	product.CategoryID = req.CategoryID
+	product.Name = req.Name
+	product.Image = req.Image
+	product.Specs = req.Specs
+	product.Unit = req.Unit
+	product.AuditStatus = model.ProductAuditPending
+
+	err = s.productRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewProductRepository(tx)
+		if err := txRepo.Update(product); err != nil {
+			return fmt.Errorf("更新商品失败: %w", err)
+		}
+		// 被驳回的商品修改后重新进入审核队列，记录一次重新提交；待审核期间的修改也需留痕
+		action := model.ProductAuditActionEdit
+		if fromStatus == model.ProductAuditRejected {
+			action = model.ProductAuditActionResubmit
+		}
+		return txRepo.CreateAudit(newProductAudit(product.ID, action,
+			fromStatus, model.ProductAuditPending, "", claims))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.reindex(product.ID)
+	return product, nil
+}
+
+// UpdateCerts 供应商为已审核通过的商品上传三证资质
+func (s *productService) UpdateCerts(id uint, req *model.UpdateProductCertsRequest, claims *jwt.CustomClaims) error {
+	product, err := s.getProduct(id)
+	if err != nil {
+		return err
+	}
+	if product.CreatorID != claims.OrgID {
+		return errors.New("无权修改此商品")
+	}
+	if product.AuditStatus != model.ProductAuditApproved {
+		return errors.New("商品审核通过后才能上传资质")
+	}
+
+	certs, err := json.Marshal(req.Certs)
+	if err != nil {
+		return err
+	}
+	product.StaticCerts = string(certs)
+	return s.productRepo.Update(product)
+}
+
+// GetProduct 获取商品详情及审核历史
+func (s *productService) GetProduct(id uint, claims *jwt.CustomClaims) (*model.ProductDetail, error) {
+	product, err := s.getProduct(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkVisible(product, claims); err != nil {
+		return nil, err
+	}
+
+	audits, err := s.productRepo.ListAudits(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	detail := &model.ProductDetail{Product: product, Audits: audits, Duplicates: []model.ProductDuplicate{}}
+	single := []model.ScmProduct{*product}
+	s.fileService.FillProductImages(single)
+	product.ImageURLs = single[0].ImageURLs
+
+	// 待审商品附带疑似重复商品，供审核人判断是否需要合并
+	if product.AuditStatus == model.ProductAuditPending && product.MergedInto == 0 {
+		if detail.Duplicates, err = s.duplicatesOf(product, claims); err != nil {
+			return nil, err
+		}
+	}
+	return detail, nil
+}
+
+// ListProducts 按当前用户的组织范围列出商品。
+// 平台可查看全部；学校只能查看本校商品；供应商在 mine=true 时查看自己提交的商品，否则查看本校已审核通过的商品。
+func (s *productService) ListProducts(filter model.ProductListFilter, mine bool, claims *jwt.CustomClaims, page, pageSize int) ([]model.ScmProduct, int64, error) {
+	switch {
+	case isPlatformRole(claims.Role):
+		// 平台不做额外限制
+	case isSchoolRole(claims.Role):
+		filter.SchoolID = claims.OrgID
+	case isSupplierRole(claims.Role):
+		schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.SchoolID = schoolID
+		if mine {
+			filter.CreatorID = claims.OrgID
+		} else {
+			approved := model.ProductAuditApproved
+			filter.AuditStatus = &approved
+		}
+	default:
+		return nil, 0, errors.New("您的角色无权查看商品库")
+	}
+	return s.listWithImages(filter, page, pageSize)
+}
+
+// ApproveProduct 学校审核通过商品，通过后规格锁定
+func (s *productService) ApproveProduct(id uint, claims *jwt.CustomClaims) error {
+	return s.audit(id, claims, true, "")
+}
+
+// RejectProduct 学校驳回商品，必须填写驳回原因
+func (s *productService) RejectProduct(id uint, req *model.RejectProductRequest, claims *jwt.CustomClaims) error {
+	return s.audit(id, claims, false, req.Reason)
+}
+
+// audit 执行审核操作，状态变更和审核记录在同一事务中写入
+func (s *productService) audit(id uint, claims *jwt.CustomClaims, approve bool, reason string) error {
+	product, err := s.getProduct(id)
+	if err != nil {
+		return err
+	}
+	if product.SchoolID != claims.OrgID {
+		return errors.New("无权审核其他学校的商品")
+	}
+	if product.MergedInto != 0 {
+		return errors.New("商品已被合并，无需审核")
+	}
+	if product.AuditStatus != model.ProductAuditPending {
+		return errors.New("只能审核待审状态的商品")
+	}
+
+	action := model.ProductAuditActionReject
+	toStatus := model.ProductAuditRejected
+	if approve {
+		action = model.ProductAuditActionApprove
+		toStatus = model.ProductAuditApproved
+		product.SpecsLocked = true
+	}
+	product.AuditStatus = toStatus
+	product.AuditReason = reason
+
+	err = s.productRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewProductRepository(tx)
+		if err := txRepo.Update(product); err != nil {
+			return fmt.Errorf("更新审核状态失败: %w", err)
+		}
+		return txRepo.CreateAudit(newProductAudit(product.ID, action, model.ProductAuditPending, toStatus, reason, claims))
+	})
+	if err != nil {
+		return err
+	}
+	s.reindex(product.ID)
+	return nil
+}
+
+// ListProduct 学校上架商品
+func (s *productService) ListProduct(id uint, claims *jwt.CustomClaims) error {
+	product, err := s.getProduct(id)
+	if err != nil {
+		return err
+	}
+	if product.SchoolID != claims.OrgID {
+		return errors.New("无权操作其他学校的商品")
+	}
+	if product.IsListed {
+		return nil
+	}
+	if product.MergedInto != 0 {
+		return errors.New("商品已被合并，不能上架")
+	}
+	if product.AuditStatus != model.ProductAuditApproved {
+		return errors.New("只有审核通过的商品才能上架")
+	}
+
+	quotes, err := s.productRepo.CountEnabledQuotes(product.ID)
+	if err != nil {
+		return err
+	}
+	if quotes == 0 {
+		return errors.New("商品还没有启用中的供应商报价，不能上架")
+	}
+
+	product.IsListed = true
+	return s.saveListing(product)
+}
+
+// UnlistProducts 学校批量下架商品，只会影响本校的商品
+func (s *productService) UnlistProducts(ids []uint, claims *jwt.CustomClaims) (int64, error) {
+	count, err := s.productRepo.UnlistByIDs(claims.OrgID, ids)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		s.reindex(ids...)
+	}
+	return count, nil
+}
+
+// ListListedProducts 列出当前用户所属学校已上架的商品
+func (s *productService) ListListedProducts(filter model.ProductListFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.ScmProduct, int64, error) {
+	if !isBuyerRole(claims.Role) && !isSchoolRole(claims.Role) {
+		return nil, 0, errors.New("无权查看上架商品")
+	}
+	schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	listed := true
+	approved := model.ProductAuditApproved
+	filter.SchoolID = schoolID
+	filter.CreatorID = 0
+	filter.IsListed = &listed
+	filter.AuditStatus = &approved
+	return s.listWithImages(filter, page, pageSize)
+}
+
+// listWithImages 查询商品列表并附带图片各尺寸的下载地址
+func (s *productService) listWithImages(filter model.ProductListFilter, page, pageSize int) ([]model.ScmProduct, int64, error) {
+	products, total, err := s.productRepo.List(filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.fileService.FillProductImages(products)
+	return products, total, nil
+}
+
+// SyncListing 商品已无启用报价时自动下架
+func (s *productService) SyncListing(productID uint) error {
+	product, err := s.getProduct(productID)
+	if err != nil {
+		return err
+	}
+	if !product.IsListed {
+		return nil
+	}
+
+	quotes, err := s.productRepo.CountEnabledQuotes(productID)
+	if err != nil {
+		return err
+	}
+	if quotes > 0 {
+		return nil
+	}
+
+	product.IsListed = false
+	return s.saveListing(product)
+}
+
+// FindDuplicates 查找疑似重复的商品
+func (s *productService) FindDuplicates(id uint, claims *jwt.CustomClaims) ([]model.ProductDuplicate, error) {
+	product, err := s.getProduct(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkVisible(product, claims); err != nil {
+		return nil, err
+	}
+	return s.duplicatesOf(product, claims)
+}
+
+// MergeProduct 将重复商品合并到保留的商品。
+// 供应商在两个商品上都有报价时保留目标商品上的报价，被合并商品上的报价停用并记录合并去向，购物车条目转到保留的报价上。
+func (s *productService) MergeProduct(id uint, req *model.MergeProductRequest, claims *jwt.CustomClaims) error {
+	if id == req.TargetID {
+		return errors.New("不能将商品合并到自身")
+	}
+	source, err := s.getProduct(id)
+	if err != nil {
+		return err
+	}
+	target, err := s.getProduct(req.TargetID)
+	if err != nil {
+		return err
+	}
+	if source.SchoolID != claims.OrgID || target.SchoolID != claims.OrgID {
+		return errors.New("只能合并本校的商品")
+	}
+	if source.MergedInto != 0 || target.MergedInto != 0 {
+		return errors.New("商品已被合并")
+	}
+	if target.AuditStatus != model.ProductAuditApproved {
+		return errors.New("只能合并到已审核通过的商品")
+	}
+
+	err = s.productRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		quoteRepo := repository.NewQuoteRepository(tx)
+		cartRepo := repository.NewCartRepository(tx)
+		guideRepo := repository.NewGuidePriceRepository(tx)
+
+		// 1. 转移报价
+		quotes, err := quoteRepo.ListByProduct(source.ID)
+		if err != nil {
+			return err
+		}
+		for i := range quotes {
+			quote := &quotes[i]
+			kept, err := quoteRepo.GetByProductAndSupplier(target.ID, quote.SupplierID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				quote.ProductID = target.ID
+				if err := quoteRepo.Update(quote); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := moveCartItems(cartRepo, quote.ID, kept.ID); err != nil {
+				return err
+			}
+			if err := quoteRepo.DeletePendingHistory(quote.ID); err != nil {
+				return err
+			}
+			// 订单和常备订单仍引用该报价，不能删除，停用后记录合并去向
+			quote.IsEnabled = false
+			quote.MergedInto = kept.ID
+			if err := quoteRepo.Update(quote); err != nil {
+				return err
+			}
+		}
+		if err := quoteRepo.MoveHistory(source.ID, target.ID); err != nil {
+			return err
+		}
+
+		// 2. 目标商品没有指导价时沿用被合并商品的指导价
+		if guide, err := guideRepo.GetByProduct(source.ID); err == nil {
+			if _, err := guideRepo.GetByProduct(target.ID); errors.Is(err, gorm.ErrRecordNotFound) {
+				guide.ProductID = target.ID
+				if err := guideRepo.Save(guide); err != nil {
+					return err
+				}
+			} else if err := guideRepo.DeleteByProduct(source.ID); err != nil {
+				return err
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// 3. 标记被合并的商品并记录审核历史
+		source.MergedInto = target.ID
+		source.IsListed = false
+		txRepo := repository.NewProductRepository(tx)
+		if err := txRepo.Update(source); err != nil {
+			return fmt.Errorf("合并商品失败: %w", err)
+		}
+		reason := fmt.Sprintf("合并至商品 [%s](#%d)", target.Name, target.ID)
+		return txRepo.CreateAudit(newProductAudit(source.ID, model.ProductAuditActionMerge,
+			source.AuditStatus, source.AuditStatus, reason, claims))
+	})
+	if err != nil {
+		return err
+	}
+	s.reindex(source.ID, target.ID)
+	return nil
+}
+
+// duplicatesOf 计算同一学校、同一分类下与商品疑似重复的商品。
+// 只有平台和学校能看到其他供应商待审的商品，其余角色只比对已审核通过的商品和自己提交的商品。
+func (s *productService) duplicatesOf(product *model.ScmProduct, claims *jwt.CustomClaims) ([]model.ProductDuplicate, error) {
+	candidates, err := s.productRepo.ListDuplicateCandidates(product.SchoolID, product.CategoryID, product.ID)
+	if err != nil {
+		return nil, err
+	}
+	approvedOnly := !isPlatformRole(claims.Role) && !isSchoolRole(claims.Role)
+
+	duplicates := []model.ProductDuplicate{}
+	for _, c := range candidates {
+		if approvedOnly && c.AuditStatus != model.ProductAuditApproved && c.CreatorID != claims.OrgID {
+			continue
+		}
+		if sim := productSimilarity(product, &c); sim >= duplicateThreshold {
+			duplicates = append(duplicates, model.ProductDuplicate{Product: c, Similarity: math.Round(sim*100) / 100})
+		}
+	}
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Similarity > duplicates[j].Similarity
+	})
+	if len(duplicates) > maxDuplicates {
+		duplicates = duplicates[:maxDuplicates]
+	}
+	return duplicates, nil
+}
+
+// moveCartItems 将购物车中某报价的条目转到另一报价上，同一买家已有该报价时合并数量
+func moveCartItems(cartRepo repository.ICartRepository, fromQuoteID, toQuoteID uint) error {
+	carts, err := cartRepo.ListByQuote(fromQuoteID)
+	if err != nil {
+		return err
+	}
+	for i := range carts {
+		cart := &carts[i]
+		existing, err := cartRepo.GetByMerchantAndQuote(cart.MerchantID, toQuoteID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			cart.QuoteID = toQuoteID
+			if err := cartRepo.Update(cart); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		existing.Quantity += cart.Quantity
+		if err := cartRepo.Update(existing); err != nil {
+			return err
+		}
+		if err := cartRepo.Delete(cart.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// saveListing 保存商品上下架状态并同步搜索索引
+func (s *productService) saveListing(product *model.ScmProduct) error {
+	if err := s.productRepo.Update(product); err != nil {
+		return err
+	}
+	s.reindex(product.ID)
+	return nil
+}
+
+// reindex 同步搜索索引。索引同步失败不影响业务操作，可通过重建索引修复
+func (s *productService) reindex(ids ...uint) {
+	if err := s.searchService.Refresh(ids...); err != nil {
+		log.Printf("同步商品搜索索引失败: %v", err)
+	}
+}
+
+// checkVisible 校验当前用户是否可以查看该商品
+func (s *productService) checkVisible(product *model.ScmProduct, claims *jwt.CustomClaims) error {
+	switch {
+	case isPlatformRole(claims.Role):
+		return nil
+	case isSchoolRole(claims.Role):
+		if product.SchoolID == claims.OrgID {
+			return nil
+		}
+	case isSupplierRole(claims.Role):
+		if product.CreatorID == claims.OrgID {
+			return nil
+		}
+		schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+		if err != nil {
+			return err
+		}
+		if product.SchoolID == schoolID && product.AuditStatus == model.ProductAuditApproved {
+			return nil
+		}
+	case isBuyerRole(claims.Role):
+		// 食堂和商户可查看所属学校已审核通过的商品，用于比价和查看历史价格
+		schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+		if err != nil {
+			return err
+		}
+		if product.SchoolID == schoolID && product.AuditStatus == model.ProductAuditApproved {
+			return nil
+		}
+	}
+	return errors.New("无权查看此商品")
+}
+
+// getProduct 获取商品，不存在时返回友好的错误信息
+func (s *productService) getProduct(id uint) (*model.ScmProduct, error) {
+	product, err := s.productRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("商品不存在")
+		}
+		return nil, err
+	}
+	return product, nil
+}
+
+// newProductAudit 构造一条审核记录
+func newProductAudit(productID uint, action, fromStatus, toStatus int8, reason string, claims *jwt.CustomClaims) *model.ScmProductAudit {
+	return &model.ScmProductAudit{
+		ProductID:     productID,
+		Action:        action,
+		FromStatus:    fromStatus,
+		ToStatus:      toStatus,
+		Reason:        reason,
+		OperatorID:    claims.UserID,
+		OperatorOrgID: claims.OrgID,
+	}
+}
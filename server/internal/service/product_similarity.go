@@ -0,0 +1,85 @@
+// server/internal/service/product_similarity.go
+package service
+
+import (
+	"strings"
+	"unicode"
+
+	"server/internal/model"
+)
+
+const (
+	// duplicateThreshold 相似度达到该值的商品视为疑似重复
+	duplicateThreshold = 0.6
+	// maxDuplicates 最多返回的疑似重复商品数
+	maxDuplicates = 10
+	// unitMismatchPenalty 计量单位不同时相似度的折扣
+	unitMismatchPenalty = 0.8
+)
+
+// productTextReplacer 去掉不影响商品含义的常见写法差异，如"50斤/袋"与"50斤每袋"
+var productTextReplacer = strings.NewReplacer("每", "", "约", "")
+
+// normalizeProductText 归一化商品文本：全角转半角、转小写，只保留汉字、字母和数字
+func normalizeProductText(text string) string {
+	var b strings.Builder
+	for _, r := range productTextReplacer.Replace(text) {
+		if r >= '！' && r <= '～' {
+			r = r - '！' + '!'
+		}
+		r = unicode.ToLower(r)
+		if unicode.Is(unicode.Han, r) || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// productSimilarity 计算两个商品的相似度：名称和规格归一化后的字符双字组 Dice 系数，单位不同时打折
+func productSimilarity(a, b *model.ScmProduct) float64 {
+	sim := diceCoefficient(normalizeProductText(a.Name+a.Specs), normalizeProductText(b.Name+b.Specs))
+	if normalizeProductText(a.Unit) != normalizeProductText(b.Unit) {
+		sim *= unitMismatchPenalty
+	}
+	return sim
+}
+
+// diceCoefficient 计算两个字符串字符双字组的 Dice 系数，单字符字符串按单字比较
+func diceCoefficient(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+	ga, gb := bigrams(a), bigrams(b)
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(ga))
+	for _, g := range ga {
+		counts[g]++
+	}
+	shared := 0
+	for _, g := range gb {
+		if counts[g] > 0 {
+			counts[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ga)+len(gb))
+}
+
+// bigrams 将字符串切分为字符双字组
+func bigrams(s string) []string {
+	runes := []rune(s)
+	if len(runes) < 2 {
+		if len(runes) == 1 {
+			return []string{s}
+		}
+		return nil
+	}
+	grams := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		grams = append(grams, string(runes[i:i+2]))
+	}
+	return grams
+}
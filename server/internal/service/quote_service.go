@@ -0,0 +1,353 @@
+// server/internal/service/quote_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// IQuoteService 定义供应商报价(SKU)服务接口
+type IQuoteService interface {
+	// CreateQuote 供应商为本校已审核通过的商品报价，每个供应商对同一商品只能报价一次
+	CreateQuote(req *model.CreateQuoteRequest, claims *jwt.CustomClaims) (*model.ScmProductQuote, error)
+	// UpdatePrice 供应商调整报价，指定未来的生效时间时作为预约调价
+	UpdatePrice(id uint, req *model.UpdateQuotePriceRequest, claims *jwt.CustomClaims) (*model.ScmProductQuote, error)
+	// SetEnabled 供应商启用或停用报价，商品已无启用报价时自动下架
+	SetEnabled(id uint, enabled bool, claims *jwt.CustomClaims) error
+	ListMyQuotes(keyword string, claims *jwt.CustomClaims, page, pageSize int) ([]model.QuoteListItem, int64, error)
+	// ListPriceHistory 查询商品的历史价格，用于绘制价格走势图
+	ListPriceHistory(filter model.PriceHistoryFilter, claims *jwt.CustomClaims) ([]model.ScmQuotePriceHistory, error)
+	// ApplyDuePriceChanges 使所有已到生效时间的预约调价生效，返回生效的条数
+	ApplyDuePriceChanges() (int, error)
+	// StartScheduler 启动后台定时任务，按固定间隔执行预约调价
+	StartScheduler(interval time.Duration)
+}
+
+// quoteService 实现了 IQuoteService 接口
+type quoteService struct {
+	quoteRepo      repository.IQuoteRepository
+	productRepo    repository.IProductRepository
+	orgRepo        repository.IOrganizationRepository
+	productService IProductService
+	guideService   IGuidePriceService
+}
+
+// NewQuoteService 创建一个新的 quoteService 实例
+func NewQuoteService(quoteRepo repository.IQuoteRepository, productRepo repository.IProductRepository, orgRepo repository.IOrganizationRepository, productService IProductService, guideService IGuidePriceService) IQuoteService {
+	return &quoteService{
+		quoteRepo:      quoteRepo,
+		productRepo:    productRepo,
+		orgRepo:        orgRepo,
+		productService: productService,
+		guideService:   guideService,
+	}
+}
+
+// CreateQuote 供应商为商品报价
+func (s *quoteService) CreateQuote(req *model.CreateQuoteRequest, claims *jwt.CustomClaims) (*model.ScmProductQuote, error) {
+	// 1. 只能为本校已审核通过的商品报价
+	product, err := s.productRepo.GetByID(req.ProductID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("商品不存在")
+		}
+		return nil, err
+	}
+	schoolID, err := schoolIDOf(s.orgRepo, claims.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	if product.SchoolID != schoolID {
+		return nil, errors.New("只能为本校商品库中的商品报价")
+	}
+	if product.AuditStatus != model.ProductAuditApproved {
+		return nil, errors.New("商品审核通过后才能报价")
+	}
+	if product.MergedInto != 0 {
+		return nil, errors.New("商品已被合并，请为合并后的商品报价")
+	}
+
+	// 2. 每个供应商对同一商品只能有一条报价
+	if _, err := s.quoteRepo.GetByProductAndSupplier(product.ID, claims.OrgID); err == nil {
+		return nil, errors.New("您已为该商品报过价，请直接调整报价")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// 3. 超出学校指导价上限的报价按学校设置拒绝或进入复核
+	reviewStatus, err := s.guideService.CheckQuotePrice(product.ID, req.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &model.ScmProductQuote{
+		ProductID:    product.ID,
+		SupplierID:   claims.OrgID,
+		Price:        req.Price,
+		BatchReports: "[]", // JSON 列不能写入空字符串
+		IsEnabled:    true,
+		ReviewStatus: reviewStatus,
+	}
+
+	// 4. 报价和首条价格记录在同一事务中写入
+	err = s.quoteRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewQuoteRepository(tx)
+		if err := txRepo.Create(quote); err != nil {
+			return fmt.Errorf("创建报价失败: %w", err)
+		}
+		// 有了报价之后规格不再允许变更，平台下发的商品此时才锁定规格
+		if !product.SpecsLocked {
+			if err := repository.NewProductRepository(tx).LockSpecs(product.ID); err != nil {
+				return err
+			}
+		}
+		return txRepo.CreateHistory(&model.ScmQuotePriceHistory{
+			QuoteID:       quote.ID,
+			ProductID:     quote.ProductID,
+			SupplierID:    quote.SupplierID,
+			Price:         quote.Price,
+			EffectiveFrom: time.Now(),
+			Applied:       true,
+			OperatorID:    claims.UserID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// UpdatePrice 供应商调整报价。
+// 新的调价会取消尚未生效的预约调价，同一报价同时只保留最后一次设定的预约。
+func (s *quoteService) UpdatePrice(id uint, req *model.UpdateQuotePriceRequest, claims *jwt.CustomClaims) (*model.ScmProductQuote, error) {
+	quote, err := s.getOwnQuote(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	reviewStatus, err := s.guideService.CheckQuotePrice(quote.ProductID, req.Price)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	immediate := req.EffectiveFrom == nil || !req.EffectiveFrom.After(now)
+
+	// 锁定报价行后只更新价格相关的列，避免覆盖并发写入的检测报告或供货开关
+	err = s.quoteRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewQuoteRepository(tx)
+		locked, err := txRepo.LockByID(quote.ID)
+		if err != nil {
+			return err
+		}
+		if locked.MergedInto != 0 {
+			return errors.New("报价已随商品合并，请操作合并后的报价")
+		}
+		quote = locked
+
+		history := &model.ScmQuotePriceHistory{
+			QuoteID:       quote.ID,
+			ProductID:     quote.ProductID,
+			SupplierID:    quote.SupplierID,
+			OldPrice:      quote.Price,
+			Price:         req.Price,
+			EffectiveFrom: now,
+			Applied:       immediate,
+			OperatorID:    claims.UserID,
+		}
+		if !immediate {
+			history.EffectiveFrom = *req.EffectiveFrom
+		}
+		if err := txRepo.DeletePendingHistory(quote.ID); err != nil {
+			return err
+		}
+		if immediate {
+			quote.Price = req.Price
+			quote.ReviewStatus = reviewStatus
+			quote.ReviewReason = ""
+			if err := txRepo.UpdateFields(quote.ID, map[string]interface{}{
+				"price":         quote.Price,
+				"review_status": quote.ReviewStatus,
+				"review_reason": "",
+			}); err != nil {
+				return fmt.Errorf("更新报价失败: %w", err)
+			}
+		}
+		return txRepo.CreateHistory(history)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if immediate && reviewStatus == model.QuoteReviewPending {
+		// 待复核的报价不能用于下单，商品可能因此没有可用报价
+		if err := s.productService.SyncListing(quote.ProductID); err != nil {
+			return nil, err
+		}
+	}
+	return quote, nil
+}
+
+// SetEnabled 供应商启用或停用报价
+func (s *quoteService) SetEnabled(id uint, enabled bool, claims *jwt.CustomClaims) error {
+	quote, err := s.getOwnQuote(id, claims)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	err = s.quoteRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewQuoteRepository(tx)
+		locked, err := txRepo.LockByID(quote.ID)
+		if err != nil {
+			return err
+		}
+		if locked.IsEnabled == enabled {
+			return nil
+		}
+		if enabled && locked.ReviewStatus == model.QuoteReviewRejected {
+			return errors.New("报价价格复核未通过，请调整报价后再启用")
+		}
+		if err := txRepo.UpdateFields(locked.ID, map[string]interface{}{"is_enabled": enabled}); err != nil {
+			return fmt.Errorf("更新报价状态失败: %w", err)
+		}
+		changed = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if changed && !enabled {
+		// 停用后商品可能已无任何启用的报价，需要自动下架
+		return s.productService.SyncListing(quote.ProductID)
+	}
+	return nil
+}
+
+// ListMyQuotes 列出当前供应商的报价，并附带尚未生效的预约调价
+func (s *quoteService) ListMyQuotes(keyword string, claims *jwt.CustomClaims, page, pageSize int) ([]model.QuoteListItem, int64, error) {
+	items, total, err := s.quoteRepo.ListBySupplier(claims.OrgID, keyword, page, pageSize)
+	if err != nil || len(items) == 0 {
+		return items, total, err
+	}
+
+	quoteIDs := make([]uint, len(items))
+	for i := range items {
+		quoteIDs[i] = items[i].ID
+	}
+	pending, err := s.quoteRepo.ListPendingHistory(quoteIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	pendingByQuote := make(map[uint]model.ScmQuotePriceHistory, len(pending))
+	for _, h := range pending {
+		pendingByQuote[h.QuoteID] = h
+	}
+	for i := range items {
+		if h, ok := pendingByQuote[items[i].ID]; ok {
+			price, from := h.Price, h.EffectiveFrom
+			items[i].PendingPrice = &price
+			items[i].PendingFrom = &from
+		}
+	}
+	return items, total, nil
+}
+
+// ListPriceHistory 查询商品的历史价格，只能查询自己有权查看的商品
+func (s *quoteService) ListPriceHistory(filter model.PriceHistoryFilter, claims *jwt.CustomClaims) ([]model.ScmQuotePriceHistory, error) {
+	if _, err := s.productService.GetProduct(filter.ProductID, claims); err != nil {
+		return nil, err
+	}
+	return s.quoteRepo.ListHistory(filter)
+}
+
+// ApplyDuePriceChanges 使所有已到生效时间的预约调价生效
+func (s *quoteService) ApplyDuePriceChanges() (int, error) {
+	due, err := s.quoteRepo.ListDueHistory(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	// 单条预约调价失败只记录日志，不影响其余到期的调价
+	applied := 0
+	for _, h := range due {
+		if err := s.applyPriceChange(h); err != nil {
+			log.Printf("预约调价 [%d] 生效失败: %v", h.ID, err)
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// applyPriceChange 使一条预约调价生效
+func (s *quoteService) applyPriceChange(h model.ScmQuotePriceHistory) error {
+	// 预约期间学校可能调整了指导价，生效时重新校验；已无法拒绝的超价报价转入复核
+	reviewStatus, err := s.guideService.CheckQuotePrice(h.ProductID, h.Price)
+	if errors.Is(err, ErrQuoteAboveCeiling) {
+		reviewStatus = model.QuoteReviewPending
+	} else if err != nil {
+		return err
+	}
+
+	err = s.quoteRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		txRepo := repository.NewQuoteRepository(tx)
+		quote, err := txRepo.LockByID(h.QuoteID)
+		if err != nil {
+			return err
+		}
+		if err := txRepo.UpdateFields(quote.ID, map[string]interface{}{
+			"price":         h.Price,
+			"review_status": reviewStatus,
+			"review_reason": "",
+		}); err != nil {
+			return err
+		}
+		// 以生效时的实际价格作为原价格，预约期间价格可能已被其他途径修改
+		return txRepo.MarkHistoryApplied(h.ID, quote.Price)
+	})
+	if err != nil {
+		return err
+	}
+	if reviewStatus == model.QuoteReviewPending {
+		return s.productService.SyncListing(h.ProductID)
+	}
+	return nil
+}
+
+// StartScheduler 启动后台定时任务
+func (s *quoteService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := s.ApplyDuePriceChanges(); err != nil {
+				log.Printf("执行预约调价失败: %v", err)
+			}
+		}
+	}()
+}
+
+// getOwnQuote 获取当前供应商自己的报价
+func (s *quoteService) getOwnQuote(id uint, claims *jwt.CustomClaims) (*model.ScmProductQuote, error) {
+	quote, err := s.quoteRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("报价不存在")
+		}
+		return nil, err
+	}
+	if quote.SupplierID != claims.OrgID {
+		return nil, errors.New("无权操作其他供应商的报价")
+	}
+	if quote.MergedInto != 0 {
+		return nil, errors.New("报价已随商品合并，请操作合并后的报价")
+	}
+	return quote, nil
+}
@@ -0,0 +1,303 @@
+// server/internal/service/receiving_service.go
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// IReceivingService 定义买家收货验收服务接口
+type IReceivingService interface {
+	// Receive 验收已送达的订单：登记各明细的实收数量、实称重量和拒收原因，保存凭证照片和验收人签名。
+	// 实收少于发货数量时生成待审核的售后记录，多于发货数量时生成调价记录并补计金额；没有差异的订单直接完成。
+	Receive(id uint, req *model.ReceiveOrderRequest, claims *jwt.CustomClaims) (*model.ReceiptDetail, error)
+	// GetReceipt 获取订单的验收结果
+	GetReceipt(id uint, claims *jwt.CustomClaims) (*model.ReceiptDetail, error)
+}
+
+// receivingService 实现了 IReceivingService 接口
+type receivingService struct {
+	orderRepo     repository.IOrderRepository
+	afterSaleRepo repository.IAfterSaleRepository
+	orgRepo       repository.IOrganizationRepository
+	fileRepo      repository.IFileRepository
+	fileService   IFileService
+}
+
+// NewReceivingService 创建一个新的 receivingService 实例
+func NewReceivingService(orderRepo repository.IOrderRepository, afterSaleRepo repository.IAfterSaleRepository, orgRepo repository.IOrganizationRepository, fileRepo repository.IFileRepository, fileService IFileService) IReceivingService {
+	return &receivingService{
+		orderRepo:     orderRepo,
+		afterSaleRepo: afterSaleRepo,
+		orgRepo:       orgRepo,
+		fileRepo:      fileRepo,
+		fileService:   fileService,
+	}
+}
+
+// Receive 验收订单
+func (s *receivingService) Receive(id uint, req *model.ReceiveOrderRequest, claims *jwt.CustomClaims) (*model.ReceiptDetail, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, errors.New("仅食堂和商户可以验收订单")
+	}
+	order, err := getVisibleOrder(s.orderRepo, s.orgRepo, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OrderStatusDelivered {
+		return nil, fmt.Errorf("订单处于 [%s] 状态，不能验收", orderStatusLabel(order.Status))
+	}
+
+	// 1. 凭证照片和签名须为本单位上传的文件
+	vouchers := uniqueKeys(req.Vouchers)
+	if err := checkUploadedFiles(s.fileRepo, append(vouchers, req.Signature), claims.OrgID); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(vouchers)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := []model.OrdOrder{*order}
+	if err := fillOrderItems(s.orderRepo, orders); err != nil {
+		return nil, err
+	}
+	order = &orders[0]
+
+	// 2. 登记各明细的验收结果，并按数量差异生成售后或调价记录
+	lines := make(map[uint]model.ReceiveOrderLine, len(req.Lines))
+	for _, line := range req.Lines {
+		if _, ok := lines[line.ItemID]; ok {
+			return nil, fmt.Errorf("订单明细 #%d 重复", line.ItemID)
+		}
+		lines[line.ItemID] = line
+	}
+	byID := make(map[uint]bool, len(order.Items))
+	for _, item := range order.Items {
+		byID[item.ID] = true
+	}
+	for itemID := range lines {
+		if !byID[itemID] {
+			return nil, fmt.Errorf("订单明细 #%d 不存在", itemID)
+		}
+	}
+
+	var afterSales []model.OrdAfterSale
+	var adjustments []model.OrdPriceAdjustment
+	total := 0.0
+	for i := range order.Items {
+		item := &order.Items[i]
+		line, ok := lines[item.ID]
+		if !ok {
+			line = model.ReceiveOrderLine{ItemID: item.ID}
+		}
+		afterSale, adjustment, err := receiveOrderItem(order, item, line, claims.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if afterSale != nil {
+			afterSales = append(afterSales, *afterSale)
+		}
+		if adjustment != nil {
+			adjustments = append(adjustments, *adjustment)
+		}
+		total = roundMoney(total + item.Amount)
+	}
+
+	// 验收差异生成的售后以验收凭证作为凭证
+	for i := range afterSales {
+		afterSales[i].Evidences = string(raw)
+	}
+
+	now := time.Now()
+	order.ReceiptVouchers = string(raw)
+	order.ReceiptSignature = req.Signature
+	order.ReceiverName = req.ReceiverName
+	order.ReceivedAt = &now
+	order.TotalAmount = total
+
+	// 3. 订单进入已收货状态，没有差异的订单直接完成，有差异的等售后处理完毕后再完成
+	err = s.orderRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		orderRepo := repository.NewOrderRepository(tx)
+		afterSaleRepo := repository.NewAfterSaleRepository(tx)
+		if err := transitionOrder(orderRepo, order, model.OrderStatusReceived, model.OrderActorBuyer, claims, req.Remark); err != nil {
+			return err
+		}
+		for i := range order.Items {
+			if err := orderRepo.UpdateItem(&order.Items[i]); err != nil {
+				return fmt.Errorf("更新订单明细失败: %w", err)
+			}
+		}
+		if err := orderRepo.UpdateReceipt(order); err != nil {
+			return fmt.Errorf("保存验收信息失败: %w", err)
+		}
+		for i := range afterSales {
+			if err := afterSaleRepo.Create(&afterSales[i]); err != nil {
+				return fmt.Errorf("生成售后记录失败: %w", err)
+			}
+		}
+		for i := range adjustments {
+			if err := orderRepo.CreatePriceAdjustment(&adjustments[i]); err != nil {
+				return fmt.Errorf("生成调价记录失败: %w", err)
+			}
+		}
+		if len(afterSales) > 0 {
+			return nil
+		}
+		return transitionOrder(orderRepo, order, model.OrderStatusCompleted, model.OrderActorBuyer, claims, "验收无差异，自动完成")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.fillReceiptImages(order)
+	if afterSales == nil {
+		afterSales = []model.OrdAfterSale{}
+	}
+	if adjustments == nil {
+		adjustments = []model.OrdPriceAdjustment{}
+	}
+	return &model.ReceiptDetail{Order: order, AfterSales: afterSales, Adjustments: adjustments}, nil
+}
+
+// GetReceipt 获取订单的验收结果
+func (s *receivingService) GetReceipt(id uint, claims *jwt.CustomClaims) (*model.ReceiptDetail, error) {
+	order, err := getVisibleOrder(s.orderRepo, s.orgRepo, id, claims)
+	if err != nil {
+		return nil, err
+	}
+	orders := []model.OrdOrder{*order}
+	if err := fillOrderItems(s.orderRepo, orders); err != nil {
+		return nil, err
+	}
+	order = &orders[0]
+	afterSales, err := s.afterSaleRepo.ListByOrder(id)
+	if err != nil {
+		return nil, err
+	}
+	adjustments, err := s.orderRepo.ListPriceAdjustments(id)
+	if err != nil {
+		return nil, err
+	}
+	s.fillReceiptImages(order)
+	return &model.ReceiptDetail{Order: order, AfterSales: afterSales, Adjustments: adjustments}, nil
+}
+
+// checkUploadedFiles 校验凭证文件均已上传且由本单位上传
+func checkUploadedFiles(fileRepo repository.IFileRepository, keys []string, orgID uint) error {
+	keys = uniqueKeys(keys)
+	if len(keys) == 0 {
+		return nil
+	}
+	files, err := fileRepo.ListByKeys(keys)
+	if err != nil {
+		return err
+	}
+	if len(files) != len(keys) {
+		return errors.New("部分凭证文件不存在，请重新上传")
+	}
+	// 相同内容的文件只保存一份，按上传记录判断本单位是否上传过
+	uploaded, err := fileRepo.CountUploaded(keys, orgID)
+	if err != nil {
+		return err
+	}
+	if uploaded != int64(len(keys)) {
+		return errors.New("凭证文件须由本单位上传")
+	}
+	return nil
+}
+
+// fillReceiptImages 为订单填充收货凭证和签名的下载地址，失败时只记录日志
+func (s *receivingService) fillReceiptImages(order *model.OrdOrder) {
+	orders := []model.OrdOrder{*order}
+	s.fileService.FillOrderVouchers(orders)
+	order.VoucherImages = orders[0].VoucherImages
+	order.SignatureImage = orders[0].SignatureImage
+}
+
+// receiveOrderItem 登记一条明细的验收结果。实收少于发货数量时返回待审核的售后记录，拒收按退货退款、短少按仅退款；
+// 实收多于发货数量时按快照单价补计明细金额并返回调价记录。
+func receiveOrderItem(order *model.OrdOrder, item *model.OrdOrderItem, line model.ReceiveOrderLine, operatorID uint) (*model.OrdAfterSale, *model.OrdPriceAdjustment, error) {
+	shipped := item.Quantity
+	received := shipped
+	status := model.ReceiveAccepted
+	switch {
+	case line.Rejected:
+		if line.Reason == "" {
+			return nil, nil, fmt.Errorf("商品 [%s] 拒收需填写原因", item.SnapName)
+		}
+		received = 0
+		status = model.ReceiveRejected
+	case line.ReceivedQty != nil:
+		received = *line.ReceivedQty
+	}
+
+	item.ReceiveStatus = status
+	item.ReceivedQty = received
+	item.ActualWeight = line.ActualWeight
+	item.ReceiveRemark = line.Reason
+
+	diff := received - shipped
+	switch {
+	case diff < 0:
+		afterSale := &model.OrdAfterSale{
+			OrderID:     order.ID,
+			OrderItemID: item.ID,
+			MerchantID:  order.MerchantID,
+			SupplierID:  order.SupplierID,
+			Source:      model.AfterSaleSourceReceipt,
+			Type:        model.AfterSaleTypeRefund,
+			Quantity:    -diff,
+			Reason:      line.Reason,
+			ApplyAmount: roundMoney(item.SnapPrice * float64(-diff)),
+			Status:      model.AfterSalePending,
+			OperatorID:  operatorID,
+		}
+		if status == model.ReceiveRejected {
+			afterSale.Type = model.AfterSaleTypeReturn
+		}
+		if afterSale.Reason == "" {
+			afterSale.Reason = fmt.Sprintf("验收实收 %d，少于发货数量 %d", received, shipped)
+		}
+		return afterSale, nil, nil
+	case diff > 0:
+		before := item.Amount
+		item.Amount = roundMoney(item.SnapPrice * float64(received))
+		adjustment := &model.OrdPriceAdjustment{
+			OrderID:      order.ID,
+			OrderItemID:  item.ID,
+			Quantity:     diff,
+			BeforeAmount: before,
+			AfterAmount:  item.Amount,
+			Reason:       line.Reason,
+			OperatorID:   operatorID,
+		}
+		if adjustment.Reason == "" {
+			adjustment.Reason = fmt.Sprintf("验收实收 %d，多于发货数量 %d", received, shipped)
+		}
+		return nil, adjustment, nil
+	}
+	return nil, nil, nil
+}
+
+// uniqueKeys 去除重复和空的文件 Key，保持原有顺序
+func uniqueKeys(keys []string) []string {
+	seen := make(map[string]bool, len(keys))
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,562 @@
+// server/internal/service/standing_order_service.go
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// IStandingOrderService 定义定期订单服务接口
+type IStandingOrderService interface {
+	List(claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdStandingOrder, int64, error)
+	// Get 获取模板详情，附带各商品当前的可下单状态
+	Get(id uint, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error)
+	Create(req *model.SaveStandingOrderRequest, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error)
+	Update(id uint, req *model.SaveStandingOrderRequest, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error)
+	Delete(id uint, claims *jwt.CustomClaims) error
+	Pause(id uint, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error)
+	Resume(id uint, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error)
+	// SetSkipDates 整体替换模板的跳过日期
+	SetSkipDates(id uint, req *model.SkipDatesRequest, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error)
+	// ListRuns 分页列出模板的执行记录
+	ListRuns(id uint, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdStandingOrderRun, int64, error)
+	// RunDue 为所有启用中的模板生成到期的订单：送达日期在今天之后、提前下单天数之内且尚未处理过的日期都会被处理。
+	// 模板中不可下单的商品会被略过并通知买家，全部不可下单时本次执行失败。
+	RunDue(now time.Time) (*model.StandingOrderRunResult, error)
+	// StartScheduler 启动后台定时任务，按固定间隔执行 RunDue
+	StartScheduler(interval time.Duration)
+}
+
+// standingOrderService 实现了 IStandingOrderService 接口
+type standingOrderService struct {
+	standingRepo        repository.IStandingOrderRepository
+	quoteRepo           repository.IQuoteRepository
+	orgRepo             repository.IOrganizationRepository
+	orderService        IOrderService
+	categoryService     ICategoryService
+	notificationService INotificationService
+}
+
+// NewStandingOrderService 创建一个新的 standingOrderService 实例
+func NewStandingOrderService(standingRepo repository.IStandingOrderRepository, quoteRepo repository.IQuoteRepository, orgRepo repository.IOrganizationRepository, orderService IOrderService, categoryService ICategoryService, notificationService INotificationService) IStandingOrderService {
+	return &standingOrderService{
+		standingRepo:        standingRepo,
+		quoteRepo:           quoteRepo,
+		orgRepo:             orgRepo,
+		orderService:        orderService,
+		categoryService:     categoryService,
+		notificationService: notificationService,
+	}
+}
+
+// List 列出当前买家的定期订单模板
+func (s *standingOrderService) List(claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdStandingOrder, int64, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, 0, errors.New("仅食堂和商户可以使用定期订单")
+	}
+	return s.standingRepo.ListByMerchant(claims.OrgID, page, pageSize)
+}
+
+// Get 获取模板详情
+func (s *standingOrderService) Get(id uint, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error) {
+	standing, err := s.getOwn(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.withLines(standing)
+}
+
+// Create 创建定期订单模板
+func (s *standingOrderService) Create(req *model.SaveStandingOrderRequest, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, errors.New("仅食堂和商户可以使用定期订单")
+	}
+	standing := &model.OrdStandingOrder{
+		MerchantID: claims.OrgID,
+		SkipDates:  "[]",
+		Status:     model.StandingOrderActive,
+		OperatorID: claims.UserID,
+	}
+	items, err := s.apply(standing, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.standingRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		standingRepo := repository.NewStandingOrderRepository(tx)
+		if err := standingRepo.Create(standing); err != nil {
+			return fmt.Errorf("创建定期订单失败: %w", err)
+		}
+		for i := range items {
+			items[i].StandingOrderID = standing.ID
+		}
+		return standingRepo.ReplaceItems(standing.ID, items)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.withLines(standing)
+}
+
+// Update 修改定期订单模板，跳过日期和启用状态保持不变
+func (s *standingOrderService) Update(id uint, req *model.SaveStandingOrderRequest, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error) {
+	standing, err := s.getOwn(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.apply(standing, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// 只更新请求中的字段，不覆盖并发修改的跳过日期、启用状态和最近执行日期
+	err = s.standingRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		standingRepo := repository.NewStandingOrderRepository(tx)
+		err := standingRepo.UpdateFields(standing.ID, map[string]interface{}{
+			"name":        standing.Name,
+			"supplier_id": standing.SupplierID,
+			"weekdays":    standing.Weekdays,
+			"dates":       standing.Dates,
+			"lead_days":   standing.LeadDays,
+			"start_date":  standing.StartDate,
+			"end_date":    standing.EndDate,
+		})
+		if err != nil {
+			return fmt.Errorf("更新定期订单失败: %w", err)
+		}
+		for i := range items {
+			items[i].StandingOrderID = standing.ID
+		}
+		return standingRepo.ReplaceItems(standing.ID, items)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.withLines(standing)
+}
+
+// Delete 删除定期订单模板，已生成的订单不受影响
+func (s *standingOrderService) Delete(id uint, claims *jwt.CustomClaims) error {
+	if _, err := s.getOwn(id, claims); err != nil {
+		return err
+	}
+	return s.standingRepo.Delete(id)
+}
+
+// Pause 暂停模板，暂停期间不会自动下单
+func (s *standingOrderService) Pause(id uint, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error) {
+	return s.setStatus(id, model.StandingOrderPaused, claims)
+}
+
+// Resume 恢复模板。暂停期间错过的送达日期不会补单，只处理恢复后仍在提前下单天数之内的日期。
+func (s *standingOrderService) Resume(id uint, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error) {
+	return s.setStatus(id, model.StandingOrderActive, claims)
+}
+
+// SetSkipDates 设置跳过日期
+func (s *standingOrderService) SetSkipDates(id uint, req *model.SkipDatesRequest, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error) {
+	standing, err := s.getOwn(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(uniqueDates(req.Dates))
+	if err != nil {
+		return nil, err
+	}
+	standing.SkipDates = string(raw)
+	if err := s.standingRepo.UpdateFields(standing.ID, map[string]interface{}{"skip_dates": standing.SkipDates}); err != nil {
+		return nil, fmt.Errorf("更新跳过日期失败: %w", err)
+	}
+	return s.withLines(standing)
+}
+
+// ListRuns 列出执行记录
+func (s *standingOrderService) ListRuns(id uint, claims *jwt.CustomClaims, page, pageSize int) ([]model.OrdStandingOrderRun, int64, error) {
+	if _, err := s.getOwn(id, claims); err != nil {
+		return nil, 0, err
+	}
+	return s.standingRepo.ListRuns(id, page, pageSize)
+}
+
+// RunDue 执行到期的定期订单
+func (s *standingOrderService) RunDue(now time.Time) (*model.StandingOrderRunResult, error) {
+	list, err := s.standingRepo.ListActive()
+	if err != nil {
+		return nil, err
+	}
+	result := &model.StandingOrderRunResult{}
+	today := startOfDay(now)
+	for i := range list {
+		for offset := 1; offset <= list[i].LeadDays; offset++ {
+			date := today.AddDate(0, 0, offset)
+			// 逐个日期重新读取模板，执行期间模板可能已被暂停、删除或修改
+			standing, err := s.standingRepo.GetByID(list[i].ID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			} else if err != nil {
+				return result, err
+			}
+			if standing.Status != model.StandingOrderActive || offset > standing.LeadDays {
+				break
+			}
+			if !standingScheduled(standing, date) {
+				continue
+			}
+			if _, err := s.standingRepo.GetRun(standing.ID, date); err == nil {
+				continue
+			} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return result, err
+			}
+
+			run, err := s.runOnce(standing, date)
+			if err != nil && isTransientError(err) {
+				// 数据库连接中断、超时或死锁时不记录执行结果，下次调度时重试
+				log.Printf("执行定期订单 [%d] %s 失败，稍后重试: %v", standing.ID, date.Format("2006-01-02"), err)
+				continue
+			}
+			if err != nil {
+				// 买家组织失效、结算校验不通过等重试也无法成功的错误记为失败并通知买家
+				run = s.failedRun(standing, date, err.Error())
+			}
+			if err := s.standingRepo.CreateRun(run); err != nil {
+				// 多个实例同时调度时，唯一索引保证同一日期只记录一次
+				log.Printf("记录定期订单 [%d] %s 的执行结果失败: %v", standing.ID, date.Format("2006-01-02"), err)
+				continue
+			}
+			switch run.Status {
+			case model.StandingRunOrdered:
+				result.Ordered++
+			case model.StandingRunSkipped:
+				result.Skipped++
+			default:
+				result.Failed++
+			}
+			if err := s.standingRepo.UpdateLastRunDate(standing.ID, date); err != nil {
+				log.Printf("更新定期订单 [%d] 最近执行日期失败: %v", standing.ID, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// StartScheduler 启动后台定时任务
+func (s *standingOrderService) StartScheduler(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			result, err := s.RunDue(time.Now())
+			if err != nil {
+				log.Printf("执行定期订单失败: %v", err)
+				continue
+			}
+			if result.Ordered+result.Skipped+result.Failed > 0 {
+				log.Printf("定期订单执行完成: 下单 %d, 跳过 %d, 失败 %d", result.Ordered, result.Skipped, result.Failed)
+			}
+		}
+	}()
+}
+
+// runOnce 按模板为某送达日期下单，返回执行记录；不可下单的商品和失败原因会通知买家。
+// 模板中没有可下单的商品时记为失败，读库或结算出错时返回 error，由调用方区分是否重试
+func (s *standingOrderService) runOnce(standing *model.OrdStandingOrder, date time.Time) (*model.OrdStandingOrderRun, error) {
+	run := &model.OrdStandingOrderRun{StandingOrderID: standing.ID, DeliveryDate: date}
+	day := date.Format("2006-01-02")
+	if containsDate(parseDates(standing.SkipDates), day) {
+		run.Status = model.StandingRunSkipped
+		run.Message = "按跳过日期跳过"
+		return run, nil
+	}
+
+	// 1. 挑出当前仍可下单的商品，其余商品略过
+	rows, err := s.standingRepo.ListLines(standing.ID)
+	if err != nil {
+		return nil, fmt.Errorf("读取模板商品失败: %w", err)
+	}
+	schoolID, err := schoolIDOf(s.orgRepo, standing.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[uint]*model.ReportPolicy)
+	now := time.Now()
+	var available []model.CartLineRow
+	var unavailable []string
+	for _, row := range rows {
+		policy, err := lineReportPolicy(s.categoryService, policies, row)
+		if err != nil {
+			return nil, err
+		}
+		line := buildCartLine(row, schoolID, policy, now)
+		if !line.Orderable {
+			name := row.ProductName
+			if name == "" {
+				name = fmt.Sprintf("报价#%d", row.QuoteID)
+			}
+			unavailable = append(unavailable, fmt.Sprintf("%s（%s）", name, line.Reason))
+			continue
+		}
+		available = append(available, row)
+	}
+	if len(available) == 0 {
+		return s.failedRun(standing, date, "模板中没有可下单的商品: "+strings.Join(unavailable, "；")), nil
+	}
+
+	// 2. 通过正常的结算流程下单，结算令牌保证同一送达日期只下单一次
+	token := fmt.Sprintf("standing-%d-%s", standing.ID, date.Format("20060102"))
+	result, err := s.orderService.CheckoutRows(standing.MerchantID, standing.OperatorID, token, available, date,
+		fmt.Sprintf("定期订单 [%s] 自动下单", standing.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	run.Status = model.StandingRunOrdered
+	run.CheckoutID = result.CheckoutID
+	run.Message = fmt.Sprintf("已下单 %d 笔，金额 %.2f", len(result.Orders), result.TotalAmount)
+	if len(unavailable) > 0 {
+		content := "以下商品已无法下单，本次未采购: " + strings.Join(unavailable, "；")
+		run.Message = truncate(run.Message+"；"+content, 500)
+		s.notify(standing, fmt.Sprintf("定期订单 [%s] %s 部分商品不可下单", standing.Name, day), content)
+	}
+	return run, nil
+}
+
+// failedRun 生成失败的执行记录并通知买家
+func (s *standingOrderService) failedRun(standing *model.OrdStandingOrder, date time.Time, message string) *model.OrdStandingOrderRun {
+	day := date.Format("2006-01-02")
+	s.notify(standing, fmt.Sprintf("定期订单 [%s] %s 未能下单", standing.Name, day), message)
+	return &model.OrdStandingOrderRun{
+		StandingOrderID: standing.ID,
+		DeliveryDate:    date,
+		Status:          model.StandingRunFailed,
+		Message:         truncate(message, 500),
+	}
+}
+
+// notify 通知模板所属买家，失败时只记录日志
+func (s *standingOrderService) notify(standing *model.OrdStandingOrder, title, content string) {
+	if err := s.notificationService.Notify(standing.MerchantID, model.NotifyStandingOrder, title, content, standing.ID); err != nil {
+		log.Printf("发送定期订单 [%d] 通知失败: %v", standing.ID, err)
+	}
+}
+
+// apply 校验请求并写入模板字段，返回模板商品
+func (s *standingOrderService) apply(standing *model.OrdStandingOrder, req *model.SaveStandingOrderRequest) ([]model.OrdStandingOrderItem, error) {
+	if len(req.Weekdays) == 0 && len(req.Dates) == 0 {
+		return nil, errors.New("请至少指定每周送达日或具体送达日期")
+	}
+	leadDays := req.LeadDays
+	if leadDays == 0 {
+		leadDays = model.StandingOrderMinLeadDays
+	}
+
+	// 1. 商品须来自同一供应商，同一报价不能重复
+	items := make([]model.OrdStandingOrderItem, 0, len(req.Items))
+	seen := make(map[uint]bool, len(req.Items))
+	for _, item := range req.Items {
+		if seen[item.QuoteID] {
+			return nil, fmt.Errorf("报价 #%d 重复", item.QuoteID)
+		}
+		seen[item.QuoteID] = true
+		quote, err := s.quoteRepo.GetByID(item.QuoteID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("报价 #%d 不存在", item.QuoteID)
+			}
+			return nil, err
+		}
+		if quote.SupplierID != req.SupplierID {
+			return nil, fmt.Errorf("报价 #%d 不属于所选供应商", item.QuoteID)
+		}
+		items = append(items, model.OrdStandingOrderItem{QuoteID: item.QuoteID, Quantity: item.Quantity})
+	}
+
+	// 2. 送达日期范围，默认从最早可自动下单的日期开始
+	start := startOfDay(time.Now()).AddDate(0, 0, leadDays)
+	if req.StartDate != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.StartDate, time.Local)
+		if err != nil {
+			return nil, errors.New("无效的开始日期")
+		}
+		start = date
+	}
+	var end *time.Time
+	if req.EndDate != "" {
+		date, err := time.ParseInLocation("2006-01-02", req.EndDate, time.Local)
+		if err != nil {
+			return nil, errors.New("无效的结束日期")
+		}
+		if date.Before(start) {
+			return nil, errors.New("结束日期不能早于开始日期")
+		}
+		end = &date
+	}
+
+	dates, err := json.Marshal(uniqueDates(req.Dates))
+	if err != nil {
+		return nil, err
+	}
+	weekdays := make([]int, 0, len(req.Weekdays))
+	seenDay := make(map[int]bool, len(req.Weekdays))
+	for _, d := range req.Weekdays {
+		if !seenDay[d] {
+			seenDay[d] = true
+			weekdays = append(weekdays, d)
+		}
+	}
+	sort.Ints(weekdays)
+	parts := make([]string, 0, len(weekdays))
+	for _, d := range weekdays {
+		parts = append(parts, strconv.Itoa(d))
+	}
+
+	standing.Name = req.Name
+	standing.SupplierID = req.SupplierID
+	standing.Weekdays = strings.Join(parts, ",")
+	standing.Dates = string(dates)
+	standing.LeadDays = leadDays
+	standing.StartDate = start
+	standing.EndDate = end
+	return items, nil
+}
+
+// setStatus 暂停或恢复模板
+func (s *standingOrderService) setStatus(id uint, status int8, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error) {
+	standing, err := s.getOwn(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if standing.Status == status {
+		return s.withLines(standing)
+	}
+	standing.Status = status
+	if err := s.standingRepo.UpdateFields(standing.ID, map[string]interface{}{"status": status}); err != nil {
+		return nil, fmt.Errorf("更新定期订单失败: %w", err)
+	}
+	return s.withLines(standing)
+}
+
+// withLines 为模板填充商品及其当前可下单状态
+func (s *standingOrderService) withLines(standing *model.OrdStandingOrder) (*model.OrdStandingOrder, error) {
+	rows, err := s.standingRepo.ListLines(standing.ID)
+	if err != nil {
+		return nil, err
+	}
+	schoolID, err := schoolIDOf(s.orgRepo, standing.MerchantID)
+	if err != nil {
+		return nil, err
+	}
+	policies := make(map[uint]*model.ReportPolicy)
+	now := time.Now()
+	standing.Items = make([]model.CartLine, 0, len(rows))
+	for _, row := range rows {
+		policy, err := lineReportPolicy(s.categoryService, policies, row)
+		if err != nil {
+			return nil, err
+		}
+		standing.Items = append(standing.Items, buildCartLine(row, schoolID, policy, now))
+	}
+	return standing, nil
+}
+
+// getOwn 获取当前买家自己的模板
+func (s *standingOrderService) getOwn(id uint, claims *jwt.CustomClaims) (*model.OrdStandingOrder, error) {
+	if !isBuyerRole(claims.Role) {
+		return nil, errors.New("仅食堂和商户可以使用定期订单")
+	}
+	standing, err := s.standingRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("定期订单不存在")
+		}
+		return nil, err
+	}
+	if standing.MerchantID != claims.OrgID {
+		return nil, errors.New("无权操作其他单位的定期订单")
+	}
+	return standing, nil
+}
+
+// standingScheduled 判断模板在某送达日期是否需要送货，不考虑跳过日期
+func standingScheduled(standing *model.OrdStandingOrder, date time.Time) bool {
+	if date.Before(startOfDay(standing.StartDate)) {
+		return false
+	}
+	if standing.EndDate != nil && date.After(startOfDay(*standing.EndDate)) {
+		return false
+	}
+	weekday := strconv.Itoa(int(date.Weekday()))
+	for _, d := range strings.Split(standing.Weekdays, ",") {
+		if d == weekday {
+			return true
+		}
+	}
+	return containsDate(parseDates(standing.Dates), date.Format("2006-01-02"))
+}
+
+// parseDates 解析以 JSON 数组保存的日期列表，格式错误时视为空
+func parseDates(raw string) []string {
+	var dates []string
+	if raw == "" {
+		return dates
+	}
+	if err := json.Unmarshal([]byte(raw), &dates); err != nil {
+		log.Printf("解析日期列表失败: %v", err)
+	}
+	return dates
+}
+
+// containsDate 判断日期列表中是否包含某日期
+func containsDate(dates []string, day string) bool {
+	for _, d := range dates {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueDates 去重并按先后排序日期列表
+func uniqueDates(dates []string) []string {
+	result := uniqueKeys(dates)
+	sort.Strings(result)
+	return result
+}
+
+// transientMySQLErrors 可重试的 MySQL 错误码：锁等待超时、死锁、连接断开
+var transientMySQLErrors = map[uint16]bool{
+	1205: true, // ER_LOCK_WAIT_TIMEOUT
+	1213: true, // ER_LOCK_DEADLOCK
+	2006: true, // CR_SERVER_GONE_ERROR
+	2013: true, // CR_SERVER_LOST
+}
+
+// isTransientError 判断错误是否为可重试的数据库错误，业务校验错误和其他数据库错误重试也无法成功
+func isTransientError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return transientMySQLErrors[mysqlErr.Number]
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &netErr)
+}
@@ -0,0 +1,37 @@
+// server/internal/service/standing_order_service_test.go
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"死锁", &mysql.MySQLError{Number: 1213}, true},
+		{"锁等待超时", fmt.Errorf("结算失败: %w", &mysql.MySQLError{Number: 1205}), true},
+		{"连接失效", driver.ErrBadConn, true},
+		{"连接中断", fmt.Errorf("读取模板商品失败: %w", mysql.ErrInvalidConn), true},
+		{"超时", context.DeadlineExceeded, true},
+		{"唯一索引冲突", &mysql.MySQLError{Number: 1062}, false},
+		{"记录不存在", gorm.ErrRecordNotFound, false},
+		{"业务校验", errors.New("商户未归属任何学校"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientError(tt.err); got != tt.want {
+				t.Errorf("isTransientError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
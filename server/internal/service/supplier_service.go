@@ -5,6 +5,8 @@ import (
 	"server/internal/model"
 	"server/internal/repository"
 	"server/pkg/password"
+
+	"gorm.io/gorm"
 )
 
 // CreateSupplierRequest 定义了创建供应商及其管理员的请求结构
@@ -100,38 +102,27 @@ func (s *supplierService) CreateSupplierWithAdmin(req *CreateSupplierRequest, cr
 		CreatedBy: creatorID,
 	}
 
-	// 使用事务确保原子性
-	tx := s.orgRepo.GetDB().Begin()
-	if tx.Error != nil {
-		return nil, nil, errors.New("开启事务失败")
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	// 使用事务确保原子性（当 orgRepo 已绑定外层事务时，GORM 会以保存点的方式嵌套执行）
+	err = s.orgRepo.GetDB().Transaction(func(tx *gorm.DB) error {
+		// 1. 创建组织
+		if err := tx.Create(org).Error; err != nil {
+			return errors.New("创建供应商组织失败: " + err.Error())
 		}
-	}()
-
-	// 1. 创建组织
-	if err := tx.Create(org).Error; err != nil {
-		tx.Rollback()
-		return nil, nil, errors.New("创建供应商组织失败: " + err.Error())
-	}
 
-	// 2. 关联组织并创建用户
-	user.OrgID = org.ID
-	if err := tx.Create(user).Error; err != nil {
-		tx.Rollback()
-		return nil, nil, errors.New("创建供应商管理员失败: " + err.Error())
-	}
-
-	// 3. 更新组织信息中的主管理员ID
-	if err := tx.Model(org).Update("admin_user_id", user.ID).Error; err != nil {
-		tx.Rollback()
-		return nil, nil, errors.New("更新供应商主管理员ID失败: " + err.Error())
-	}
+		// 2. 关联组织并创建用户
+		user.OrgID = org.ID
+		if err := tx.Create(user).Error; err != nil {
+			return errors.New("创建供应商管理员失败: " + err.Error())
+		}
 
-	if err := tx.Commit().Error; err != nil {
-		return nil, nil, errors.New("提交事务失败: " + err.Error())
+		// 3. 更新组织信息中的主管理员ID
+		if err := tx.Model(org).Update("admin_user_id", user.ID).Error; err != nil {
+			return errors.New("更新供应商主管理员ID失败: " + err.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
 	return org, user, nil
@@ -139,8 +130,8 @@ func (s *supplierService) CreateSupplierWithAdmin(req *CreateSupplierRequest, cr
 
 // ListSuppliers 列出属于某个学校的所有供应商
 func (s *supplierService) ListSuppliers(page, pageSize int, schoolID uint) ([]model.SysOrganization, int64, error) {
-	// 供应商的 OrgType 为 3，ParentID 是其所属的学校ID
-	return s.orgRepo.List(page, pageSize, []int8{3}, &schoolID)
+	// 供应商的 OrgType 为 2（与 CreateSupplierWithAdmin 保持一致），ParentID 是其所属的学校ID
+	return s.orgRepo.List(page, pageSize, []int8{int8(model.OrgTypeSupplier)}, &schoolID)
 }
 
 // GetSupplierByID 根据ID获取供应商及其管理员信息
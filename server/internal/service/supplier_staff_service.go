@@ -0,0 +1,198 @@
+// server/internal/service/supplier_staff_service.go
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"server/internal/model"
+	"server/internal/repository"
+	"server/pkg/jwt"
+
+	"gorm.io/gorm"
+)
+
+// ISupplierStaffService 定义供应商员工（司机、分拣员）服务接口
+type ISupplierStaffService interface {
+	CreateStaff(req *model.SupplierStaffRequest, claims *jwt.CustomClaims) (*model.ScmSupplierStaff, error)
+	UpdateStaff(id uint, req *model.SupplierStaffRequest, claims *jwt.CustomClaims) (*model.ScmSupplierStaff, error)
+	SetStaffEnabled(id uint, enabled bool, claims *jwt.CustomClaims) error
+	DeleteStaff(id uint, claims *jwt.CustomClaims) error
+	// ListStaff 分页列出本供应商的员工，附带健康证状态
+	ListStaff(filter model.SupplierStaffFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.SupplierStaffItem, int64, error)
+	// ExpiryWarnings 列出健康证已过期、即将过期或未登记有效期的员工
+	ExpiryWarnings(claims *jwt.CustomClaims, page, pageSize int) ([]model.SupplierStaffItem, int64, error)
+	// EnsureAssignable 校验员工可被指派为指定岗位：属于该供应商、已启用、岗位匹配且健康证在有效期内
+	EnsureAssignable(supplierID, staffID uint, roleType int8) (*model.ScmSupplierStaff, error)
+}
+
+// supplierStaffService 实现了 ISupplierStaffService 接口
+type supplierStaffService struct {
+	staffRepo repository.ISupplierStaffRepository
+}
+
+// NewSupplierStaffService 创建一个新的 supplierStaffService 实例
+func NewSupplierStaffService(staffRepo repository.ISupplierStaffRepository) ISupplierStaffService {
+	return &supplierStaffService{staffRepo: staffRepo}
+}
+
+// CreateStaff 为当前供应商新增员工
+func (s *supplierStaffService) CreateStaff(req *model.SupplierStaffRequest, claims *jwt.CustomClaims) (*model.ScmSupplierStaff, error) {
+	staff := &model.ScmSupplierStaff{SupplierID: claims.OrgID, IsEnabled: true}
+	if err := fillStaff(staff, req); err != nil {
+		return nil, err
+	}
+	if err := s.staffRepo.Create(staff); err != nil {
+		return nil, fmt.Errorf("新增员工失败: %w", err)
+	}
+	return staff, nil
+}
+
+// UpdateStaff 修改员工信息
+func (s *supplierStaffService) UpdateStaff(id uint, req *model.SupplierStaffRequest, claims *jwt.CustomClaims) (*model.ScmSupplierStaff, error) {
+	staff, err := s.getOwnStaff(id, claims)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillStaff(staff, req); err != nil {
+		return nil, err
+	}
+	if err := s.staffRepo.Update(staff); err != nil {
+		return nil, fmt.Errorf("更新员工失败: %w", err)
+	}
+	return staff, nil
+}
+
+// SetStaffEnabled 启用或停用员工，停用后不能再被指派
+func (s *supplierStaffService) SetStaffEnabled(id uint, enabled bool, claims *jwt.CustomClaims) error {
+	staff, err := s.getOwnStaff(id, claims)
+	if err != nil {
+		return err
+	}
+	staff.IsEnabled = enabled
+	return s.staffRepo.Update(staff)
+}
+
+// DeleteStaff 删除员工。已参与分拣或配送的员工仍被历史单据和溯源记录引用，只能停用
+func (s *supplierStaffService) DeleteStaff(id uint, claims *jwt.CustomClaims) error {
+	if _, err := s.getOwnStaff(id, claims); err != nil {
+		return err
+	}
+	referenced, err := s.staffRepo.IsReferenced(id)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return errors.New("员工已参与分拣或配送，不能删除，请改为停用")
+	}
+	return s.staffRepo.Delete(id)
+}
+
+// ListStaff 分页列出本供应商的员工
+func (s *supplierStaffService) ListStaff(filter model.SupplierStaffFilter, claims *jwt.CustomClaims, page, pageSize int) ([]model.SupplierStaffItem, int64, error) {
+	filter.SupplierID = claims.OrgID
+	staffs, total, err := s.staffRepo.List(filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	return toStaffItems(staffs, time.Now()), total, nil
+}
+
+// ExpiryWarnings 列出健康证需要关注的员工，包括未来 HealthCertWarnDays 天内到期的
+func (s *supplierStaffService) ExpiryWarnings(claims *jwt.CustomClaims, page, pageSize int) ([]model.SupplierStaffItem, int64, error) {
+	deadline := startOfDay(time.Now()).AddDate(0, 0, model.HealthCertWarnDays+1)
+	filter := model.SupplierStaffFilter{ExpireBy: &deadline}
+	return s.ListStaff(filter, claims, page, pageSize)
+}
+
+// EnsureAssignable 校验员工可被指派到分拣或配送任务
+func (s *supplierStaffService) EnsureAssignable(supplierID, staffID uint, roleType int8) (*model.ScmSupplierStaff, error) {
+	staff, err := s.staffRepo.GetByID(staffID)
+	if err != nil || staff.SupplierID != supplierID {
+		return nil, errors.New("员工不存在")
+	}
+	if !staff.IsEnabled {
+		return nil, fmt.Errorf("员工 [%s] 已停用", staff.Name)
+	}
+	if staff.RoleType != roleType {
+		return nil, fmt.Errorf("员工 [%s] 的岗位不符", staff.Name)
+	}
+	switch healthCertStatus(staff, time.Now()) {
+	case model.HealthCertMissing:
+		return nil, fmt.Errorf("员工 [%s] 未登记健康证有效期，不能指派", staff.Name)
+	case model.HealthCertExpired:
+		return nil, fmt.Errorf("员工 [%s] 的健康证已过期，不能指派", staff.Name)
+	}
+	return staff, nil
+}
+
+// getOwnStaff 获取当前供应商的员工，不属于本供应商时视为不存在
+func (s *supplierStaffService) getOwnStaff(id uint, claims *jwt.CustomClaims) (*model.ScmSupplierStaff, error) {
+	staff, err := s.staffRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("员工不存在")
+		}
+		return nil, err
+	}
+	if staff.SupplierID != claims.OrgID {
+		return nil, errors.New("员工不存在")
+	}
+	return staff, nil
+}
+
+// fillStaff 校验请求并填充员工字段
+func fillStaff(staff *model.ScmSupplierStaff, req *model.SupplierStaffRequest) error {
+	expiry, err := time.ParseInLocation("2006-01-02", req.HealthCertExpiry, time.Local)
+	if err != nil {
+		return errors.New("健康证有效期格式错误")
+	}
+	staff.Name = req.Name
+	staff.Mobile = req.Mobile
+	staff.RoleType = req.RoleType
+	staff.HealthCert = req.HealthCert
+	staff.HealthCertNo = req.HealthCertNo
+	staff.HealthCertExpiry = &expiry
+	return nil
+}
+
+// toStaffItems 为员工附加健康证状态和剩余天数
+func toStaffItems(staffs []model.ScmSupplierStaff, now time.Time) []model.SupplierStaffItem {
+	today := startOfDay(now)
+	items := make([]model.SupplierStaffItem, len(staffs))
+	for i := range staffs {
+		items[i] = model.SupplierStaffItem{
+			ScmSupplierStaff: staffs[i],
+			CertStatus:       healthCertStatus(&staffs[i], now),
+		}
+		if expiry := staffs[i].HealthCertExpiry; expiry != nil {
+			days := int(startOfDay(*expiry).Sub(today).Hours() / 24)
+			items[i].DaysLeft = &days
+		}
+	}
+	return items
+}
+
+// healthCertStatus 计算健康证状态，有效期当天仍视为有效
+func healthCertStatus(staff *model.ScmSupplierStaff, now time.Time) int8 {
+	if staff.HealthCertExpiry == nil {
+		return model.HealthCertMissing
+	}
+	today := startOfDay(now)
+	expiry := startOfDay(*staff.HealthCertExpiry)
+	switch {
+	case expiry.Before(today):
+		return model.HealthCertExpired
+	case expiry.Before(today.AddDate(0, 0, model.HealthCertWarnDays+1)):
+		return model.HealthCertExpiring
+	default:
+		return model.HealthCertValid
+	}
+}
+
+// startOfDay 返回本地时间当天零点
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
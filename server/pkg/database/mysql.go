@@ -8,6 +8,7 @@ import (
 
 	"server/internal/config"
 	"server/internal/model" // 导入所有模型的包
+	"server/internal/repository"
 	"server/pkg/password"
 
 	"gorm.io/driver/mysql"
@@ -58,18 +59,36 @@ func Migrate() error {
 		&model.SysDictionary{},
 		&model.SysOpLog{},
 		&model.SysBanner{},
+		&model.SysExportJob{},
+		&model.SysFile{},
+		&model.SysFileUpload{},
+		&model.SysFileReference{},
+		&model.SysNotification{},
 
 		// SCM models
 		&model.ScmCategory{},
 		&model.ScmProduct{},
+		&model.ScmMasterProduct{},
+		&model.ScmProductAudit{},
 		&model.ScmProductQuote{},
+		&model.ScmQuotePriceHistory{},
+		&model.ScmGuidePrice{},
 		&model.ScmSupplierStaff{},
 
 		// Order models
 		&model.OrdCart{},
 		&model.OrdOrder{},
+		&model.OrdCheckout{},
+		&model.OrdOrderLog{},
+		&model.OrdPickList{},
+		&model.OrdPickLine{},
+		&model.OrdDeliveryRun{},
+		&model.OrdStandingOrder{},
+		&model.OrdStandingOrderItem{},
+		&model.OrdStandingOrderRun{},
 		&model.OrdOrderItem{},
 		&model.OrdAfterSale{},
+		&model.OrdPriceAdjustment{},
 		&model.OrdItemTrace{},
 
 		// Finance models
@@ -88,6 +107,12 @@ func Migrate() error {
 	if err := seedUsers(DB); err != nil {
 		return fmt.Errorf("初始用户填充失败: %w", err)
 	}
+	if err := seedDictionaries(DB); err != nil {
+		return fmt.Errorf("数据字典填充失败: %w", err)
+	}
+	if err := seedFileRecords(DB); err != nil {
+		return fmt.Errorf("文件记录填充失败: %w", err)
+	}
 
 	return nil
 }
@@ -101,6 +126,10 @@ func seedRoles(db *gorm.DB) error {
 		{RoleName: "学校员工", RoleKey: "school_staff", CanCreateUsers: false},
 		{RoleName: "供应商管理员", RoleKey: "supplier_admin", CanCreateUsers: true},
 		{RoleName: "供应商员工", RoleKey: "supplier_staff", CanCreateUsers: false},
+		{RoleName: "食堂管理员", RoleKey: "canteen_admin", CanCreateUsers: true},
+		{RoleName: "食堂员工", RoleKey: "canteen_staff", CanCreateUsers: false},
+		{RoleName: "商户管理员", RoleKey: "merchant_admin", CanCreateUsers: true},
+		{RoleName: "商户员工", RoleKey: "merchant_staff", CanCreateUsers: false},
 	}
 
 	fmt.Println("正在填充初始角色数据...")
@@ -156,3 +185,42 @@ func seedUsers(db *gorm.DB) error {
 
 	return nil
 }
+
+// seedDictionaries 填充系统内置的数据字典，已存在的字典项不会被覆盖
+func seedDictionaries(db *gorm.DB) error {
+	items := []model.SysDictionary{
+		{DictCode: model.DictEnabledStatus, ItemLabel: "启用", ItemValue: "true", Sort: 1},
+		{DictCode: model.DictEnabledStatus, ItemLabel: "禁用", ItemValue: "false", Sort: 2},
+		{DictCode: model.DictUserStatus, ItemLabel: "正常", ItemValue: "1", Sort: 1},
+		{DictCode: model.DictUserStatus, ItemLabel: "锁定", ItemValue: "2", Sort: 2},
+		{DictCode: model.DictOrderStatus, ItemLabel: "待接", ItemValue: "10", Sort: 1},
+		{DictCode: model.DictOrderStatus, ItemLabel: "已接单", ItemValue: "20", Sort: 2},
+		{DictCode: model.DictOrderStatus, ItemLabel: "分拣中", ItemValue: "25", Sort: 3},
+		{DictCode: model.DictOrderStatus, ItemLabel: "配送", ItemValue: "30", Sort: 4},
+		{DictCode: model.DictOrderStatus, ItemLabel: "已送达", ItemValue: "35", Sort: 5},
+		{DictCode: model.DictOrderStatus, ItemLabel: "已收货", ItemValue: "38", Sort: 6},
+		{DictCode: model.DictOrderStatus, ItemLabel: "完成", ItemValue: "40", Sort: 7},
+		{DictCode: model.DictOrderStatus, ItemLabel: "已拒单", ItemValue: "50", Sort: 8},
+		{DictCode: model.DictOrderStatus, ItemLabel: "已取消", ItemValue: "60", Sort: 9},
+	}
+
+	fmt.Println("正在填充数据字典...")
+	for _, item := range items {
+		result := db.Where(model.SysDictionary{DictCode: item.DictCode, ItemValue: item.ItemValue}).FirstOrCreate(&item)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	fmt.Println("✅ 数据字典填充成功！")
+	return nil
+}
+
+// seedFileRecords 为引入上传记录和引用表之前的文件补登记，避免已被使用的文件被当作孤儿文件清理
+func seedFileRecords(db *gorm.DB) error {
+	fileRepo := repository.NewFileRepository(db)
+	if err := fileRepo.BackfillUploads(); err != nil {
+		return err
+	}
+	return fileRepo.BackfillReferences()
+}
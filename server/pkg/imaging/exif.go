@@ -0,0 +1,128 @@
+// server/pkg/imaging/exif.go
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// EXIF 标签
+const (
+	tagOrientation      = 0x0112
+	tagDateTime         = 0x0132
+	tagExifIFD          = 0x8769
+	tagDateTimeOriginal = 0x9003
+)
+
+// exifInfo 是从 JPEG 的 EXIF 中读取的信息
+type exifInfo struct {
+	Orientation int        // 1-8，未记录时为 1
+	CapturedAt  *time.Time // 拍摄时间，优先取 DateTimeOriginal
+}
+
+// readJPEGExif 从 JPEG 数据中读取方向和拍摄时间，没有 EXIF 或格式异常时返回默认值
+func readJPEGExif(data []byte) exifInfo {
+	info := exifInfo{Orientation: 1}
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return info
+	}
+
+	// 遍历 JPEG 段，找到 APP1 中的 EXIF 数据
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return info
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // 图像数据开始或结束
+			return info
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return info
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			parseTIFF(segment[6:], &info)
+			return info
+		}
+		i += 2 + length
+	}
+	return info
+}
+
+// parseTIFF 解析 EXIF 中的 TIFF 结构
+func parseTIFF(tiff []byte, info *exifInfo) {
+	if len(tiff) < 8 {
+		return
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return
+	}
+
+	var dateTime, original string
+	var exifOffset uint32
+	readIFD(tiff, order, order.Uint32(tiff[4:8]), func(tag, typ uint16, count, value uint32, raw []byte) {
+		switch tag {
+		case tagOrientation:
+			if typ == 3 { // SHORT
+				if o := int(order.Uint16(raw)); o >= 1 && o <= 8 {
+					info.Orientation = o
+				}
+			}
+		case tagDateTime:
+			dateTime = readASCII(tiff, count, value, raw)
+		case tagExifIFD:
+			exifOffset = value
+		}
+	})
+	if exifOffset != 0 {
+		readIFD(tiff, order, exifOffset, func(tag, typ uint16, count, value uint32, raw []byte) {
+			if tag == tagDateTimeOriginal {
+				original = readASCII(tiff, count, value, raw)
+			}
+		})
+	}
+
+	for _, s := range []string{original, dateTime} {
+		if t, err := time.ParseInLocation("2006:01:02 15:04:05", s, time.Local); err == nil {
+			info.CapturedAt = &t
+			return
+		}
+	}
+}
+
+// readIFD 遍历一个 IFD 的所有条目，raw 为条目中 4 字节的值/偏移字段
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32, fn func(tag, typ uint16, count, value uint32, raw []byte)) {
+	if int(offset)+2 > len(tiff) {
+		return
+	}
+	n := int(order.Uint16(tiff[offset:]))
+	start := int(offset) + 2
+	for i := 0; i < n; i++ {
+		entry := start + i*12
+		if entry+12 > len(tiff) {
+			return
+		}
+		raw := tiff[entry+8 : entry+12]
+		fn(order.Uint16(tiff[entry:]), order.Uint16(tiff[entry+2:]), order.Uint32(tiff[entry+4:]), order.Uint32(raw), raw)
+	}
+}
+
+// readASCII 读取 ASCII 类型的值，长度不超过 4 字节时值直接保存在条目中
+func readASCII(tiff []byte, count, offset uint32, raw []byte) string {
+	var b []byte
+	if count <= 4 {
+		b = raw[:count]
+	} else if uint64(offset)+uint64(count) <= uint64(len(tiff)) {
+		b = tiff[offset : offset+count]
+	}
+	return strings.TrimRight(string(b), "\x00 ")
+}
@@ -0,0 +1,179 @@
+// server/pkg/imaging/imaging.go
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // 注册 GIF 解码器，用于识别尺寸
+	"image/jpeg"
+	"image/png"
+	"time"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册 WebP 解码器
+)
+
+// maxPixels 允许处理的最大像素数，防止解压炸弹耗尽内存。
+// 解码和旋正时会同时持有多份 RGBA 像素（每像素 4 字节），上限取 4096×4096，足以覆盖常见手机照片
+const maxPixels = 4096 * 4096
+
+// Variant 描述一种缩放规格，图片长边缩放到 MaxSide，不放大小图
+type Variant struct {
+	Name    string
+	MaxSide int
+	Quality int // JPEG 编码质量
+}
+
+// 预设的缩放规格
+var (
+	Thumb  = Variant{Name: "thumb", MaxSide: 240, Quality: 75}
+	Medium = Variant{Name: "medium", MaxSide: 960, Quality: 82}
+)
+
+// Result 是图片处理的结果
+type Result struct {
+	Original    []byte     // 规范化后的原图，已去除元数据
+	Width       int        // 规范化后的宽度
+	Height      int        // 规范化后的高度
+	CapturedAt  *time.Time // EXIF 中的拍摄时间
+	Variants    map[string][]byte
+	VariantExt  string // 缩略图的扩展名：PNG 原图保持 PNG 以保留透明度，其余为 JPEG
+	VariantMIME string
+}
+
+// Processable 判断该类型的图片是否需要处理；GIF 可能是动图，保持原样
+func Processable(mimeType string) bool {
+	switch mimeType {
+	case "image/jpeg", "image/png", "image/webp":
+		return true
+	}
+	return false
+}
+
+// Process 规范化图片并生成缩略图。
+// JPEG 会按 EXIF 方向旋正后重新编码，从而去掉 EXIF（包括 GPS 位置）；
+// PNG 和 WebP 删除 EXIF、XMP 等元数据块，像素数据保持不变。
+func Process(data []byte, mimeType string, variants ...Variant) (*Result, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("无法识别的图片: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, errors.New("图片尺寸过大")
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("图片解码失败: %w", err)
+	}
+
+	result := &Result{Variants: make(map[string][]byte, len(variants)), VariantExt: ".jpg", VariantMIME: "image/jpeg"}
+	if mimeType == "image/jpeg" {
+		info := readJPEGExif(data)
+		result.CapturedAt = info.CapturedAt
+		img = orient(img, info.Orientation)
+		if result.Original, err = encodeJPEG(img, 92); err != nil {
+			return nil, err
+		}
+	}
+	switch mimeType {
+	case "image/png":
+		result.VariantExt, result.VariantMIME = ".png", "image/png"
+		if result.Original, err = stripPNGMetadata(data); err != nil {
+			return nil, err
+		}
+	case "image/webp":
+		if result.Original, err = stripWebPMetadata(data); err != nil {
+			return nil, err
+		}
+	}
+	bounds := img.Bounds()
+	result.Width, result.Height = bounds.Dx(), bounds.Dy()
+
+	for _, v := range variants {
+		scaled := resize(img, v.MaxSide)
+		var out []byte
+		if result.VariantMIME == "image/png" {
+			var buf bytes.Buffer
+			err = png.Encode(&buf, scaled)
+			out = buf.Bytes()
+		} else {
+			out, err = encodeJPEG(scaled, v.Quality)
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Variants[v.Name] = out
+	}
+	return result, nil
+}
+
+// resize 等比缩放到长边不超过 maxSide
+func resize(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+	if w >= h {
+		h = max(1, h*maxSide/w)
+		w = maxSide
+	} else {
+		w = max(1, w*maxSide/h)
+		h = maxSide
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+	return dst
+}
+
+// orient 按 EXIF 方向值旋转/翻转图片，使其以正确方向显示
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	src := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	dw, dh := w, h
+	if orientation >= 5 { // 5-8 需要交换宽高
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // 水平翻转
+				dx, dy = w-1-x, y
+			case 3: // 旋转 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // 垂直翻转
+				dx, dy = x, h-1-y
+			case 5: // 沿左上-右下对角线翻转
+				dx, dy = y, x
+			case 6: // 顺时针旋转 90°
+				dx, dy = h-1-y, x
+			case 7: // 沿右上-左下对角线翻转
+				dx, dy = h-1-y, w-1-x
+			case 8: // 逆时针旋转 90°
+				dx, dy = y, w-1-x
+			}
+			si, di := src.PixOffset(x, y), dst.PixOffset(dx, dy)
+			copy(dst.Pix[di:di+4], src.Pix[si:si+4])
+		}
+	}
+	return dst
+}
+
+// encodeJPEG 将图片编码为 JPEG，编码结果不包含任何 EXIF
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,78 @@
+// server/pkg/imaging/imaging_test.go
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// gridImage 按行生成图片，每个像素的红色分量为格子中的值
+func gridImage(rows [][]uint8) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x, v := range row {
+			img.Set(x, y, color.RGBA{R: v, A: 255})
+		}
+	}
+	return img
+}
+
+// gridOf 读出图片每个像素的红色分量
+func gridOf(img image.Image) [][]uint8 {
+	b := img.Bounds()
+	rows := make([][]uint8, b.Dy())
+	for y := range rows {
+		rows[y] = make([]uint8, b.Dx())
+		for x := range rows[y] {
+			r, _, _, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			rows[y][x] = uint8(r >> 8)
+		}
+	}
+	return rows
+}
+
+func TestOrient(t *testing.T) {
+	src := [][]uint8{
+		{1, 2},
+		{3, 4},
+		{5, 6},
+	}
+	tests := []struct {
+		orientation int
+		want        [][]uint8
+	}{
+		{0, src},
+		{1, src},
+		{2, [][]uint8{{2, 1}, {4, 3}, {6, 5}}},
+		{3, [][]uint8{{6, 5}, {4, 3}, {2, 1}}},
+		{4, [][]uint8{{5, 6}, {3, 4}, {1, 2}}},
+		{5, [][]uint8{{1, 3, 5}, {2, 4, 6}}},
+		{6, [][]uint8{{5, 3, 1}, {6, 4, 2}}},
+		{7, [][]uint8{{6, 4, 2}, {5, 3, 1}}},
+		{8, [][]uint8{{2, 4, 6}, {1, 3, 5}}},
+		{9, src},
+	}
+	for _, tt := range tests {
+		got := gridOf(orient(gridImage(src), tt.orientation))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("orient(%d) = %v, want %v", tt.orientation, got, tt.want)
+		}
+	}
+}
+
+func TestOrientOffsetBounds(t *testing.T) {
+	// 解码后的子图起点可能不是 (0, 0)
+	full := gridImage([][]uint8{
+		{9, 9, 9},
+		{9, 1, 2},
+		{9, 3, 4},
+	}).(*image.RGBA)
+	sub := full.SubImage(image.Rect(1, 1, 3, 3))
+	got := gridOf(orient(sub, 6))
+	want := [][]uint8{{3, 1}, {4, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("orient(sub, 6) = %v, want %v", got, want)
+	}
+}
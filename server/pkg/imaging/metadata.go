@@ -0,0 +1,89 @@
+// server/pkg/imaging/metadata.go
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// pngSignature PNG 文件头
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngMetadataChunks 可能包含拍摄信息或位置的 PNG 辅助块
+var pngMetadataChunks = map[string]bool{
+	"eXIf": true,
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"tIME": true,
+}
+
+// VP8X 扩展头中 EXIF 和 XMP 的标志位
+const (
+	webpFlagEXIF = 0x08
+	webpFlagXMP  = 0x04
+)
+
+// stripPNGMetadata 删除 PNG 中的 EXIF 和文本块，其余块原样保留，不重新编码像素
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("无效的 PNG 文件")
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	for i := len(pngSignature); i < len(data); {
+		// 块结构：长度(4) + 类型(4) + 数据 + CRC(4)
+		if i+8 > len(data) {
+			return nil, errors.New("PNG 数据不完整")
+		}
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, errors.New("PNG 数据不完整")
+		}
+		chunkType := string(data[i+4 : i+8])
+		if !pngMetadataChunks[chunkType] {
+			out = append(out, data[i:end]...)
+		}
+		i = end
+		if chunkType == "IEND" {
+			break
+		}
+	}
+	return out, nil
+}
+
+// stripWebPMetadata 删除 WebP 中的 EXIF 和 XMP 块并清除扩展头中的对应标志，不重新编码像素
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errors.New("无效的 WebP 文件")
+	}
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	for i := 12; i < len(data); {
+		// 块结构：类型(4) + 长度(4) + 数据，数据长度为奇数时补一个字节
+		if i+8 > len(data) {
+			return nil, errors.New("WebP 数据不完整")
+		}
+		size := int(binary.LittleEndian.Uint32(data[i+4 : i+8]))
+		end := i + 8 + size + size%2
+		if size < 0 || end > len(data) {
+			return nil, errors.New("WebP 数据不完整")
+		}
+		switch string(data[i : i+4]) {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			start := len(out)
+			out = append(out, data[i:end]...)
+			if size > 0 {
+				out[start+8] &^= webpFlagEXIF | webpFlagXMP
+			}
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out, nil
+}
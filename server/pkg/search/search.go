@@ -0,0 +1,311 @@
+// server/pkg/search/search.go
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// maxPinyinPrefix 拼音前缀索引的最大长度，超过该长度的输入只按前缀截断匹配
+const maxPinyinPrefix = 24
+
+// Hit 表示一条搜索结果
+type Hit struct {
+	ID    uint
+	Score float64
+}
+
+// document 保存文档归一化后的文本，用于打分
+type document struct {
+	text     string   // 小写后的原文
+	full     string   // 全拼，如 "tudou"
+	initials string   // 拼音首字母，如 "td"
+	tokens   []string // 写入倒排表的词项，删除文档时使用
+}
+
+// Index 是一个内存倒排索引，支持中文单字/双字切分以及拼音全拼、首字母匹配，可安全地并发使用
+type Index struct {
+	mu       sync.RWMutex
+	postings map[string]map[uint]struct{}
+	docs     map[uint]*document
+	args     pinyin.Args
+}
+
+// New 创建一个空索引
+func New() *Index {
+	return &Index{
+		postings: make(map[string]map[uint]struct{}),
+		docs:     make(map[uint]*document),
+		args:     pinyin.NewArgs(),
+	}
+}
+
+// Add 将文档加入索引，已存在时替换
+func (ix *Index) Add(id uint, text string) {
+	doc := ix.analyze(text)
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(id)
+	ix.docs[id] = doc
+	for _, token := range doc.tokens {
+		ids, ok := ix.postings[token]
+		if !ok {
+			ids = make(map[uint]struct{})
+			ix.postings[token] = ids
+		}
+		ids[id] = struct{}{}
+	}
+}
+
+// Remove 从索引中删除文档
+func (ix *Index) Remove(id uint) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.remove(id)
+}
+
+// Reset 清空索引
+func (ix *Index) Reset() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.postings = make(map[string]map[uint]struct{})
+	ix.docs = make(map[uint]*document)
+}
+
+// Len 返回索引中的文档数
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return len(ix.docs)
+}
+
+// Search 返回与查询匹配且通过 accept 过滤的文档，按相关度从高到低排列。
+// 查询中的每个片段（连续的汉字或字母数字）都必须匹配；accept 为 nil 时不过滤。
+func (ix *Index) Search(query string, accept func(id uint) bool) []Hit {
+	q := normalize(query)
+	segments := split(q)
+	if len(segments) == 0 {
+		return nil
+	}
+	compact := strings.Join(segments, "")
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	// 1. 取所有片段词项倒排表的交集作为候选
+	var candidates map[uint]struct{}
+	for _, seg := range segments {
+		for _, token := range queryTokens(seg) {
+			ids := ix.postings[token]
+			if len(ids) == 0 {
+				return nil
+			}
+			if candidates == nil {
+				candidates = make(map[uint]struct{}, len(ids))
+				for id := range ids {
+					candidates[id] = struct{}{}
+				}
+				continue
+			}
+			for id := range candidates {
+				if _, ok := ids[id]; !ok {
+					delete(candidates, id)
+				}
+			}
+		}
+	}
+
+	// 2. 校验汉字片段连续出现，并打分
+	hits := make([]Hit, 0, len(candidates))
+	for id := range candidates {
+		doc := ix.docs[id]
+		if !containsHanSegments(doc.text, segments) {
+			continue
+		}
+		if accept != nil && !accept(id) {
+			continue
+		}
+		hits = append(hits, Hit{ID: id, Score: score(doc, compact)})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	return hits
+}
+
+// remove 删除文档，调用方需持有写锁
+func (ix *Index) remove(id uint) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	for _, token := range doc.tokens {
+		if ids, ok := ix.postings[token]; ok {
+			delete(ids, id)
+			if len(ids) == 0 {
+				delete(ix.postings, token)
+			}
+		}
+	}
+	delete(ix.docs, id)
+}
+
+// analyze 对文本进行切分，生成汉字单字、双字词项，字母数字单词前缀，以及拼音全拼和首字母的前缀词项
+func (ix *Index) analyze(text string) *document {
+	doc := &document{text: normalize(text)}
+	tokens := make(map[string]struct{})
+
+	var syllables []string
+	for _, seg := range split(doc.text) {
+		runes := []rune(seg)
+		if !isHan(runes[0]) {
+			addPrefixes(tokens, seg, 0)
+			continue
+		}
+		for i, r := range runes {
+			tokens[string(r)] = struct{}{}
+			if i+1 < len(runes) {
+				tokens[string(runes[i:i+2])] = struct{}{}
+			}
+			if py := pinyin.SinglePinyin(r, ix.args); len(py) > 0 {
+				syllables = append(syllables, py[0])
+			}
+		}
+	}
+
+	// 拼音从每个音节开始都建立前缀，这样输入"dou"也能匹配"土豆"
+	var full, initials strings.Builder
+	starts := make([]int, 0, len(syllables))
+	for _, s := range syllables {
+		starts = append(starts, full.Len())
+		full.WriteString(s)
+		initials.WriteByte(s[0])
+	}
+	doc.full, doc.initials = full.String(), initials.String()
+	for _, start := range starts {
+		addPrefixes(tokens, doc.full, start)
+	}
+	for i := range doc.initials {
+		addPrefixes(tokens, doc.initials, i)
+	}
+
+	doc.tokens = make([]string, 0, len(tokens))
+	for token := range tokens {
+		doc.tokens = append(doc.tokens, token)
+	}
+	return doc
+}
+
+// queryTokens 生成查询片段需要命中的词项
+func queryTokens(seg string) []string {
+	runes := []rune(seg)
+	if !isHan(runes[0]) {
+		if len(seg) > maxPinyinPrefix {
+			seg = seg[:maxPinyinPrefix]
+		}
+		return []string{seg}
+	}
+	if len(runes) == 1 {
+		return []string{seg}
+	}
+	tokens := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		tokens = append(tokens, string(runes[i:i+2]))
+	}
+	return tokens
+}
+
+// score 计算文档与查询的相关度：完全匹配 > 前缀匹配 > 包含，名称越短越靠前
+func score(doc *document, q string) float64 {
+	s := 0.0
+	switch {
+	case doc.text == q:
+		s += 100
+	case doc.full == q || doc.initials == q:
+		s += 80
+	case strings.HasPrefix(doc.text, q), strings.HasPrefix(doc.full, q), strings.HasPrefix(doc.initials, q):
+		s += 50
+	case strings.Contains(doc.text, q), strings.Contains(doc.full, q), strings.Contains(doc.initials, q):
+		s += 20
+	}
+	return s - 0.5*float64(utf8.RuneCountInString(doc.text))
+}
+
+// containsHanSegments 校验查询中的汉字片段在原文中连续出现
+func containsHanSegments(text string, segments []string) bool {
+	for _, seg := range segments {
+		r, _ := utf8.DecodeRuneInString(seg)
+		if isHan(r) && !strings.Contains(text, seg) {
+			return false
+		}
+	}
+	return true
+}
+
+// addPrefixes 为 s[start:] 的每个前缀生成词项，长度不超过 maxPinyinPrefix
+func addPrefixes(tokens map[string]struct{}, s string, start int) {
+	end := len(s)
+	if end-start > maxPinyinPrefix {
+		end = start + maxPinyinPrefix
+	}
+	for i := start + 1; i <= end; i++ {
+		tokens[s[start:i]] = struct{}{}
+	}
+}
+
+// split 将文本切分为连续的汉字片段和字母数字片段，其余字符作为分隔符
+func split(text string) []string {
+	var segments []string
+	var cur []rune
+	curHan := false
+	flush := func() {
+		if len(cur) > 0 {
+			segments = append(segments, string(cur))
+			cur = cur[:0]
+		}
+	}
+	for _, r := range text {
+		switch {
+		case isHan(r):
+			if !curHan {
+				flush()
+			}
+			curHan = true
+			cur = append(cur, r)
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if curHan {
+				flush()
+			}
+			curHan = false
+			cur = append(cur, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return segments
+}
+
+// normalize 将文本转为小写并把全角字母数字转为半角
+func normalize(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '０' && r <= '～' {
+			r = r - '０' + '0'
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
+
+// isHan 判断字符是否为汉字
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
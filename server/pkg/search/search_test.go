@@ -0,0 +1,69 @@
+// server/pkg/search/search_test.go
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	ix := New()
+	ix.Add(1, "土豆")
+	ix.Add(2, "土豆粉")
+	ix.Add(3, "红薯")
+	ix.Add(4, "西红柿 500g")
+	ix.Add(5, "ＡＢＣ牛奶")
+
+	tests := []struct {
+		name  string
+		query string
+		want  []uint
+	}{
+		{"汉字完全匹配排在前面", "土豆", []uint{1, 2}},
+		{"单字匹配", "薯", []uint{3}},
+		{"全拼", "tudou", []uint{1, 2}},
+		{"拼音首字母", "td", []uint{1, 2}},
+		{"拼音前缀优先于词中匹配", "hongs", []uint{3, 4}},
+		{"多个片段都须匹配", "西红柿 500", []uint{4}},
+		{"汉字须连续出现", "土粉", nil},
+		{"全角转半角并忽略大小写", "abc", []uint{5}},
+		{"无匹配", "白菜", nil},
+		{"空查询", "  ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []uint
+			for _, hit := range ix.Search(tt.query, nil) {
+				got = append(got, hit.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchAcceptAndRemove(t *testing.T) {
+	ix := New()
+	ix.Add(1, "土豆")
+	ix.Add(2, "土豆粉")
+
+	hits := ix.Search("土豆", func(id uint) bool { return id != 1 })
+	if len(hits) != 1 || hits[0].ID != 2 {
+		t.Errorf("Search with accept = %v, want only 2", hits)
+	}
+
+	ix.Remove(2)
+	hits = ix.Search("土豆", nil)
+	if len(hits) != 1 || hits[0].ID != 1 {
+		t.Errorf("Search after Remove = %v, want only 1", hits)
+	}
+
+	ix.Add(1, "红薯")
+	if hits := ix.Search("土豆", nil); len(hits) != 0 {
+		t.Errorf("Search after replacing document = %v, want none", hits)
+	}
+	if ix.Len() != 1 {
+		t.Errorf("Len = %d, want 1", ix.Len())
+	}
+}
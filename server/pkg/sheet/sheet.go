@@ -0,0 +1,167 @@
+// server/pkg/sheet/sheet.go
+package sheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format 表示支持的表格文件格式
+type Format string
+
+const (
+	FormatXLSX Format = "xlsx"
+	FormatCSV  Format = "csv"
+)
+
+// utf8BOM 写入 CSV 时附带 BOM，保证 Excel 打开中文不乱码
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ParseFormat 将字符串解析为表格格式，空字符串默认为 xlsx
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "", "xlsx":
+		return FormatXLSX, nil
+	case "csv":
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("不支持的文件格式: %s", s)
+	}
+}
+
+// FormatOf 根据文件名后缀判断表格格式
+func FormatOf(filename string) (Format, error) {
+	ext := filepath.Ext(filename)
+	if ext == "" {
+		return "", errors.New("无法识别文件格式，请上传 .xlsx 或 .csv 文件")
+	}
+	return ParseFormat(ext)
+}
+
+// ContentType 返回格式对应的 MIME 类型
+func (f Format) ContentType() string {
+	if f == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+// Read 读取表格文件的第一个工作表，返回所有行（包含表头）
+func Read(r io.Reader, format Format) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+		reader.FieldsPerRecord = -1 // 允许各行列数不一致
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case FormatXLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("解析 Excel 文件失败: %w", err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("Excel 文件中没有工作表")
+		}
+		return f.GetRows(sheets[0])
+	default:
+		return nil, fmt.Errorf("不支持的文件格式: %s", format)
+	}
+}
+
+// Writer 是一个按行流式写出表格的写入器
+type Writer interface {
+	// WriteRow 写入一行数据
+	WriteRow(values []string) error
+	// Close 结束写入并将剩余内容刷新到底层 io.Writer
+	Close() error
+}
+
+// NewWriter 创建指定格式的表格写入器
+func NewWriter(w io.Writer, format Format, sheetName string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		if _, err := w.Write(utf8BOM); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, sheetName)
+	default:
+		return nil, fmt.Errorf("不支持的文件格式: %s", format)
+	}
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (cw *csvWriter) WriteRow(values []string) error {
+	return cw.w.Write(values)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, sheetName string) (*xlsxWriter, error) {
+	if sheetName == "" {
+		sheetName = "Sheet1"
+	}
+	f := excelize.NewFile()
+	// 新建文件默认带有 Sheet1，按需重命名
+	if sheetName != "Sheet1" {
+		if err := f.SetSheetName("Sheet1", sheetName); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	stream, err := f.NewStreamWriter(sheetName)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, file: f, stream: stream}, nil
+}
+
+func (xw *xlsxWriter) WriteRow(values []string) error {
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		row[i] = v
+	}
+	return xw.stream.SetRow(cell, row)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+	if err := xw.stream.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
@@ -0,0 +1,119 @@
+// server/pkg/storage/local.go
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalConfig 定义了本地磁盘存储的配置
+type LocalConfig struct {
+	Dir     string // 文件存放目录
+	BaseURL string // 下载地址前缀，签名参数附加在 key 之后
+}
+
+// Local 是将对象保存在本地磁盘上的存储后端，下载地址由应用自身校验签名后提供文件
+type Local struct {
+	dir     string
+	baseURL string
+	signer  *Signer
+}
+
+// NewLocal 创建本地磁盘存储，目录不存在时自动创建
+func NewLocal(cfg LocalConfig, signer *Signer) (*Local, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建存储目录失败: %w", err)
+	}
+	return &Local{dir: cfg.Dir, baseURL: strings.TrimRight(cfg.BaseURL, "/"), signer: signer}, nil
+}
+
+// Put 先写入临时文件再重命名，避免读取到写了一半的文件
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (l *Local) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL 返回形如 {BaseURL}/{key}?expires=...&signature=... 的下载地址
+func (l *Local) SignedURL(ctx context.Context, key string, expires time.Duration) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+	expiresAt := time.Now().Add(expires).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	query.Set("signature", l.signer.Sign(key, expiresAt))
+	return l.baseURL + "/" + key + "?" + query.Encode(), nil
+}
+
+// Verify 校验下载地址中的签名参数
+func (l *Local) Verify(key, expires, signature string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return false
+	}
+	return l.signer.Verify(key, expiresAt, signature, time.Now())
+}
+
+// Path 返回对象在磁盘上的路径，供下载接口直接发送文件
+func (l *Local) Path(key string) (string, error) {
+	return l.path(key)
+}
+
+// path 将 key 转换为存储目录下的路径，拒绝跳出存储目录的 key
+func (l *Local) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if key == "" || clean == "/" || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("非法的文件路径: %s", key)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(clean)), nil
+}